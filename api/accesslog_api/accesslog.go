@@ -0,0 +1,38 @@
+package accesslog_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/accesslog"
+	"linkstar/utils/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessLogQueryRequest struct {
+	DeviceID  uint      `form:"deviceId"`                                 // 设备ID
+	ServiceID uint      `form:"serviceId"`                                // 服务ID
+	IP        string    `form:"ip" binding:"omitempty,ip"`                // 客户端 IP
+	From      time.Time `form:"from"`                                     // 开始时间（RFC3339），默认结束时间前 24 小时
+	To        time.Time `form:"to"`                                       // 结束时间（RFC3339），默认当前时间
+	Limit     int       `form:"limit" binding:"omitempty,min=1,max=1000"` // 最多返回条数，默认 100
+}
+
+// GET /access-log 按连接关闭时间倒序
+func (AccessLogApi) AccessLogQueryView(c *gin.Context) {
+	cr := middleware.GetBindRequest[AccessLogQueryRequest](c)
+
+	list, err := accesslog.Query(accesslog.Filter{
+		DeviceID:  cr.DeviceID,
+		ServiceID: cr.ServiceID,
+		IP:        cr.IP,
+		From:      cr.From,
+		To:        cr.To,
+		Limit:     cr.Limit,
+	})
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(list, int64(len(list)), c)
+}
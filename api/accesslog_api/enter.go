@@ -0,0 +1,24 @@
+package accesslog_api
+
+import (
+	"errors"
+	"linkstar/modules/accesslog"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogApi 访问日志查询接口（v2 风格）
+type AccessLogApi struct {
+}
+
+// 将 accesslog 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, accesslog.ErrInvalidRange):
+		res.Error(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,113 @@
+package acme_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/acme"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsUpdateRequest struct {
+	acme.Settings
+}
+
+type OrderUriRequest struct {
+	ID uint `uri:"id" json:"-"` // 申请ID
+}
+
+type OrderCreateRequest struct {
+	acme.OrderSpec
+}
+
+type OrderUpdateRequest struct {
+	ID uint `uri:"id" json:"-"` // 申请ID
+	acme.OrderSpec
+}
+
+// GET /acme/settings
+func (AcmeApi) SettingsGetView(c *gin.Context) {
+	res.JSON(http.StatusOK, acme.GetSettings(), c)
+}
+
+// PUT /acme/settings
+func (AcmeApi) SettingsUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SettingsUpdateRequest](c)
+
+	s, err := acme.UpdateSettings(cr.Settings)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, s, c)
+}
+
+// GET /acme/orders
+func (AcmeApi) OrderListView(c *gin.Context) {
+	list := acme.ListOrders()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /acme/orders/:id
+func (AcmeApi) OrderGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[OrderUriRequest](c)
+
+	o, err := acme.GetOrder(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, o, c)
+}
+
+// POST /acme/orders 启用时在后台立即申请
+func (AcmeApi) OrderCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[OrderCreateRequest](c)
+
+	o, err := acme.AddOrder(cr.OrderSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(o, c)
+}
+
+// PUT /acme/orders/:id
+func (AcmeApi) OrderUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[OrderUpdateRequest](c)
+
+	o, err := acme.UpdateOrder(cr.ID, cr.OrderSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, o, c)
+}
+
+// DELETE /acme/orders/:id 已签发的证书保留在证书管理中
+func (AcmeApi) OrderDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[OrderUriRequest](c)
+
+	if err := acme.DeleteOrder(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// POST /acme/orders/:id/issue 在后台立即申请或续期，返回 202，通过 GET /acme/orders/:id 查看进度
+func (AcmeApi) OrderIssueView(c *gin.Context) {
+	cr := middleware.GetBindRequest[OrderUriRequest](c)
+
+	if err := acme.IssueOrder(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	o, err := acme.GetOrder(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusAccepted, o, c)
+}
@@ -0,0 +1,34 @@
+package acme_api
+
+import (
+	"errors"
+	"linkstar/modules/acme"
+	"linkstar/modules/ddns"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AcmeApi ACME 证书申请接口（v2 风格）
+type AcmeApi struct {
+}
+
+// 将 acme 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, acme.ErrOrderNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, acme.ErrIssuing):
+		res.Error(http.StatusConflict, res.ErrCodeInvalidState, err.Error(), c)
+	case errors.Is(err, ddns.ErrProviderNotFound):
+		errs := validate.Errors{{Field: "providerId", Rule: "exists", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	case errors.Is(err, acme.ErrInvalidConfig):
+		errs := validate.Errors{{Field: acme.InvalidField(err), Rule: "invalid", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,109 @@
+package cert_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/certs"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertUriRequest struct {
+	ID uint `uri:"id" json:"-"` // 证书ID
+}
+
+type CertUploadRequest struct {
+	Name string `json:"name" binding:"required"` // 名称
+	Cert string `json:"cert" binding:"required"` // 证书 PEM，可包含中间证书链
+	Key  string `json:"key" binding:"required"`  // 私钥 PEM
+}
+
+type CertGenerateRequest struct {
+	Name    string   `json:"name" binding:"required"`                            // 名称
+	Source  string   `json:"source" binding:"required,oneof=selfsigned localca"` // selfsigned 自签名 / localca 本地 CA 签发
+	Domains []string `json:"domains" binding:"required,min=1,dive,required"`     // 域名或 IP，第一个作为 CN
+	Days    int      `json:"days" binding:"min=0,max=3650"`                      // 有效期（天），0 为 365 天
+}
+
+type CertReplaceRequest struct {
+	ID   uint   `uri:"id" json:"-"`              // 证书ID
+	Cert string `json:"cert" binding:"required"` // 证书 PEM
+	Key  string `json:"key" binding:"required"`  // 私钥 PEM
+}
+
+// GET /certs
+func (CertApi) CertListView(c *gin.Context) {
+	list := certs.ListCerts()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /certs/:id
+func (CertApi) CertGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[CertUriRequest](c)
+
+	cert, err := certs.GetCert(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, cert, c)
+}
+
+// POST /certs 上传证书
+func (CertApi) CertUploadView(c *gin.Context) {
+	cr := middleware.GetBindRequest[CertUploadRequest](c)
+
+	cert, err := certs.UploadCert(cr.Name, []byte(cr.Cert), []byte(cr.Key))
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(cert, c)
+}
+
+// POST /certs/generate 生成自签名证书或由本地 CA 签发
+func (CertApi) CertGenerateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[CertGenerateRequest](c)
+
+	cert, err := certs.GenerateCert(cr.Name, cr.Source, cr.Domains, cr.Days)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(cert, c)
+}
+
+// PUT /certs/:id 替换证书内容，使用它的服务在下次握手时生效
+func (CertApi) CertReplaceView(c *gin.Context) {
+	cr := middleware.GetBindRequest[CertReplaceRequest](c)
+
+	cert, err := certs.ReplaceCert(cr.ID, []byte(cr.Cert), []byte(cr.Key))
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, cert, c)
+}
+
+// DELETE /certs/:id
+func (CertApi) CertDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[CertUriRequest](c)
+
+	if err := certs.DeleteCert(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// GET /certs/ca 下载本地 CA 证书（不存在时生成），导入客户端后信任本地 CA 签发的证书
+func (CertApi) CertCAView(c *gin.Context) {
+	pem, err := certs.CACertPEM()
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="linkstar-ca.crt"`)
+	c.Data(http.StatusOK, "application/x-pem-file", pem)
+}
@@ -0,0 +1,30 @@
+package cert_api
+
+import (
+	"errors"
+	"linkstar/modules/certs"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertApi 证书管理接口（v2 风格）
+type CertApi struct {
+}
+
+// 将 certs 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, certs.ErrCertNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, certs.ErrInvalidCert):
+		errs := validate.Errors{{Field: "cert", Rule: "invalid", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	case errors.Is(err, certs.ErrCertInUse):
+		res.Error(http.StatusConflict, res.ErrCodeConflict, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
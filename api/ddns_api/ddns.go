@@ -0,0 +1,154 @@
+package ddns_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/ddns"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProviderUriRequest struct {
+	ID uint `uri:"id" json:"-"` // 服务商ID
+}
+
+type ProviderCreateRequest struct {
+	ddns.ProviderSpec
+}
+
+type ProviderUpdateRequest struct {
+	ID uint `uri:"id" json:"-"` // 服务商ID
+	ddns.ProviderSpec
+}
+
+type DomainUriRequest struct {
+	ID uint `uri:"id" json:"-"` // 域名ID
+}
+
+type DomainCreateRequest struct {
+	ddns.DomainSpec
+}
+
+type DomainUpdateRequest struct {
+	ID uint `uri:"id" json:"-"` // 域名ID
+	ddns.DomainSpec
+}
+
+// GET /ddns/providers
+func (DdnsApi) ProviderListView(c *gin.Context) {
+	list := ddns.ListProviders()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /ddns/providers/:id
+func (DdnsApi) ProviderGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ProviderUriRequest](c)
+
+	p, err := ddns.GetProvider(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, p, c)
+}
+
+// POST /ddns/providers
+func (DdnsApi) ProviderCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ProviderCreateRequest](c)
+
+	p, err := ddns.AddProvider(cr.ProviderSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(p, c)
+}
+
+// PUT /ddns/providers/:id
+func (DdnsApi) ProviderUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ProviderUpdateRequest](c)
+
+	p, err := ddns.UpdateProvider(cr.ID, cr.ProviderSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, p, c)
+}
+
+// DELETE /ddns/providers/:id
+func (DdnsApi) ProviderDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ProviderUriRequest](c)
+
+	if err := ddns.DeleteProvider(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// GET /ddns/domains
+func (DdnsApi) DomainListView(c *gin.Context) {
+	list := ddns.ListDomains()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /ddns/domains/:id
+func (DdnsApi) DomainGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DomainUriRequest](c)
+
+	d, err := ddns.GetDomain(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, d, c)
+}
+
+// POST /ddns/domains
+func (DdnsApi) DomainCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DomainCreateRequest](c)
+
+	d, err := ddns.AddDomain(cr.DomainSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(d, c)
+}
+
+// PUT /ddns/domains/:id
+func (DdnsApi) DomainUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DomainUpdateRequest](c)
+
+	d, err := ddns.UpdateDomain(cr.ID, cr.DomainSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, d, c)
+}
+
+// DELETE /ddns/domains/:id
+func (DdnsApi) DomainDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DomainUriRequest](c)
+
+	if err := ddns.DeleteDomain(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// POST /ddns/domains/:id/sync 立即同步（不受最小间隔限制），返回同步后的状态
+func (DdnsApi) DomainSyncView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DomainUriRequest](c)
+
+	d, err := ddns.SyncDomain(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, d, c)
+}
@@ -0,0 +1,32 @@
+package ddns_api
+
+import (
+	"errors"
+	"linkstar/modules/ddns"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DdnsApi DDNS 管理接口（v2 风格）
+type DdnsApi struct {
+}
+
+// 将 ddns 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, ddns.ErrProviderNotFound), errors.Is(err, ddns.ErrDomainNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, ddns.ErrInvalidConfig):
+		errs := validate.Errors{{Field: ddns.InvalidField(err), Rule: "invalid", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	case errors.Is(err, ddns.ErrProviderInUse):
+		res.Error(http.StatusConflict, res.ErrCodeConflict, err.Error(), c)
+	case errors.Is(err, ddns.ErrNoPublicIP):
+		res.Error(http.StatusConflict, res.ErrCodeInvalidState, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
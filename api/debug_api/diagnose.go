@@ -0,0 +1,28 @@
+package debug_api
+
+import (
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 按服务 key 统计 goroutine 数量
+func (DebugApi) GoroutineSummaryView(c *gin.Context) {
+	res.OkWithData(stun.GetGoroutineSummary(), c)
+}
+
+// 输出 runningServices 中正在运行的服务
+func (DebugApi) RunningServicesView(c *gin.Context) {
+	res.OkWithData(stun.GetRunningServices(), c)
+}
+
+// 输出 UPnP 队列状态
+func (DebugApi) UpnpQueueView(c *gin.Context) {
+	res.OkWithData(stun.GetUpnpQueueState(), c)
+}
+
+// 输出当前 STUN 套接字绑定
+func (DebugApi) StunBindingsView(c *gin.Context) {
+	res.OkWithData(stun.GetStunBindings(), c)
+}
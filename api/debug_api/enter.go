@@ -0,0 +1,4 @@
+package debug_api
+
+type DebugApi struct {
+}
@@ -0,0 +1,24 @@
+package dns_api
+
+import (
+	"linkstar/modules/dnsserver"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /dns/status 运行状态与查询统计
+func (DnsApi) DnsStatusView(c *gin.Context) {
+	res.JSON(http.StatusOK, dnsserver.GetStatus(), c)
+}
+
+// GET /dns/records 当前可应答的全部记录
+func (DnsApi) DnsRecordListView(c *gin.Context) {
+	list, err := dnsserver.ListRecords()
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(list, int64(len(list)), c)
+}
@@ -0,0 +1,24 @@
+package dns_api
+
+import (
+	"errors"
+	"linkstar/modules/dnsserver"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DnsApi 内置权威 DNS 接口（v2 风格）
+type DnsApi struct {
+}
+
+// 将 dnsserver 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, dnsserver.ErrNotEnabled):
+		res.Error(http.StatusConflict, res.ErrCodeInvalidState, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
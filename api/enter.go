@@ -1,9 +1,39 @@
 package api
 
-import "linkstar/api/stun_api"
+import (
+	"linkstar/api/accesslog_api"
+	"linkstar/api/acme_api"
+	"linkstar/api/cert_api"
+	"linkstar/api/ddns_api"
+	"linkstar/api/debug_api"
+	"linkstar/api/dns_api"
+	"linkstar/api/firewall_api"
+	"linkstar/api/limit_api"
+	"linkstar/api/log_api"
+	"linkstar/api/metrics_api"
+	"linkstar/api/redirect_api"
+	"linkstar/api/srv_api"
+	"linkstar/api/stun_api"
+	"linkstar/api/stun_v2_api"
+	"linkstar/api/webhook_api"
+)
 
 type Api struct {
-	StunApi stun_api.StunApi
+	StunApi      stun_api.StunApi
+	StunV2Api    stun_v2_api.StunV2Api
+	DebugApi     debug_api.DebugApi
+	WebhookApi   webhook_api.WebhookApi
+	DdnsApi      ddns_api.DdnsApi
+	SrvApi       srv_api.SrvApi
+	DnsApi       dns_api.DnsApi
+	RedirectApi  redirect_api.RedirectApi
+	CertApi      cert_api.CertApi
+	AcmeApi      acme_api.AcmeApi
+	FirewallApi  firewall_api.FirewallApi
+	LimitApi     limit_api.LimitApi
+	MetricsApi   metrics_api.MetricsApi
+	AccessLogApi accesslog_api.AccessLogApi
+	LogApi       log_api.LogApi
 }
 
 var App = new(Api)
@@ -0,0 +1,24 @@
+package firewall_api
+
+import (
+	"errors"
+	"linkstar/modules/firewall"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FirewallApi 访问控制与自动封禁接口（v2 风格）
+type FirewallApi struct {
+}
+
+// 将 firewall 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, firewall.ErrBanNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,61 @@
+package firewall_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/firewall"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsUpdateRequest struct {
+	firewall.Settings
+}
+
+type BanUriRequest struct {
+	IP string `uri:"ip" json:"-" binding:"ip"` // 被封禁的地址
+}
+
+type BanClearResponse struct {
+	Cleared int `json:"cleared"` // 解除的封禁数量
+}
+
+// GET /firewall/settings
+func (FirewallApi) SettingsGetView(c *gin.Context) {
+	res.JSON(http.StatusOK, firewall.GetSettings(), c)
+}
+
+// PUT /firewall/settings 立即对新连接生效
+func (FirewallApi) SettingsUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SettingsUpdateRequest](c)
+
+	s, err := firewall.UpdateSettings(cr.Settings)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, s, c)
+}
+
+// GET /firewall/bans
+func (FirewallApi) BanListView(c *gin.Context) {
+	list := firewall.ListBans()
+	res.List(list, int64(len(list)), c)
+}
+
+// DELETE /firewall/bans 解除全部封禁
+func (FirewallApi) BanClearView(c *gin.Context) {
+	res.JSON(http.StatusOK, BanClearResponse{Cleared: firewall.ClearBans()}, c)
+}
+
+// DELETE /firewall/bans/:ip
+func (FirewallApi) BanDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[BanUriRequest](c)
+
+	if err := firewall.Unban(cr.IP); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
@@ -0,0 +1,17 @@
+package limit_api
+
+import (
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitApi 全局连接数与带宽限制接口（v2 风格）
+type LimitApi struct {
+}
+
+// 将 limiter 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+}
@@ -0,0 +1,31 @@
+package limit_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/limiter"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LimitsUpdateRequest struct {
+	limiter.Limits
+}
+
+// GET /limits
+func (LimitApi) LimitsGetView(c *gin.Context) {
+	res.JSON(http.StatusOK, limiter.GetGlobal(), c)
+}
+
+// PUT /limits 立即生效，不重启服务
+func (LimitApi) LimitsUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[LimitsUpdateRequest](c)
+
+	status, err := limiter.UpdateGlobal(cr.Limits)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, status, c)
+}
@@ -0,0 +1,24 @@
+package log_api
+
+import (
+	"errors"
+	"linkstar/core"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogApi 运行日志接口（v2 风格）
+type LogApi struct {
+}
+
+// 将 core 返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, core.ErrInvalidLogLevel):
+		res.Error(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,32 @@
+package log_api
+
+import (
+	"linkstar/core"
+	"linkstar/middleware"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LogLevelUpdateRequest struct {
+	Console string `json:"console" binding:"omitempty,oneof=trace debug info warn warning error"` // 控制台级别，为空不修改
+	File    string `json:"file" binding:"omitempty,oneof=trace debug info warn warning error"`    // 日志文件级别，为空不修改
+}
+
+// GET /log/level
+func (LogApi) LogLevelGetView(c *gin.Context) {
+	res.JSON(http.StatusOK, core.GetLogLevels(), c)
+}
+
+// PUT /log/level 立即生效，不写回配置文件，重启后恢复配置中的级别
+func (LogApi) LogLevelUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[LogLevelUpdateRequest](c)
+
+	levels, err := core.SetLogLevels(cr.Console, cr.File)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, levels, c)
+}
@@ -0,0 +1,5 @@
+package metrics_api
+
+// MetricsApi Prometheus 指标接口
+type MetricsApi struct {
+}
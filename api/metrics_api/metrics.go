@@ -0,0 +1,16 @@
+package metrics_api
+
+import (
+	"bytes"
+	"linkstar/modules/metrics"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /metrics Prometheus 文本格式
+func (MetricsApi) MetricsView(c *gin.Context) {
+	var buf bytes.Buffer
+	metrics.WriteTo(&buf)
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,28 @@
+package redirect_api
+
+import (
+	"errors"
+	"linkstar/modules/redirect"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedirectApi 固定地址跳转（/go 下，不属于 /api）
+type RedirectApi struct {
+}
+
+// 将 redirect 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, redirect.ErrServiceNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeServiceNotFound, err.Error(), c)
+	case errors.Is(err, redirect.ErrAmbiguous):
+		res.Error(http.StatusConflict, res.ErrCodeConflict, err.Error(), c)
+	case errors.Is(err, redirect.ErrServiceOffline):
+		res.Error(http.StatusServiceUnavailable, res.ErrCodeInvalidState, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,51 @@
+package redirect_api
+
+import (
+	"errors"
+	"linkstar/middleware"
+	"linkstar/modules/redirect"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GoRequest struct {
+	Name   string `uri:"name" json:"-"`    // 服务名称，忽略大小写，空格可写作 -
+	Device string `form:"device" json:"-"` // 设备名称或设备ID，同名服务分布在多个设备上时必填
+	Token  string `form:"token" json:"-"`  // 分享令牌（配置了 shareToken 时必填）
+}
+
+// GET /go/:name 302 跳转到服务当前的访问地址
+func (RedirectApi) GoRedirectView(c *gin.Context) {
+	cr := middleware.GetBindRequest[GoRequest](c)
+
+	target, err := redirect.Lookup(cr.Name, cr.Device)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	// 端口随时会变，禁止浏览器缓存跳转
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target.PublicURL)
+}
+
+// GET /go/:name/info 查询服务当前的访问地址（供脚本使用），不在线时 online 为 false
+func (RedirectApi) GoInfoView(c *gin.Context) {
+	cr := middleware.GetBindRequest[GoRequest](c)
+
+	target, err := redirect.Lookup(cr.Name, cr.Device)
+	if err != nil && !errors.Is(err, redirect.ErrServiceOffline) {
+		failWithError(err, c)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	res.JSON(http.StatusOK, target, c)
+}
+
+// GET /go 全部在线服务
+func (RedirectApi) GoListView(c *gin.Context) {
+	list := redirect.List()
+	c.Header("Cache-Control", "no-store")
+	res.List(list, int64(len(list)), c)
+}
@@ -0,0 +1,37 @@
+package srv_api
+
+import (
+	"errors"
+	"linkstar/modules/ddns"
+	"linkstar/modules/srv"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SrvApi SRV 记录管理接口（v2 风格）
+type SrvApi struct {
+}
+
+// 将 srv 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, stun.ErrDeviceNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeDeviceNotFound, err.Error(), c)
+	case errors.Is(err, stun.ErrServiceNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeServiceNotFound, err.Error(), c)
+	case errors.Is(err, srv.ErrRecordNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, ddns.ErrProviderNotFound):
+		errs := validate.Errors{{Field: "providerId", Rule: "exists", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	case errors.Is(err, srv.ErrInvalidConfig):
+		errs := validate.Errors{{Field: srv.InvalidField(err), Rule: "invalid", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
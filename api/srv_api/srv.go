@@ -0,0 +1,74 @@
+package srv_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/srv"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SrvUriRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+}
+
+type SrvSetRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	srv.RecordSpec
+}
+
+// GET /srv/records
+func (SrvApi) SrvListView(c *gin.Context) {
+	list := srv.ListRecords()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /devices/:id/services/:sid/srv
+func (SrvApi) SrvGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SrvUriRequest](c)
+
+	r, err := srv.GetRecord(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, r, c)
+}
+
+// PUT /devices/:id/services/:sid/srv 新增或替换
+func (SrvApi) SrvSetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SrvSetRequest](c)
+
+	r, err := srv.SetRecord(cr.DeviceID, cr.ServiceID, cr.RecordSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, r, c)
+}
+
+// DELETE /devices/:id/services/:sid/srv
+func (SrvApi) SrvDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SrvUriRequest](c)
+
+	if err := srv.DeleteRecord(cr.DeviceID, cr.ServiceID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// POST /devices/:id/services/:sid/srv/sync 立即重新发布，返回发布后的状态
+func (SrvApi) SrvSyncView(c *gin.Context) {
+	cr := middleware.GetBindRequest[SrvUriRequest](c)
+
+	r, err := srv.SyncRecord(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, r, c)
+}
@@ -0,0 +1,49 @@
+package stun_api
+
+import (
+	"errors"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 将 stun 模块返回的错误转换为 v1 响应，冲突类错误带上字段信息
+func failWithStunError(err error, c *gin.Context) {
+	if field := stun.ConflictField(err); field != "" {
+		errs := validate.Errors{{Field: field, Rule: "conflict", Message: err.Error()}}
+		res.FailWithDetails(err.Error(), errs, c)
+		return
+	}
+
+	if field := stun.InvalidField(err); field != "" {
+		errs := validate.Errors{{Field: field, Rule: "invalid", Message: err.Error()}}
+		res.FailWithDetails(err.Error(), errs, c)
+		return
+	}
+
+	if errors.Is(err, stun.ErrSaveConfig) {
+		res.FailWithMsg("保存配置失败", c)
+		return
+	}
+	res.FailWithMsg(err.Error(), c)
+}
+
+// 保存前探测内网目标，返回警告信息
+func probeService(deviceID uint, port uint16, protocol string) (string, error) {
+	device, err := stun.GetDevice(deviceID)
+	if err != nil {
+		return "", err
+	}
+	return stun.ProbeTarget(device.IP, port, protocol), nil
+}
+
+// 成功响应，有探测警告时写入 msg
+func okWithWarning(data any, warning string, c *gin.Context) {
+	if warning != "" {
+		res.Ok(data, "保存成功，但"+warning, c)
+		return
+	}
+	res.OkWithData(data, c)
+}
@@ -1,7 +1,6 @@
 package stun_api
 
 import (
-	"linkstar/global"
 	"linkstar/middleware"
 	"linkstar/modules/stun"
 	"linkstar/utils/res"
@@ -10,40 +9,15 @@ import (
 )
 
 type StunDeviceDeleteViewRequest struct {
-	DeviceID uint `json:"deviceId"` // 设备ID
+	DeviceID uint `json:"deviceId" binding:"required"` // 设备ID
 }
 
 func (StunApi) StunDeviceDeleteView(c *gin.Context) {
 	cr := middleware.GetBindRequest[StunDeviceDeleteViewRequest](c)
 
-	// 查找目标设备
-	deviceIndex := -1
-	for i, device := range global.StunConfig.Devices {
-		if device.DeviceID == cr.DeviceID {
-			deviceIndex = i
-			break
-		}
-	}
-	if deviceIndex == -1 {
-		res.FailWithMsg("设备不存在", c)
-		return
-	}
-
-	// 停止该设备下所有服务的 STUN 穿透
-	for _, svc := range global.StunConfig.Devices[deviceIndex].Services {
-		stun.StopService(cr.DeviceID, svc.ID)
-	}
-
-	// 从切片中删除该设备
-	devices := global.StunConfig.Devices
-	global.StunConfig.Devices = append(
-		devices[:deviceIndex],
-		devices[deviceIndex+1:]...,
-	)
-
-	// 持久化配置到文件
-	if err := stun.UpdateStunConfig(global.StunConfig); err != nil {
-		res.FailWithMsg("保存配置失败", c)
+	// 停止该设备下所有服务的 STUN 穿透并删除设备
+	if err := stun.DeleteDevice(cr.DeviceID); err != nil {
+		failWithStunError(err, c)
 		return
 	}
 
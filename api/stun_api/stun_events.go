@@ -0,0 +1,87 @@
+package stun_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"linkstar/middleware"
+	"linkstar/modules/event"
+	"linkstar/modules/stun"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sseRetry     = 3 * time.Second  // 建议客户端断线后的重连间隔
+	sseKeepAlive = 15 * time.Second // 心跳间隔，防止代理因空闲断开
+	sseBuffer    = 64               // 订阅缓冲，写满说明客户端过慢，断开后由客户端续传
+)
+
+type StunEventsViewRequest struct {
+	LastEventID uint64 `form:"lastEventId"` // 最后收到的事件ID，优先使用 Last-Event-ID 请求头
+}
+
+// 推送服务状态变化（Server-Sent Events）
+// 新连接先收到 snapshot 全量快照；断线重连时浏览器自动携带 Last-Event-ID，
+// 能续传则补发期间错过的事件，否则重新发送快照
+func (StunApi) StunEventsView(c *gin.Context) {
+	cr := middleware.GetBindRequest[StunEventsViewRequest](c)
+	lastID, _ := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64)
+	if lastID == 0 {
+		lastID = cr.LastEventID
+	}
+
+	sub, replay, ok := event.Default.SubscribeSince(lastID, sseBuffer)
+	defer sub.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds())
+	if !ok {
+		writeSSE(c.Writer, sub.LastID, "snapshot", gin.H{
+			"id":   sub.LastID,
+			"type": "snapshot",
+			"time": time.Now(),
+			"data": stun.Snapshot(),
+		})
+	}
+	for _, env := range replay {
+		writeSSE(c.Writer, env.ID, string(env.Type), env)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case env := <-sub.C:
+			if sub.Dropped() > 0 {
+				// 消费过慢丢了事件，断开让客户端重连续传
+				return
+			}
+			writeSSE(c.Writer, env.ID, string(env.Type), env)
+		case <-ticker.C:
+			io.WriteString(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeSSE(w io.Writer, id uint64, name string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, name, data)
+}
@@ -0,0 +1,57 @@
+package stun_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StunServiceActionViewRequest struct {
+	DeviceID  uint   `json:"deviceId" binding:"required"`                                // 设备ID
+	ServiceID uint   `json:"serviceId" binding:"required"`                               // 服务ID
+	Action    string `json:"action" binding:"required,oneof=start stop restart repunch"` // 操作：start 启用并启动 / stop 停止并禁用 / restart 重启 / repunch 重新打洞
+}
+
+type StunDeviceActionViewRequest struct {
+	DeviceID uint   `json:"deviceId" binding:"required"`                                // 设备ID
+	Action   string `json:"action" binding:"required,oneof=start stop restart repunch"` // 操作，对设备下所有服务执行
+}
+
+type StunServiceBulkActionViewRequest struct {
+	Action   string               `json:"action" binding:"required,oneof=start stop restart repunch"` // 操作
+	Services []stun.ServiceTarget `json:"services" binding:"required,min=1,dive"`                     // 目标服务列表
+}
+
+// 对单个服务执行启动/停止/重启/重新打洞
+func (StunApi) StunServiceActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[StunServiceActionViewRequest](c)
+
+	svc, err := stun.ServiceAction(cr.DeviceID, cr.ServiceID, cr.Action)
+	if err != nil {
+		failWithStunError(err, c)
+		return
+	}
+	res.OkWithData(svc, c)
+}
+
+// 对设备下所有服务执行同一操作
+func (StunApi) StunDeviceActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[StunDeviceActionViewRequest](c)
+
+	results, err := stun.DeviceAction(cr.DeviceID, cr.Action)
+	if err != nil {
+		failWithStunError(err, c)
+		return
+	}
+	res.OkWithList(results, int64(len(results)), c)
+}
+
+// 批量对多个服务执行同一操作
+func (StunApi) StunServiceBulkActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[StunServiceBulkActionViewRequest](c)
+
+	results := stun.BulkServiceAction(cr.Services, cr.Action)
+	res.OkWithList(results, int64(len(results)), c)
+}
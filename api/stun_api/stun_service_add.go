@@ -1,22 +1,38 @@
 package stun_api
 
 import (
-	"linkstar/global"
 	"linkstar/middleware"
+	"linkstar/modules/limiter"
 	"linkstar/modules/stun"
 	"linkstar/modules/stun/model"
-	"linkstar/utils/res"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type StunServiceAddViewRequest struct {
-	DeviceID     uint   `json:"deviceId"`     // 设备ID
-	Name         string `json:"name"`         // 服务名称,如 "SSH" / "Web管理" / "照片库"
-	InternalPort uint16 `json:"internalPort"` // 内网端口,如 22
-	Protocol     string `json:"protocol"`     // 传输协议 "TCP"/"UDP" (默认 TCP)
-	TLS          bool   `json:"tls"`          // 证书
+	DeviceID     uint   `json:"deviceId" binding:"required"`                                     // 设备ID
+	Name         string `json:"name" binding:"required"`                                         // 服务名称,如 "SSH" / "Web管理" / "照片库"
+	Type         string `json:"type" binding:"omitempty,oneof=forward http-proxy sni-proxy mux"` // 服务类型 forward 端口转发（默认）/ http-proxy HTTP 反向代理 / sni-proxy TLS 透传 / mux 协议分流
+	InternalPort uint16 `json:"internalPort"`                                                    // 内网端口,如 22；http-proxy / sni-proxy / mux 服务可选，作为默认目标
+	Protocol     string `json:"protocol" binding:"omitempty,protocol"`                           // 传输协议 "TCP"/"UDP" (默认 TCP)
+	TLS          bool   `json:"tls"`                                                             // 证书
+
+	// TLS 终止（仅 TCP）
+	TLSTerminate bool `json:"tlsTerminate"` // 在打洞端口上终止 TLS，以明文转发给内网目标
+	CertID       uint `json:"certId"`       // 使用的证书，0 为自动生成的自签名证书
+	HTTPRedirect bool `json:"httpRedirect"` // 明文 HTTP 请求 301 跳转到 https
+
+	Mux model.MuxConfig `json:"mux"` // mux 服务的协议分流配置
+
+	// PROXY protocol（仅 TCP）
+	ProxyProtocol       string `json:"proxyProtocol" binding:"omitempty,oneof=v1 v2"` // 连接内网目标时发送的 PROXY protocol 版本，为空不发送
+	AcceptProxyProtocol bool   `json:"acceptProxyProtocol"`                           // 入站连接带 PROXY protocol 头（位于其他中继之后）
+
+	// 访问控制
+	Allow []string `json:"allow" binding:"omitempty,dive,cidr"` // 允许的客户端 CIDR，非空时只接受其中的地址
+	Deny  []string `json:"deny" binding:"omitempty,dive,cidr"`  // 禁止的客户端 CIDR
+
+	Limits limiter.Limits `json:"limits"` // 并发连接、新建速率与带宽限制
 
 	// UPnP 相关配置
 	UseUPnP        bool   `json:"useUpnp"`        // 是否启用 UPnP 自动端口映射 (默认 true)
@@ -24,63 +40,47 @@ type StunServiceAddViewRequest struct {
 
 	Enabled     bool   `json:"enabled"`     // 服务是否启用 (默认 true)
 	Description string `json:"description"` // 服务描述信息 (可选)
+
+	Probe bool `json:"probe"` // 保存前探测内网目标是否在监听，未监听时返回警告
 }
 
 func (StunApi) StunServiceAddView(c *gin.Context) {
 	cr := middleware.GetBindRequest[StunServiceAddViewRequest](c)
 
-	// 查找目标设备
-	deviceIndex := -1
-	for i, device := range global.StunConfig.Devices {
-		if device.DeviceID == cr.DeviceID {
-			deviceIndex = i
-			break
-		}
-	}
-	if deviceIndex == -1 {
-		res.FailWithMsg("设备不存在", c)
-		return
-	}
-
-	// 生成新服务ID（取当前最大ID+1）
-	var maxID uint = 0
-	for _, svc := range global.StunConfig.Devices[deviceIndex].Services {
-		if svc.ID > maxID {
-			maxID = svc.ID
+	var warning string
+	if cr.Probe {
+		var err error
+		if warning, err = probeService(cr.DeviceID, cr.InternalPort, cr.Protocol); err != nil {
+			failWithStunError(err, c)
+			return
 		}
 	}
 
-	// 构建新服务
-	newService := model.Service{
-		ID:             maxID + 1,
-		Name:           cr.Name,
-		InternalPort:   cr.InternalPort,
-		Protocol:       cr.Protocol,
-		TLS:            cr.TLS,
-		UseUPnP:        cr.UseUPnP,
-		UPnPMappedPort: cr.UPnPMappedPort,
-		Enabled:        cr.Enabled,
-		Description:    cr.Description,
-		UpdatedAt:      time.Now(),
-	}
-
-	// 添加服务到设备
-	global.StunConfig.Devices[deviceIndex].Services = append(
-		global.StunConfig.Devices[deviceIndex].Services,
-		newService,
-	)
-
-	// 持久化配置到文件
-	if err := stun.UpdateStunConfig(global.StunConfig); err != nil {
-		res.FailWithMsg("保存配置失败", c)
+	// 持久化并启动该服务的 STUN 穿透
+	svc, err := stun.AddService(cr.DeviceID, model.ServiceSpec{
+		Name:                cr.Name,
+		Type:                cr.Type,
+		InternalPort:        cr.InternalPort,
+		Protocol:            cr.Protocol,
+		TLS:                 cr.TLS,
+		TLSTerminate:        cr.TLSTerminate,
+		CertID:              cr.CertID,
+		HTTPRedirect:        cr.HTTPRedirect,
+		Mux:                 cr.Mux,
+		ProxyProtocol:       cr.ProxyProtocol,
+		AcceptProxyProtocol: cr.AcceptProxyProtocol,
+		Allow:               cr.Allow,
+		Deny:                cr.Deny,
+		Limits:              cr.Limits,
+		UseUPnP:             cr.UseUPnP,
+		UPnPMappedPort:      cr.UPnPMappedPort,
+		Enabled:             cr.Enabled,
+		Description:         cr.Description,
+	})
+	if err != nil {
+		failWithStunError(err, c)
 		return
 	}
 
-	// 启动该服务的 STUN 穿透
-	device := &global.StunConfig.Devices[deviceIndex]
-	services := device.Services
-	stun.StartService(device, &services[len(services)-1])
-
-	res.OkWithData(newService, c)
+	okWithWarning(svc, warning, c)
 }
-
@@ -1,81 +1,87 @@
 package stun_api
 
 import (
-	"linkstar/global"
 	"linkstar/middleware"
+	"linkstar/modules/limiter"
 	"linkstar/modules/stun"
-	"linkstar/utils/res"
-	"time"
+	"linkstar/modules/stun/model"
 
 	"github.com/gin-gonic/gin"
 )
 
 type StunServiceUpdateViewRequest struct {
-	DeviceID  uint   `json:"deviceId"`  // 设备ID
-	ServiceID uint   `json:"serviceId"` // 服务ID
-	Name      string `json:"name"`      // 服务名称
-	InternalPort uint16 `json:"internalPort"` // 内网端口
-	Protocol     string `json:"protocol"`     // 传输协议 "TCP"/"UDP"
-	TLS          bool   `json:"tls"`          // 证书
+	DeviceID     uint   `json:"deviceId" binding:"required"`                                     // 设备ID
+	ServiceID    uint   `json:"serviceId" binding:"required"`                                    // 服务ID
+	Name         string `json:"name" binding:"required"`                                         // 服务名称
+	Type         string `json:"type" binding:"omitempty,oneof=forward http-proxy sni-proxy mux"` // 服务类型 forward / http-proxy / sni-proxy / mux
+	InternalPort uint16 `json:"internalPort"`                                                    // 内网端口，http-proxy / sni-proxy / mux 服务可选
+	Protocol     string `json:"protocol" binding:"omitempty,protocol"`                           // 传输协议 "TCP"/"UDP"
+	TLS          bool   `json:"tls"`                                                             // 证书
+
+	// TLS 终止（仅 TCP）
+	TLSTerminate bool `json:"tlsTerminate"` // 在打洞端口上终止 TLS，以明文转发给内网目标
+	CertID       uint `json:"certId"`       // 使用的证书，0 为自动生成的自签名证书
+	HTTPRedirect bool `json:"httpRedirect"` // 明文 HTTP 请求 301 跳转到 https
+
+	Mux model.MuxConfig `json:"mux"` // mux 服务的协议分流配置
+
+	// PROXY protocol（仅 TCP）
+	ProxyProtocol       string `json:"proxyProtocol" binding:"omitempty,oneof=v1 v2"` // 连接内网目标时发送的 PROXY protocol 版本，为空不发送
+	AcceptProxyProtocol bool   `json:"acceptProxyProtocol"`                           // 入站连接带 PROXY protocol 头（位于其他中继之后）
+
+	// 访问控制
+	Allow []string `json:"allow" binding:"omitempty,dive,cidr"` // 允许的客户端 CIDR，非空时只接受其中的地址
+	Deny  []string `json:"deny" binding:"omitempty,dive,cidr"`  // 禁止的客户端 CIDR
+
+	Limits limiter.Limits `json:"limits"` // 并发连接、新建速率与带宽限制
 
 	// UPnP 相关配置
-	UseUPnP        bool   `json:"useUpnp"`
-	UPnPMappedPort uint16 `json:"upnpMappedPort"`
+	UseUPnP        bool   `json:"useUpnp"`        // 是否启用 UPnP 自动端口映射
+	UPnPMappedPort uint16 `json:"upnpMappedPort"` // UPnP 实际映射成功的端口号
+
+	Enabled     bool   `json:"enabled"`     // 服务是否启用
+	Description string `json:"description"` // 服务描述信息 (可选)
 
-	Enabled     bool   `json:"enabled"`
-	Description string `json:"description"`
+	Probe bool `json:"probe"` // 保存前探测内网目标是否在监听，未监听时返回警告
 }
 
 func (StunApi) StunServiceUpdateView(c *gin.Context) {
 	cr := middleware.GetBindRequest[StunServiceUpdateViewRequest](c)
 
-	// 查找目标设备
-	deviceIndex := -1
-	for i, device := range global.StunConfig.Devices {
-		if device.DeviceID == cr.DeviceID {
-			deviceIndex = i
-			break
+	var warning string
+	if cr.Probe {
+		var err error
+		if warning, err = probeService(cr.DeviceID, cr.InternalPort, cr.Protocol); err != nil {
+			failWithStunError(err, c)
+			return
 		}
 	}
-	if deviceIndex == -1 {
-		res.FailWithMsg("设备不存在", c)
-		return
-	}
 
-	// 查找目标服务
-	serviceIndex := -1
-	for i, svc := range global.StunConfig.Devices[deviceIndex].Services {
-		if svc.ID == cr.ServiceID {
-			serviceIndex = i
-			break
-		}
-	}
-	if serviceIndex == -1 {
-		res.FailWithMsg("服务不存在", c)
+	// 持久化并重启该服务的 STUN 穿透（停旧起新）
+	svc, err := stun.UpdateService(cr.DeviceID, cr.ServiceID, model.ServiceSpec{
+		Name:                cr.Name,
+		Type:                cr.Type,
+		InternalPort:        cr.InternalPort,
+		Protocol:            cr.Protocol,
+		TLS:                 cr.TLS,
+		TLSTerminate:        cr.TLSTerminate,
+		CertID:              cr.CertID,
+		HTTPRedirect:        cr.HTTPRedirect,
+		Mux:                 cr.Mux,
+		ProxyProtocol:       cr.ProxyProtocol,
+		AcceptProxyProtocol: cr.AcceptProxyProtocol,
+		Allow:               cr.Allow,
+		Deny:                cr.Deny,
+		Limits:              cr.Limits,
+		UseUPnP:             cr.UseUPnP,
+		UPnPMappedPort:      cr.UPnPMappedPort,
+		Enabled:             cr.Enabled,
+		Description:         cr.Description,
+	})
+	if err != nil {
+		failWithStunError(err, c)
 		return
 	}
 
-	// 更新服务字段
-	svc := &global.StunConfig.Devices[deviceIndex].Services[serviceIndex]
-	svc.Name = cr.Name
-	svc.InternalPort = cr.InternalPort
-	svc.Protocol = cr.Protocol
-	svc.TLS = cr.TLS
-	svc.UseUPnP = cr.UseUPnP
-	svc.UPnPMappedPort = cr.UPnPMappedPort
-	svc.Enabled = cr.Enabled
-	svc.Description = cr.Description
-	svc.UpdatedAt = time.Now()
-
-	// 持久化配置到文件
-	if err := stun.UpdateStunConfig(global.StunConfig); err != nil {
-		res.FailWithMsg("保存配置失败", c)
-		return
-	}
-
-	// 重启该服务的 STUN 穿透（停旧起新）
-	device := &global.StunConfig.Devices[deviceIndex]
-	stun.StartService(device, &device.Services[serviceIndex])
-
-	res.OkWithData(*svc, c)
+	okWithWarning(svc, warning, c)
 }
@@ -0,0 +1,58 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceActionRequest struct {
+	DeviceID  uint   `uri:"id" json:"-"`                                                         // 设备ID
+	ServiceID uint   `uri:"sid" json:"-"`                                                        // 服务ID
+	Action    string `uri:"action" json:"-" binding:"required,oneof=start stop restart repunch"` // 操作：start 启用并启动 / stop 停止并禁用 / restart 重启 / repunch 重新打洞
+}
+
+type DeviceActionRequest struct {
+	DeviceID uint   `uri:"id" json:"-"`                                                         // 设备ID
+	Action   string `uri:"action" json:"-" binding:"required,oneof=start stop restart repunch"` // 操作，对设备下所有服务执行
+}
+
+type BulkActionRequest struct {
+	Action   string               `uri:"action" json:"-" binding:"required,oneof=start stop restart repunch"` // 操作
+	Services []stun.ServiceTarget `json:"services" binding:"required,min=1,dive"`                             // 目标服务列表
+}
+
+// POST /devices/:id/services/:sid/actions/:action
+func (StunV2Api) ServiceActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceActionRequest](c)
+
+	svc, err := stun.ServiceAction(cr.DeviceID, cr.ServiceID, cr.Action)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, svc, c)
+}
+
+// POST /devices/:id/actions/:action
+func (StunV2Api) DeviceActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceActionRequest](c)
+
+	results, err := stun.DeviceAction(cr.DeviceID, cr.Action)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(results, int64(len(results)), c)
+}
+
+// POST /services/actions/:action
+func (StunV2Api) BulkActionView(c *gin.Context) {
+	cr := middleware.GetBindRequest[BulkActionRequest](c)
+
+	results := stun.BulkServiceAction(cr.Services, cr.Action)
+	res.List(results, int64(len(results)), c)
+}
@@ -0,0 +1,14 @@
+package stun_v2_api
+
+import (
+	"linkstar/global"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /config 获取全部的stun配置文件信息
+func (StunV2Api) ConfigView(c *gin.Context) {
+	res.JSON(http.StatusOK, global.StunConfig, c)
+}
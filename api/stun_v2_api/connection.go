@@ -0,0 +1,72 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConnUriRequest struct {
+	DeviceID  uint   `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint   `uri:"sid" json:"-"` // 服务ID
+	ConnID    uint64 `uri:"cid" json:"-"` // 连接ID
+}
+
+type ConnIPRequest struct {
+	IP string `uri:"ip" json:"-" binding:"ip"` // 客户端地址
+}
+
+type ConnKillResponse struct {
+	Killed int `json:"killed"` // 断开的连接数
+}
+
+// GET /connections 所有服务正在转发的连接
+func (StunV2Api) ConnListAllView(c *gin.Context) {
+	list := stun.ListAllConns()
+	res.List(list, int64(len(list)), c)
+}
+
+// DELETE /connections/ip/:ip 断开来自该地址的全部连接
+func (StunV2Api) ConnKillFromView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ConnIPRequest](c)
+
+	res.JSON(http.StatusOK, ConnKillResponse{Killed: stun.KillConnsFrom(cr.IP)}, c)
+}
+
+// GET /devices/:id/services/:sid/connections
+func (StunV2Api) ConnListView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	list, err := stun.ListConns(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(list, int64(len(list)), c)
+}
+
+// DELETE /devices/:id/services/:sid/connections 断开服务的全部连接
+func (StunV2Api) ConnKillServiceView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	n, err := stun.KillServiceConns(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, ConnKillResponse{Killed: n}, c)
+}
+
+// DELETE /devices/:id/services/:sid/connections/:cid
+func (StunV2Api) ConnKillView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ConnUriRequest](c)
+
+	if err := stun.KillConn(cr.DeviceID, cr.ServiceID, cr.ConnID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
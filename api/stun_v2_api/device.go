@@ -0,0 +1,78 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeviceUriRequest struct {
+	DeviceID uint `uri:"id" json:"-"` // 设备ID
+}
+
+type DeviceCreateRequest struct {
+	Name string `json:"name" binding:"required"`  // 设备名称，如 "群晖NAS" / "树莓派"
+	IP   string `json:"ip" binding:"required,ip"` // 设备内网 IP
+}
+
+type DeviceUpdateRequest struct {
+	DeviceID uint   `uri:"id" json:"-"`               // 设备ID
+	Name     string `json:"name" binding:"required"`  // 设备名称
+	IP       string `json:"ip" binding:"required,ip"` // 设备内网 IP
+}
+
+// GET /devices
+func (StunV2Api) DeviceListView(c *gin.Context) {
+	devices := stun.ListDevices()
+	res.List(devices, int64(len(devices)), c)
+}
+
+// GET /devices/:id
+func (StunV2Api) DeviceGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceUriRequest](c)
+
+	device, err := stun.GetDevice(cr.DeviceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, device, c)
+}
+
+// POST /devices
+func (StunV2Api) DeviceCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceCreateRequest](c)
+
+	device, err := stun.AddDevice(cr.Name, cr.IP)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(device, c)
+}
+
+// PUT /devices/:id
+func (StunV2Api) DeviceUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceUpdateRequest](c)
+
+	device, err := stun.UpdateDevice(cr.DeviceID, cr.Name, cr.IP)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, device, c)
+}
+
+// DELETE /devices/:id
+func (StunV2Api) DeviceDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceUriRequest](c)
+
+	if err := stun.DeleteDevice(cr.DeviceID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
@@ -0,0 +1,47 @@
+package stun_v2_api
+
+import (
+	"errors"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StunV2Api 资源风格的 v2 接口，返回真实 HTTP 状态码
+type StunV2Api struct {
+}
+
+// 将 stun 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	if field := stun.ConflictField(err); field != "" {
+		errs := validate.Errors{{Field: field, Rule: "conflict", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusConflict, res.ErrCodeConflict, err.Error(), errs, c)
+		return
+	}
+
+	if field := stun.InvalidField(err); field != "" {
+		errs := validate.Errors{{Field: field, Rule: "invalid", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+		return
+	}
+
+	switch {
+	case errors.Is(err, stun.ErrDeviceNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeDeviceNotFound, err.Error(), c)
+	case errors.Is(err, stun.ErrServiceNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeServiceNotFound, err.Error(), c)
+	case errors.Is(err, stun.ErrRouteNotFound), errors.Is(err, stun.ErrConnNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, stun.ErrNotProxyService):
+		res.Error(http.StatusConflict, res.ErrCodeInvalidState, err.Error(), c)
+	case errors.Is(err, stun.ErrServiceDisabled), errors.Is(err, stun.ErrServiceNotRunning):
+		res.Error(http.StatusConflict, res.ErrCodeInvalidState, err.Error(), c)
+	case errors.Is(err, stun.ErrUnknownAction):
+		res.Error(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
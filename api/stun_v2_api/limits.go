@@ -0,0 +1,41 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/limiter"
+	"linkstar/modules/stun"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceLimitsUpdateRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	limiter.Limits
+}
+
+// GET /devices/:id/services/:sid/limits
+func (StunV2Api) ServiceLimitsGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	status, err := stun.GetServiceLimits(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, status, c)
+}
+
+// PUT /devices/:id/services/:sid/limits 立即生效，不重新打洞
+func (StunV2Api) ServiceLimitsUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceLimitsUpdateRequest](c)
+
+	status, err := stun.UpdateServiceLimits(cr.DeviceID, cr.ServiceID, cr.Limits)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, status, c)
+}
@@ -0,0 +1,77 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/modules/stun/model"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RouteUriRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	RouteID   uint `uri:"rid" json:"-"` // 路由ID
+}
+
+type RouteCreateRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	model.ProxyRouteSpec
+}
+
+type RouteUpdateRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	RouteID   uint `uri:"rid" json:"-"` // 路由ID
+	model.ProxyRouteSpec
+}
+
+// GET /devices/:id/services/:sid/routes
+func (StunV2Api) RouteListView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	list, err := stun.ListRoutes(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(list, int64(len(list)), c)
+}
+
+// POST /devices/:id/services/:sid/routes 立即生效，不重新打洞
+func (StunV2Api) RouteCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[RouteCreateRequest](c)
+
+	route, err := stun.AddRoute(cr.DeviceID, cr.ServiceID, cr.ProxyRouteSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(route, c)
+}
+
+// PUT /devices/:id/services/:sid/routes/:rid
+func (StunV2Api) RouteUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[RouteUpdateRequest](c)
+
+	route, err := stun.UpdateRoute(cr.DeviceID, cr.ServiceID, cr.RouteID, cr.ProxyRouteSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, route, c)
+}
+
+// DELETE /devices/:id/services/:sid/routes/:rid
+func (StunV2Api) RouteDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[RouteUriRequest](c)
+
+	if err := stun.DeleteRoute(cr.DeviceID, cr.ServiceID, cr.RouteID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
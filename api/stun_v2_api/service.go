@@ -0,0 +1,124 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/modules/stun/model"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceUriRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+}
+
+type ServiceCreateRequest struct {
+	DeviceID uint `uri:"id" json:"-"` // 设备ID
+	model.ServiceSpec
+	Probe bool `json:"probe"` // 保存前探测内网目标是否在监听，未监听时返回警告
+}
+
+type ServiceUpdateRequest struct {
+	DeviceID  uint `uri:"id" json:"-"`  // 设备ID
+	ServiceID uint `uri:"sid" json:"-"` // 服务ID
+	model.ServiceSpec
+	Probe bool `json:"probe"` // 保存前探测内网目标是否在监听，未监听时返回警告
+}
+
+// ServiceResponse 新增/修改服务的响应
+type ServiceResponse struct {
+	*model.Service
+	Warnings []string `json:"warnings,omitempty"` // 探测警告
+}
+
+// 保存前探测内网目标，返回警告列表
+func probeService(deviceID uint, spec model.ServiceSpec) ([]string, error) {
+	device, err := stun.GetDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if warning := stun.ProbeTarget(device.IP, spec.InternalPort, spec.Protocol); warning != "" {
+		return []string{warning}, nil
+	}
+	return nil, nil
+}
+
+// GET /devices/:id/services
+func (StunV2Api) ServiceListView(c *gin.Context) {
+	cr := middleware.GetBindRequest[DeviceUriRequest](c)
+
+	device, err := stun.GetDevice(cr.DeviceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.List(device.Services, int64(len(device.Services)), c)
+}
+
+// GET /devices/:id/services/:sid
+func (StunV2Api) ServiceGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	_, svc, err := stun.GetService(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, svc, c)
+}
+
+// POST /devices/:id/services
+func (StunV2Api) ServiceCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceCreateRequest](c)
+
+	var warnings []string
+	if cr.Probe {
+		var err error
+		if warnings, err = probeService(cr.DeviceID, cr.ServiceSpec); err != nil {
+			failWithError(err, c)
+			return
+		}
+	}
+
+	svc, err := stun.AddService(cr.DeviceID, cr.ServiceSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(ServiceResponse{Service: svc, Warnings: warnings}, c)
+}
+
+// PUT /devices/:id/services/:sid
+func (StunV2Api) ServiceUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUpdateRequest](c)
+
+	var warnings []string
+	if cr.Probe {
+		var err error
+		if warnings, err = probeService(cr.DeviceID, cr.ServiceSpec); err != nil {
+			failWithError(err, c)
+			return
+		}
+	}
+
+	svc, err := stun.UpdateService(cr.DeviceID, cr.ServiceID, cr.ServiceSpec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, ServiceResponse{Service: svc, Warnings: warnings}, c)
+}
+
+// DELETE /devices/:id/services/:sid
+func (StunV2Api) ServiceDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	if err := stun.DeleteService(cr.DeviceID, cr.ServiceID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
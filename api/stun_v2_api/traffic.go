@@ -0,0 +1,49 @@
+package stun_v2_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/stun"
+	"linkstar/modules/traffic"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /traffic 全部服务的累计流量
+func (StunV2Api) TrafficListView(c *gin.Context) {
+	list := traffic.List()
+	res.List(list, int64(len(list)), c)
+}
+
+// DELETE /traffic 清空全部服务的流量统计
+func (StunV2Api) TrafficResetView(c *gin.Context) {
+	if err := traffic.ResetAll(); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// GET /devices/:id/services/:sid/traffic
+func (StunV2Api) ServiceTrafficGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	stats, err := stun.GetServiceTraffic(cr.DeviceID, cr.ServiceID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, stats, c)
+}
+
+// DELETE /devices/:id/services/:sid/traffic
+func (StunV2Api) ServiceTrafficResetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[ServiceUriRequest](c)
+
+	if err := stun.ResetServiceTraffic(cr.DeviceID, cr.ServiceID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
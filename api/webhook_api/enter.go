@@ -0,0 +1,28 @@
+package webhook_api
+
+import (
+	"errors"
+	"linkstar/modules/webhook"
+	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookApi webhook 管理接口（v2 风格）
+type WebhookApi struct {
+}
+
+// 将 webhook 模块返回的错误转换为 v2 响应
+func failWithError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, webhook.ErrWebhookNotFound):
+		res.Error(http.StatusNotFound, res.ErrCodeNotFound, err.Error(), c)
+	case errors.Is(err, webhook.ErrInvalidTemplate):
+		errs := validate.Errors{{Field: "bodyTemplate", Rule: "template", Message: err.Error()}}
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, err.Error(), errs, c)
+	default:
+		res.Error(http.StatusInternalServerError, res.ErrCodeInternal, err.Error(), c)
+	}
+}
@@ -0,0 +1,101 @@
+package webhook_api
+
+import (
+	"linkstar/middleware"
+	"linkstar/modules/webhook"
+	"linkstar/utils/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookUriRequest struct {
+	ID uint `uri:"id" json:"-"` // webhook ID
+}
+
+type WebhookCreateRequest struct {
+	webhook.Spec
+}
+
+type WebhookUpdateRequest struct {
+	ID uint `uri:"id" json:"-"` // webhook ID
+	webhook.Spec
+	RemoveSecret bool `json:"removeSecret"` // 清除签名密钥；secret 为空且不清除时保留原密钥
+}
+
+// GET /webhooks
+func (WebhookApi) WebhookListView(c *gin.Context) {
+	list := webhook.ListWebhooks()
+	res.List(list, int64(len(list)), c)
+}
+
+// GET /webhooks/:id
+func (WebhookApi) WebhookGetView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookUriRequest](c)
+
+	wh, err := webhook.GetWebhook(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, wh, c)
+}
+
+// POST /webhooks
+func (WebhookApi) WebhookCreateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookCreateRequest](c)
+
+	wh, err := webhook.AddWebhook(cr.Spec)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.Created(wh, c)
+}
+
+// PUT /webhooks/:id
+func (WebhookApi) WebhookUpdateView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookUpdateRequest](c)
+
+	wh, err := webhook.UpdateWebhook(cr.ID, cr.Spec, cr.RemoveSecret)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, wh, c)
+}
+
+// DELETE /webhooks/:id
+func (WebhookApi) WebhookDeleteView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookUriRequest](c)
+
+	if err := webhook.DeleteWebhook(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.NoContent(c)
+}
+
+// POST /webhooks/:id/test 立即发送测试事件，返回投递结果
+func (WebhookApi) WebhookTestView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookUriRequest](c)
+
+	delivery, err := webhook.TestWebhook(cr.ID)
+	if err != nil {
+		failWithError(err, c)
+		return
+	}
+	res.JSON(http.StatusOK, delivery, c)
+}
+
+// GET /webhooks/:id/deliveries 最近的投递记录
+func (WebhookApi) WebhookDeliveryListView(c *gin.Context) {
+	cr := middleware.GetBindRequest[WebhookUriRequest](c)
+
+	if _, err := webhook.GetWebhook(cr.ID); err != nil {
+		failWithError(err, c)
+		return
+	}
+	list := webhook.ListDeliveries(cr.ID)
+	res.List(list, int64(len(list)), c)
+}
@@ -0,0 +1,5 @@
+package conf
+
+type Debug struct {
+	Enable bool `json:"enable"` // 是否开启 /debug 调试接口（pprof、运行时诊断），默认关闭
+}
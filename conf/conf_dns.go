@@ -0,0 +1,11 @@
+package conf
+
+// DNS 内置权威 DNS（将子域名 NS 委派给本机后，根据实时状态应答 A / SRV / TXT）
+type DNS struct {
+	Enable bool   `json:"enable"` // 是否开启，默认关闭
+	Listen string `json:"listen"` // 监听地址（UDP + TCP），默认 ":5353"
+	Zone   string `json:"zone"`   // 委派给本机的子域名，如 home.example.com
+	NS     string `json:"ns"`     // 本机的 NS 主机名，默认 ns.<zone>
+	TTL    uint32 `json:"ttl"`    // 应答 TTL（秒），默认 30
+	UPnP   bool   `json:"upnp"`   // 通过 UPnP 将外网 UDP/53 映射到监听端口
+}
@@ -0,0 +1,10 @@
+package conf
+
+type Log struct {
+	Level     string `json:"level"`     // 控制台级别：trace / debug / info / warn / error，默认 debug
+	FileLevel string `json:"fileLevel"` // 日志文件级别，默认与 level 相同
+	Format    string `json:"format"`    // text（默认）或 json（每行一个 JSON 对象），控制台和文件都生效
+	MaxDays   int    `json:"maxDays"`   // 按天保留（含当天），0 表示不按天数清理
+	MaxSizeMB int    `json:"maxSizeMB"` // logs 目录总大小上限（MB），超出时从最早的一天删起，0 表示不限
+	Compress  bool   `json:"compress"`  // 是否 gzip 压缩之前日期的日志
+}
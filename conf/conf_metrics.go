@@ -0,0 +1,6 @@
+package conf
+
+type Metrics struct {
+	Enable bool `json:"enable"` // 是否开启 /metrics（Prometheus 文本格式），默认关闭
+	Auth   bool `json:"auth"`   // 是否要求携带 API token，与 /api 使用相同的鉴权方式
+}
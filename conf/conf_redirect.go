@@ -0,0 +1,8 @@
+package conf
+
+// Redirect 固定地址跳转（/go/<服务名> 302 到服务当前的访问地址）
+type Redirect struct {
+	ShareToken string `json:"shareToken"` // 分享令牌，非空时访问 /go 需携带 ?token=（API token 同样可用），为空不鉴权
+	Listen     string `json:"listen"`     // 额外的独立监听地址，如 ":8088"，为空只在面板地址上提供
+	UPnPPort   uint16 `json:"upnpPort"`   // 通过 UPnP 将该外网 TCP 端口映射到独立监听端口，0 不映射
+}
@@ -0,0 +1,6 @@
+package conf
+
+type System struct {
+	Addr  string `json:"addr"`  // 后端监听地址，如 "0.0.0.0:3333"
+	Token string `json:"token"` // API 访问令牌，为空表示不鉴权
+}
@@ -0,0 +1,11 @@
+package conf
+
+// Config 程序运行配置（config/settings.json）
+type Config struct {
+	System   System   `json:"system"`   // 系统配置
+	Debug    Debug    `json:"debug"`    // 调试接口配置
+	DNS      DNS      `json:"dns"`      // 内置权威 DNS
+	Redirect Redirect `json:"redirect"` // 固定地址跳转
+	Metrics  Metrics  `json:"metrics"`  // Prometheus 指标
+	Log      Log      `json:"log"`      // 运行日志
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"linkstar/conf"
+	"linkstar/flags"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 默认配置
+func defaultConf() *conf.Config {
+	return &conf.Config{
+		System: conf.System{
+			Addr: "0.0.0.0:3333",
+		},
+		Log: conf.Log{
+			MaxDays:  30,
+			Compress: true,
+		},
+	}
+}
+
+// ReadConf 读取配置文件，不存在时生成默认配置
+func ReadConf() *conf.Config {
+	filePath := flags.FlagOptions.File
+
+	if fileInfo, err := os.Stat(filePath); os.IsNotExist(err) || fileInfo.Size() == 0 {
+		c := defaultConf()
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			logrus.Fatalf("创建配置目录失败：%v", err)
+		}
+		if err := utilsFile.WriteJsonFile(filePath, c); err != nil {
+			logrus.Fatalf("写入默认配置失败：%v", err)
+		}
+		return c
+	}
+
+	c, err := utilsFile.ReadJsonFile[*conf.Config](filePath)
+	if err != nil {
+		logrus.Fatalf("读取配置文件失败 %s：%v", filePath, err)
+	}
+	if c.System.Addr == "" {
+		c.System.Addr = defaultConf().System.Addr
+	}
+	return c
+}
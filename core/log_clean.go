@@ -0,0 +1,156 @@
+package core
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"linkstar/conf"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 最近修改过的文件可能仍在写入（跨天时的最后几条），暂不压缩
+const compressIdle = 10 * time.Minute
+
+// 启动时清理一次，之后每小时一次
+func runLogCleaner(logPath string, c conf.Log) {
+	if c.MaxDays <= 0 && c.MaxSizeMB <= 0 && !c.Compress {
+		return
+	}
+	go func() {
+		for {
+			cleanLogs(logPath, c, time.Now())
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+// 日志目录下的按日期子目录，名称升序即时间升序
+func logDays(logPath string) []string {
+	entries, err := os.ReadDir(logPath)
+	if err != nil {
+		return nil
+	}
+	var days []string
+	for _, e := range entries {
+		if _, err := time.Parse(time.DateOnly, e.Name()); e.IsDir() && err == nil {
+			days = append(days, e.Name())
+		}
+	}
+	return days
+}
+
+func cleanLogs(logPath string, c conf.Log, now time.Time) {
+	today := now.Format(time.DateOnly)
+	days := logDays(logPath)
+
+	// 按天数删除，保留含当天在内的 MaxDays 天
+	if c.MaxDays > 0 {
+		oldest := now.AddDate(0, 0, 1-c.MaxDays).Format(time.DateOnly)
+		for len(days) > 0 && days[0] < oldest {
+			removeLogDay(logPath, days[0])
+			days = days[1:]
+		}
+	}
+
+	if c.Compress {
+		for _, day := range days {
+			if day < today {
+				compressLogDay(filepath.Join(logPath, day), now)
+			}
+		}
+	}
+
+	// 按总大小删除，当天的日志不删
+	if c.MaxSizeMB > 0 {
+		limit := int64(c.MaxSizeMB) << 20
+		sizes := make([]int64, len(days))
+		var total int64
+		for i, day := range days {
+			sizes[i] = dirSize(filepath.Join(logPath, day))
+			total += sizes[i]
+		}
+		for i := 0; total > limit && i < len(days) && days[i] < today; i++ {
+			removeLogDay(logPath, days[i])
+			total -= sizes[i]
+		}
+	}
+}
+
+func removeLogDay(logPath, day string) {
+	if err := os.RemoveAll(filepath.Join(logPath, day)); err != nil {
+		logrus.Warnf("删除日志目录 %s 失败: %v", day, err)
+		return
+	}
+	logrus.Infof("已删除过期日志 %s", day)
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// 压缩目录下尚未压缩的日志文件
+func compressLogDay(dir string, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".gz") || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || now.Sub(info.ModTime()) < compressIdle {
+			continue
+		}
+		if err := gzipFile(filepath.Join(dir, e.Name())); err != nil {
+			logrus.Warnf("压缩日志失败: %v", err)
+		}
+	}
+}
+
+// 压缩为 <name>.gz 后删除原文件，先写临时文件，中途失败不留下半个 .gz
+func gzipFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := name + ".gz.tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("压缩 %s 失败: %w", name, err)
+	}
+	src.Close()
+	return os.Remove(name)
+}
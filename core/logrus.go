@@ -2,15 +2,23 @@ package core
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"linkstar/conf"
 	"os"
 	"path"
+	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
 
+var ErrInvalidLogLevel = errors.New("日志级别只能是 trace / debug / info / warn / error")
+
+// MyLog 文本格式，NoColor 为 true 时不输出颜色（写文件时使用）
 type MyLog struct {
+	NoColor bool
 }
 
 // 颜色
@@ -21,7 +29,7 @@ const (
 	gray   = 37
 )
 
-func (MyLog) Format(entry *logrus.Entry) ([]byte, error) {
+func (f MyLog) Format(entry *logrus.Entry) ([]byte, error) {
 	var levelColor int
 	switch entry.Level {
 	case logrus.DebugLevel, logrus.TraceLevel:
@@ -40,6 +48,14 @@ func (MyLog) Format(entry *logrus.Entry) ([]byte, error) {
 		b = &bytes.Buffer{}
 	}
 	timestamp := entry.Time.Format("2006-01-02 15:04:05")
+	if f.NoColor {
+		if entry.HasCaller() {
+			fmt.Fprintf(b, "[%s] [%s] [%s:%d,%s] %s\n", timestamp, entry.Level, path.Base(entry.Caller.File), entry.Caller.Line, entry.Caller.Function, entry.Message)
+		} else {
+			fmt.Fprintf(b, "[%s] [%s] %s\n", timestamp, entry.Level, entry.Message)
+		}
+		return b.Bytes(), nil
+	}
 	if entry.HasCaller() {
 		funcVal := entry.Caller.Function
 		fileVal := fmt.Sprintf("%s:%d", path.Base(entry.Caller.File), entry.Caller.Line)
@@ -50,24 +66,126 @@ func (MyLog) Format(entry *logrus.Entry) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// JSON 格式，caller 输出为 func 和 file:line 两个字段
+var jsonFormatter = &logrus.JSONFormatter{
+	TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+	CallerPrettyfier: func(frame *runtime.Frame) (string, string) {
+		return frame.Function, fmt.Sprintf("%s:%d", path.Base(frame.File), frame.Line)
+	},
+}
+
+// consoleFormatter 低于控制台级别的日志格式化为空，logrus 写出 0 字节
+type consoleFormatter struct {
+	logrus.Formatter
+}
+
+func (f consoleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > logrus.Level(consoleLevel.Load()) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+var (
+	consoleLevel atomic.Uint32 // 控制台级别
+	fileLevel    atomic.Uint32 // 日志文件级别
+	fileHook     = &Myhook{logPath: "logs", formatter: MyLog{NoColor: true}}
+)
+
+// InitLogger 在读取配置前初始化，控制台和文件都是 Debug 级别的文本格式
 func InitLogger() {
+	consoleLevel.Store(uint32(logrus.DebugLevel))
+	fileLevel.Store(uint32(logrus.DebugLevel))
 	logrus.SetLevel(logrus.DebugLevel)
 	logrus.SetReportCaller(true)
-	logrus.SetFormatter(MyLog{})
-	logrus.AddHook(&Myhook{
-		logPath: "logs",
-	})
+	logrus.SetFormatter(consoleFormatter{MyLog{}})
+	logrus.AddHook(fileHook)
+}
+
+// ConfigureLogger 按配置设置级别、格式，并启动日志清理
+func ConfigureLogger(c conf.Log) {
+	if c.Format == "json" {
+		logrus.SetFormatter(consoleFormatter{jsonFormatter})
+		fileHook.setFormatter(jsonFormatter)
+	} else if c.Format != "" && c.Format != "text" {
+		logrus.Warnf("未知的日志格式 %q，使用 text", c.Format)
+	}
+
+	if c.FileLevel == "" {
+		c.FileLevel = c.Level
+	}
+	if _, err := SetLogLevels(c.Level, c.FileLevel); err != nil {
+		logrus.Warnf("日志级别配置无效（%s / %s），使用 debug: %v", c.Level, c.FileLevel, err)
+	}
+
+	runLogCleaner(fileHook.logPath, c)
+}
+
+// LogLevels 当前控制台与日志文件级别
+type LogLevels struct {
+	Console string `json:"console"` // 控制台级别
+	File    string `json:"file"`    // 日志文件级别
+}
+
+// GetLogLevels 当前日志级别
+func GetLogLevels() LogLevels {
+	return LogLevels{
+		Console: logrus.Level(consoleLevel.Load()).String(),
+		File:    logrus.Level(fileLevel.Load()).String(),
+	}
+}
+
+// 解析级别，空字符串返回 current
+func parseLogLevel(level string, current logrus.Level) (logrus.Level, error) {
+	if level == "" {
+		return current, nil
+	}
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil || lvl < logrus.ErrorLevel {
+		return 0, ErrInvalidLogLevel
+	}
+	return lvl, nil
+}
+
+// SetLogLevels 运行时修改日志级别，空字符串表示不修改；不写回配置文件，重启后恢复配置中的级别
+func SetLogLevels(console, file string) (LogLevels, error) {
+	consoleLvl, err := parseLogLevel(console, logrus.Level(consoleLevel.Load()))
+	if err != nil {
+		return GetLogLevels(), err
+	}
+	fileLvl, err := parseLogLevel(file, logrus.Level(fileLevel.Load()))
+	if err != nil {
+		return GetLogLevels(), err
+	}
+
+	consoleLevel.Store(uint32(consoleLvl))
+	fileLevel.Store(uint32(fileLvl))
+	// logrus 的全局级别取两者中较详细的一个，各输出再各自过滤
+	logrus.SetLevel(max(consoleLvl, fileLvl))
+	return GetLogLevels(), nil
 }
 
 type Myhook struct {
-	file    *os.File
-	errFile *os.File
-	date    string
-	logPath string
-	mu      sync.Mutex
+	file      *os.File
+	errFile   *os.File
+	date      string
+	logPath   string
+	formatter logrus.Formatter
+	mu        sync.Mutex
+}
+
+func (hook *Myhook) setFormatter(formatter logrus.Formatter) {
+	hook.mu.Lock()
+	hook.formatter = formatter
+	hook.mu.Unlock()
 }
 
 func (hook *Myhook) Fire(entry *logrus.Entry) error {
+	// 文件级别最低为 error，err.log 不受影响
+	if entry.Level > logrus.Level(fileLevel.Load()) {
+		return nil
+	}
+
 	hook.mu.Lock()
 	defer hook.mu.Unlock()
 
@@ -79,19 +197,19 @@ func (hook *Myhook) Fire(entry *logrus.Entry) error {
 		hook.date = date
 	}
 
-	msg, err := entry.String()
+	msg, err := hook.formatter.Format(entry)
 	if err != nil {
 		return fmt.Errorf("序列化日志失败: %w", err)
 	}
 
 	if hook.file != nil {
-		if _, err := hook.file.Write([]byte(msg)); err != nil {
+		if _, err := hook.file.Write(msg); err != nil {
 			return fmt.Errorf("写入info.log失败: %w", err)
 		}
 	}
 
 	if entry.Level <= logrus.ErrorLevel && hook.errFile != nil {
-		if _, err := hook.errFile.Write([]byte(msg)); err != nil {
+		if _, err := hook.errFile.Write(msg); err != nil {
 			return fmt.Errorf("写入err.log失败: %w", err)
 		}
 	}
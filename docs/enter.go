@@ -0,0 +1,4 @@
+// Package docs 根据路由表和请求/响应类型生成 OpenAPI 3 文档
+package docs
+
+//go:generate go run ./gen
@@ -0,0 +1,677 @@
+// Code generated by docs/gen; DO NOT EDIT.
+
+package docs
+
+// fieldDocs 结构体及字段注释，key: "包路径.类型" 或 "包路径.类型.字段"
+var fieldDocs = map[string]string{
+	"linkstar/api/accesslog_api.AccessLogApi":                                "AccessLogApi 访问日志查询接口（v2 风格）",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.DeviceID":              "设备ID",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.From":                  "开始时间（RFC3339），默认结束时间前 24 小时",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.IP":                    "客户端 IP",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.Limit":                 "最多返回条数，默认 100",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.ServiceID":             "服务ID",
+	"linkstar/api/accesslog_api.AccessLogQueryRequest.To":                    "结束时间（RFC3339），默认当前时间",
+	"linkstar/api/acme_api.AcmeApi":                                          "AcmeApi ACME 证书申请接口（v2 风格）",
+	"linkstar/api/acme_api.OrderUpdateRequest.ID":                            "申请ID",
+	"linkstar/api/acme_api.OrderUriRequest.ID":                               "申请ID",
+	"linkstar/api/cert_api.CertApi":                                          "CertApi 证书管理接口（v2 风格）",
+	"linkstar/api/cert_api.CertGenerateRequest.Days":                         "有效期（天），0 为 365 天",
+	"linkstar/api/cert_api.CertGenerateRequest.Domains":                      "域名或 IP，第一个作为 CN",
+	"linkstar/api/cert_api.CertGenerateRequest.Name":                         "名称",
+	"linkstar/api/cert_api.CertGenerateRequest.Source":                       "selfsigned 自签名 / localca 本地 CA 签发",
+	"linkstar/api/cert_api.CertReplaceRequest.Cert":                          "证书 PEM",
+	"linkstar/api/cert_api.CertReplaceRequest.ID":                            "证书ID",
+	"linkstar/api/cert_api.CertReplaceRequest.Key":                           "私钥 PEM",
+	"linkstar/api/cert_api.CertUploadRequest.Cert":                           "证书 PEM，可包含中间证书链",
+	"linkstar/api/cert_api.CertUploadRequest.Key":                            "私钥 PEM",
+	"linkstar/api/cert_api.CertUploadRequest.Name":                           "名称",
+	"linkstar/api/cert_api.CertUriRequest.ID":                                "证书ID",
+	"linkstar/api/ddns_api.DdnsApi":                                          "DdnsApi DDNS 管理接口（v2 风格）",
+	"linkstar/api/ddns_api.DomainUpdateRequest.ID":                           "域名ID",
+	"linkstar/api/ddns_api.DomainUriRequest.ID":                              "域名ID",
+	"linkstar/api/ddns_api.ProviderUpdateRequest.ID":                         "服务商ID",
+	"linkstar/api/ddns_api.ProviderUriRequest.ID":                            "服务商ID",
+	"linkstar/api/dns_api.DnsApi":                                            "DnsApi 内置权威 DNS 接口（v2 风格）",
+	"linkstar/api/firewall_api.BanClearResponse.Cleared":                     "解除的封禁数量",
+	"linkstar/api/firewall_api.BanUriRequest.IP":                             "被封禁的地址",
+	"linkstar/api/firewall_api.FirewallApi":                                  "FirewallApi 访问控制与自动封禁接口（v2 风格）",
+	"linkstar/api/limit_api.LimitApi":                                        "LimitApi 全局连接数与带宽限制接口（v2 风格）",
+	"linkstar/api/log_api.LogApi":                                            "LogApi 运行日志接口（v2 风格）",
+	"linkstar/api/log_api.LogLevelUpdateRequest.Console":                     "控制台级别，为空不修改",
+	"linkstar/api/log_api.LogLevelUpdateRequest.File":                        "日志文件级别，为空不修改",
+	"linkstar/api/metrics_api.MetricsApi":                                    "MetricsApi Prometheus 指标接口",
+	"linkstar/api/redirect_api.GoRequest.Device":                             "设备名称或设备ID，同名服务分布在多个设备上时必填",
+	"linkstar/api/redirect_api.GoRequest.Name":                               "服务名称，忽略大小写，空格可写作 -",
+	"linkstar/api/redirect_api.GoRequest.Token":                              "分享令牌（配置了 shareToken 时必填）",
+	"linkstar/api/redirect_api.RedirectApi":                                  "RedirectApi 固定地址跳转（/go 下，不属于 /api）",
+	"linkstar/api/srv_api.SrvApi":                                            "SrvApi SRV 记录管理接口（v2 风格）",
+	"linkstar/api/srv_api.SrvSetRequest.DeviceID":                            "设备ID",
+	"linkstar/api/srv_api.SrvSetRequest.ServiceID":                           "服务ID",
+	"linkstar/api/srv_api.SrvUriRequest.DeviceID":                            "设备ID",
+	"linkstar/api/srv_api.SrvUriRequest.ServiceID":                           "服务ID",
+	"linkstar/api/stun_api.StunDeviceActionViewRequest.Action":               "操作，对设备下所有服务执行",
+	"linkstar/api/stun_api.StunDeviceActionViewRequest.DeviceID":             "设备ID",
+	"linkstar/api/stun_api.StunDeviceAddViewRequest.IP":                      "设备内网 IP",
+	"linkstar/api/stun_api.StunDeviceAddViewRequest.Name":                    "设备名称，如 \"群晖NAS\" / \"树莓派\"",
+	"linkstar/api/stun_api.StunDeviceDeleteViewRequest.DeviceID":             "设备ID",
+	"linkstar/api/stun_api.StunDeviceUpdateViewRequest.DeviceID":             "设备ID",
+	"linkstar/api/stun_api.StunDeviceUpdateViewRequest.IP":                   "设备内网 IP",
+	"linkstar/api/stun_api.StunDeviceUpdateViewRequest.Name":                 "设备名称",
+	"linkstar/api/stun_api.StunEventsViewRequest.LastEventID":                "最后收到的事件ID，优先使用 Last-Event-ID 请求头",
+	"linkstar/api/stun_api.StunServiceActionViewRequest.Action":              "操作：start 启用并启动 / stop 停止并禁用 / restart 重启 / repunch 重新打洞",
+	"linkstar/api/stun_api.StunServiceActionViewRequest.DeviceID":            "设备ID",
+	"linkstar/api/stun_api.StunServiceActionViewRequest.ServiceID":           "服务ID",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.AcceptProxyProtocol":    "入站连接带 PROXY protocol 头（位于其他中继之后）",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Allow":                  "允许的客户端 CIDR，非空时只接受其中的地址",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.CertID":                 "使用的证书，0 为自动生成的自签名证书",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Deny":                   "禁止的客户端 CIDR",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Description":            "服务描述信息 (可选)",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.DeviceID":               "设备ID",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Enabled":                "服务是否启用 (默认 true)",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.HTTPRedirect":           "明文 HTTP 请求 301 跳转到 https",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.InternalPort":           "内网端口,如 22；http-proxy / sni-proxy / mux 服务可选，作为默认目标",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Limits":                 "并发连接、新建速率与带宽限制",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Mux":                    "mux 服务的协议分流配置",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Name":                   "服务名称,如 \"SSH\" / \"Web管理\" / \"照片库\"",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Probe":                  "保存前探测内网目标是否在监听，未监听时返回警告",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Protocol":               "传输协议 \"TCP\"/\"UDP\" (默认 TCP)",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.ProxyProtocol":          "连接内网目标时发送的 PROXY protocol 版本，为空不发送",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.TLS":                    "证书",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.TLSTerminate":           "在打洞端口上终止 TLS，以明文转发给内网目标",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.Type":                   "服务类型 forward 端口转发（默认）/ http-proxy HTTP 反向代理 / sni-proxy TLS 透传 / mux 协议分流",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.UPnPMappedPort":         "UPnP 实际映射成功的端口号",
+	"linkstar/api/stun_api.StunServiceAddViewRequest.UseUPnP":                "是否启用 UPnP 自动端口映射 (默认 true)",
+	"linkstar/api/stun_api.StunServiceBulkActionViewRequest.Action":          "操作",
+	"linkstar/api/stun_api.StunServiceBulkActionViewRequest.Services":        "目标服务列表",
+	"linkstar/api/stun_api.StunServiceDeleteViewRequest.DeviceID":            "设备ID",
+	"linkstar/api/stun_api.StunServiceDeleteViewRequest.ServiceID":           "服务ID",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.AcceptProxyProtocol": "入站连接带 PROXY protocol 头（位于其他中继之后）",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Allow":               "允许的客户端 CIDR，非空时只接受其中的地址",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.CertID":              "使用的证书，0 为自动生成的自签名证书",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Deny":                "禁止的客户端 CIDR",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Description":         "服务描述信息 (可选)",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.DeviceID":            "设备ID",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Enabled":             "服务是否启用",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.HTTPRedirect":        "明文 HTTP 请求 301 跳转到 https",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.InternalPort":        "内网端口，http-proxy / sni-proxy / mux 服务可选",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Limits":              "并发连接、新建速率与带宽限制",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Mux":                 "mux 服务的协议分流配置",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Name":                "服务名称",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Probe":               "保存前探测内网目标是否在监听，未监听时返回警告",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Protocol":            "传输协议 \"TCP\"/\"UDP\"",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.ProxyProtocol":       "连接内网目标时发送的 PROXY protocol 版本，为空不发送",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.ServiceID":           "服务ID",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.TLS":                 "证书",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.TLSTerminate":        "在打洞端口上终止 TLS，以明文转发给内网目标",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.Type":                "服务类型 forward / http-proxy / sni-proxy / mux",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.UPnPMappedPort":      "UPnP 实际映射成功的端口号",
+	"linkstar/api/stun_api.StunServiceUpdateViewRequest.UseUPnP":             "是否启用 UPnP 自动端口映射",
+	"linkstar/api/stun_v2_api.BulkActionRequest.Action":                      "操作",
+	"linkstar/api/stun_v2_api.BulkActionRequest.Services":                    "目标服务列表",
+	"linkstar/api/stun_v2_api.ConnIPRequest.IP":                              "客户端地址",
+	"linkstar/api/stun_v2_api.ConnKillResponse.Killed":                       "断开的连接数",
+	"linkstar/api/stun_v2_api.ConnUriRequest.ConnID":                         "连接ID",
+	"linkstar/api/stun_v2_api.ConnUriRequest.DeviceID":                       "设备ID",
+	"linkstar/api/stun_v2_api.ConnUriRequest.ServiceID":                      "服务ID",
+	"linkstar/api/stun_v2_api.DeviceActionRequest.Action":                    "操作，对设备下所有服务执行",
+	"linkstar/api/stun_v2_api.DeviceActionRequest.DeviceID":                  "设备ID",
+	"linkstar/api/stun_v2_api.DeviceCreateRequest.IP":                        "设备内网 IP",
+	"linkstar/api/stun_v2_api.DeviceCreateRequest.Name":                      "设备名称，如 \"群晖NAS\" / \"树莓派\"",
+	"linkstar/api/stun_v2_api.DeviceUpdateRequest.DeviceID":                  "设备ID",
+	"linkstar/api/stun_v2_api.DeviceUpdateRequest.IP":                        "设备内网 IP",
+	"linkstar/api/stun_v2_api.DeviceUpdateRequest.Name":                      "设备名称",
+	"linkstar/api/stun_v2_api.DeviceUriRequest.DeviceID":                     "设备ID",
+	"linkstar/api/stun_v2_api.RouteCreateRequest.DeviceID":                   "设备ID",
+	"linkstar/api/stun_v2_api.RouteCreateRequest.ServiceID":                  "服务ID",
+	"linkstar/api/stun_v2_api.RouteUpdateRequest.DeviceID":                   "设备ID",
+	"linkstar/api/stun_v2_api.RouteUpdateRequest.RouteID":                    "路由ID",
+	"linkstar/api/stun_v2_api.RouteUpdateRequest.ServiceID":                  "服务ID",
+	"linkstar/api/stun_v2_api.RouteUriRequest.DeviceID":                      "设备ID",
+	"linkstar/api/stun_v2_api.RouteUriRequest.RouteID":                       "路由ID",
+	"linkstar/api/stun_v2_api.RouteUriRequest.ServiceID":                     "服务ID",
+	"linkstar/api/stun_v2_api.ServiceActionRequest.Action":                   "操作：start 启用并启动 / stop 停止并禁用 / restart 重启 / repunch 重新打洞",
+	"linkstar/api/stun_v2_api.ServiceActionRequest.DeviceID":                 "设备ID",
+	"linkstar/api/stun_v2_api.ServiceActionRequest.ServiceID":                "服务ID",
+	"linkstar/api/stun_v2_api.ServiceCreateRequest.DeviceID":                 "设备ID",
+	"linkstar/api/stun_v2_api.ServiceCreateRequest.Probe":                    "保存前探测内网目标是否在监听，未监听时返回警告",
+	"linkstar/api/stun_v2_api.ServiceLimitsUpdateRequest.DeviceID":           "设备ID",
+	"linkstar/api/stun_v2_api.ServiceLimitsUpdateRequest.ServiceID":          "服务ID",
+	"linkstar/api/stun_v2_api.ServiceResponse":                               "ServiceResponse 新增/修改服务的响应",
+	"linkstar/api/stun_v2_api.ServiceResponse.Warnings":                      "探测警告",
+	"linkstar/api/stun_v2_api.ServiceUpdateRequest.DeviceID":                 "设备ID",
+	"linkstar/api/stun_v2_api.ServiceUpdateRequest.Probe":                    "保存前探测内网目标是否在监听，未监听时返回警告",
+	"linkstar/api/stun_v2_api.ServiceUpdateRequest.ServiceID":                "服务ID",
+	"linkstar/api/stun_v2_api.ServiceUriRequest.DeviceID":                    "设备ID",
+	"linkstar/api/stun_v2_api.ServiceUriRequest.ServiceID":                   "服务ID",
+	"linkstar/api/stun_v2_api.StunV2Api":                                     "StunV2Api 资源风格的 v2 接口，返回真实 HTTP 状态码",
+	"linkstar/api/webhook_api.WebhookApi":                                    "WebhookApi webhook 管理接口（v2 风格）",
+	"linkstar/api/webhook_api.WebhookUpdateRequest.ID":                       "webhook ID",
+	"linkstar/api/webhook_api.WebhookUpdateRequest.RemoveSecret":             "清除签名密钥；secret 为空且不清除时保留原密钥",
+	"linkstar/api/webhook_api.WebhookUriRequest.ID":                          "webhook ID",
+	"linkstar/conf.Config":                                                   "Config 程序运行配置（config/settings.json）",
+	"linkstar/conf.Config.DNS":                                               "内置权威 DNS",
+	"linkstar/conf.Config.Debug":                                             "调试接口配置",
+	"linkstar/conf.Config.Log":                                               "运行日志",
+	"linkstar/conf.Config.Metrics":                                           "Prometheus 指标",
+	"linkstar/conf.Config.Redirect":                                          "固定地址跳转",
+	"linkstar/conf.Config.System":                                            "系统配置",
+	"linkstar/conf.DNS":                                                      "DNS 内置权威 DNS（将子域名 NS 委派给本机后，根据实时状态应答 A / SRV / TXT）",
+	"linkstar/conf.DNS.Enable":                                               "是否开启，默认关闭",
+	"linkstar/conf.DNS.Listen":                                               "监听地址（UDP + TCP），默认 \":5353\"",
+	"linkstar/conf.DNS.NS":                                                   "本机的 NS 主机名，默认 ns.<zone>",
+	"linkstar/conf.DNS.TTL":                                                  "应答 TTL（秒），默认 30",
+	"linkstar/conf.DNS.UPnP":                                                 "通过 UPnP 将外网 UDP/53 映射到监听端口",
+	"linkstar/conf.DNS.Zone":                                                 "委派给本机的子域名，如 home.example.com",
+	"linkstar/conf.Debug.Enable":                                             "是否开启 /debug 调试接口（pprof、运行时诊断），默认关闭",
+	"linkstar/conf.Log.Compress":                                             "是否 gzip 压缩之前日期的日志",
+	"linkstar/conf.Log.FileLevel":                                            "日志文件级别，默认与 level 相同",
+	"linkstar/conf.Log.Format":                                               "text（默认）或 json（每行一个 JSON 对象），控制台和文件都生效",
+	"linkstar/conf.Log.Level":                                                "控制台级别：trace / debug / info / warn / error，默认 debug",
+	"linkstar/conf.Log.MaxDays":                                              "按天保留（含当天），0 表示不按天数清理",
+	"linkstar/conf.Log.MaxSizeMB":                                            "logs 目录总大小上限（MB），超出时从最早的一天删起，0 表示不限",
+	"linkstar/conf.Metrics.Auth":                                             "是否要求携带 API token，与 /api 使用相同的鉴权方式",
+	"linkstar/conf.Metrics.Enable":                                           "是否开启 /metrics（Prometheus 文本格式），默认关闭",
+	"linkstar/conf.Redirect":                                                 "Redirect 固定地址跳转（/go/<服务名> 302 到服务当前的访问地址）",
+	"linkstar/conf.Redirect.Listen":                                          "额外的独立监听地址，如 \":8088\"，为空只在面板地址上提供",
+	"linkstar/conf.Redirect.ShareToken":                                      "分享令牌，非空时访问 /go 需携带 ?token=（API token 同样可用），为空不鉴权",
+	"linkstar/conf.Redirect.UPnPPort":                                        "通过 UPnP 将该外网 TCP 端口映射到独立监听端口，0 不映射",
+	"linkstar/conf.System.Addr":                                              "后端监听地址，如 \"0.0.0.0:3333\"",
+	"linkstar/conf.System.Token":                                             "API 访问令牌，为空表示不鉴权",
+	"linkstar/core.LogLevels":                                                "LogLevels 当前控制台与日志文件级别",
+	"linkstar/core.LogLevels.Console":                                        "控制台级别",
+	"linkstar/core.LogLevels.File":                                           "日志文件级别",
+	"linkstar/core.MyLog":                                                    "MyLog 文本格式，NoColor 为 true 时不输出颜色（写文件时使用）",
+	"linkstar/core.consoleFormatter":                                         "consoleFormatter 低于控制台级别的日志格式化为空，logrus 写出 0 字节",
+	"linkstar/flags.Options.File":                                            "配置文件路径",
+	"linkstar/modules/accesslog.Filter":                                      "Filter 查询条件，零值表示不限",
+	"linkstar/modules/accesslog.Filter.DeviceID":                             "设备ID",
+	"linkstar/modules/accesslog.Filter.From":                                 "关闭时间范围，默认最近 24 小时",
+	"linkstar/modules/accesslog.Filter.IP":                                   "客户端 IP",
+	"linkstar/modules/accesslog.Filter.Limit":                                "最多返回条数，默认 100",
+	"linkstar/modules/accesslog.Filter.ServiceID":                            "服务ID",
+	"linkstar/modules/accesslog.Filter.To":                                   "关闭时间范围，默认最近 24 小时",
+	"linkstar/modules/accesslog.Record":                                      "Record 一个转发连接的访问记录，连接关闭时写入",
+	"linkstar/modules/accesslog.Record.BytesIn":                              "从客户端收到的字节",
+	"linkstar/modules/accesslog.Record.BytesOut":                             "发往客户端的字节",
+	"linkstar/modules/accesslog.Record.CloseReason":                          "关闭原因",
+	"linkstar/modules/accesslog.Record.Device":                               "设备名称",
+	"linkstar/modules/accesslog.Record.DeviceID":                             "设备ID",
+	"linkstar/modules/accesslog.Record.DurationMs":                           "持续时间（毫秒）",
+	"linkstar/modules/accesslog.Record.RemoteAddr":                           "客户端地址",
+	"linkstar/modules/accesslog.Record.Service":                              "服务名称",
+	"linkstar/modules/accesslog.Record.ServiceID":                            "服务ID",
+	"linkstar/modules/accesslog.Record.StartedAt":                            "连接建立时间",
+	"linkstar/modules/accesslog.Record.Target":                               "内网目标，http-proxy 为最后一次请求的目标",
+	"linkstar/modules/accesslog.Record.Time":                                 "连接关闭时间",
+	"linkstar/modules/acme.Config":                                           "Config ACME 配置文件（config/acme.json），签发的证书保存在证书管理中",
+	"linkstar/modules/acme.Config.Orders":                                    "证书申请",
+	"linkstar/modules/acme.Config.Settings":                                  "账户与 CA 设置",
+	"linkstar/modules/acme.Order":                                            "Order 证书申请",
+	"linkstar/modules/acme.Order.CertID":                                     "签发后保存在证书管理中的证书ID，续期时原地替换",
+	"linkstar/modules/acme.Order.ID":                                         "申请ID",
+	"linkstar/modules/acme.OrderInfo":                                        "OrderInfo 证书申请及其状态",
+	"linkstar/modules/acme.OrderSpec":                                        "OrderSpec 证书申请中由用户配置的部分",
+	"linkstar/modules/acme.OrderSpec.Domains":                                "域名，支持 *.example.com",
+	"linkstar/modules/acme.OrderSpec.Enabled":                                "是否自动申请与续期",
+	"linkstar/modules/acme.OrderSpec.Name":                                   "证书名称",
+	"linkstar/modules/acme.OrderSpec.ProviderID":                             "写入 _acme-challenge TXT 记录的 DNS 服务商（DDNS 中配置）",
+	"linkstar/modules/acme.OrderSpec.Zone":                                   "主域名，如 example.com",
+	"linkstar/modules/acme.OrderStatus":                                      "OrderStatus 证书申请的运行状态，不持久化",
+	"linkstar/modules/acme.OrderStatus.LastAttemptAt":                        "上次申请时间",
+	"linkstar/modules/acme.OrderStatus.LastError":                            "上次失败原因",
+	"linkstar/modules/acme.OrderStatus.LastSuccessAt":                        "上次签发成功时间",
+	"linkstar/modules/acme.OrderStatus.NextRenewAt":                          "计划续期时间",
+	"linkstar/modules/acme.OrderStatus.NotAfter":                             "当前证书到期时间",
+	"linkstar/modules/acme.OrderStatus.State":                                "idle / issuing / valid / failed",
+	"linkstar/modules/acme.Settings":                                         "Settings ACME 账户与 CA 设置",
+	"linkstar/modules/acme.Settings.DirectoryURL":                            "ACME 目录地址，为空使用 Let's Encrypt 正式环境",
+	"linkstar/modules/acme.Settings.Email":                                   "账户联系邮箱",
+	"linkstar/modules/acme.Settings.PropagationWait":                         "写入 TXT 记录后等待生效的秒数，0 为 30 秒",
+	"linkstar/modules/acme.Settings.RenewBeforeDays":                         "到期前多少天续期，0 为 30 天",
+	"linkstar/modules/acme.Settings.SkipTLSVerify":                           "不校验 ACME 服务器证书（仅用于本地 Pebble 测试）",
+	"linkstar/modules/acme.invalidFieldError":                                "invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true",
+	"linkstar/modules/certs.Cert":                                            "Cert 证书信息",
+	"linkstar/modules/certs.Cert.Domains":                                    "证书包含的域名与 IP",
+	"linkstar/modules/certs.Cert.Fingerprint":                                "SHA-256 指纹",
+	"linkstar/modules/certs.Cert.ID":                                         "证书ID",
+	"linkstar/modules/certs.Cert.Issuer":                                     "签发者",
+	"linkstar/modules/certs.Cert.Name":                                       "名称",
+	"linkstar/modules/certs.Cert.NotAfter":                                   "过期时间",
+	"linkstar/modules/certs.Cert.NotBefore":                                  "生效时间",
+	"linkstar/modules/certs.Cert.Source":                                     "来源：upload / selfsigned / localca / acme",
+	"linkstar/modules/certs.Config":                                          "Config 证书配置文件（config/certs.json），证书与私钥保存在 config/certs/<id>.crt / <id>.key",
+	"linkstar/modules/ddns.Config":                                           "Config DDNS 配置文件（config/ddns.json）",
+	"linkstar/modules/ddns.Config.Domains":                                   "跟随公网IP更新的域名",
+	"linkstar/modules/ddns.Config.Providers":                                 "DNS 服务商账号",
+	"linkstar/modules/ddns.Domain":                                           "Domain 跟随公网IP更新 A 记录的域名",
+	"linkstar/modules/ddns.Domain.ID":                                        "域名ID",
+	"linkstar/modules/ddns.DomainInfo":                                       "DomainInfo 域名及其同步状态",
+	"linkstar/modules/ddns.DomainInfo.Status":                                "同步状态",
+	"linkstar/modules/ddns.DomainSpec":                                       "DomainSpec 域名中由用户配置的部分",
+	"linkstar/modules/ddns.DomainSpec.Enabled":                               "是否启用",
+	"linkstar/modules/ddns.DomainSpec.MinInterval":                           "两次更新的最小间隔（秒），0 为默认 60 秒，期间的变化合并为一次更新",
+	"linkstar/modules/ddns.DomainSpec.Name":                                  "完整域名，如 home.example.com，与主域名相同表示根域名",
+	"linkstar/modules/ddns.DomainSpec.ProviderID":                            "使用的 DNS 服务商",
+	"linkstar/modules/ddns.DomainSpec.TTL":                                   "TTL（秒），0 使用服务商默认值",
+	"linkstar/modules/ddns.DomainSpec.Zone":                                  "主域名，如 example.com",
+	"linkstar/modules/ddns.DomainStatus":                                     "DomainStatus 域名同步状态（仅保存在内存中）",
+	"linkstar/modules/ddns.DomainStatus.IP":                                  "最近一次成功写入的 IP",
+	"linkstar/modules/ddns.DomainStatus.LastAttemptAt":                       "最近一次请求服务商的时间",
+	"linkstar/modules/ddns.DomainStatus.LastError":                           "最近一次失败原因，成功后清空",
+	"linkstar/modules/ddns.DomainStatus.LastSuccessAt":                       "最近一次成功的时间",
+	"linkstar/modules/ddns.DomainStatus.NextUpdateAt":                        "下次更新时间",
+	"linkstar/modules/ddns.DomainStatus.Pending":                             "受最小间隔限制，等待下次更新",
+	"linkstar/modules/ddns.DomainStatus.Updates":                             "成功更新次数",
+	"linkstar/modules/ddns.ProviderConfig":                                   "ProviderConfig DNS 服务商账号",
+	"linkstar/modules/ddns.ProviderConfig.ID":                                "服务商ID",
+	"linkstar/modules/ddns.ProviderSpec":                                     "ProviderSpec 服务商中由用户配置的部分，不同类型使用不同的凭据字段",
+	"linkstar/modules/ddns.ProviderSpec.AccessKeyID":                         "阿里云 AccessKey ID",
+	"linkstar/modules/ddns.ProviderSpec.AccessKeySecret":                     "阿里云 AccessKey Secret",
+	"linkstar/modules/ddns.ProviderSpec.Body":                                "url 类型：请求体模板",
+	"linkstar/modules/ddns.ProviderSpec.Endpoint":                            "覆盖 API 地址，为空使用官方地址（可指向本地模拟服务）",
+	"linkstar/modules/ddns.ProviderSpec.Method":                              "url 类型：请求方法，默认 GET",
+	"linkstar/modules/ddns.ProviderSpec.Name":                                "名称",
+	"linkstar/modules/ddns.ProviderSpec.Token":                               "Cloudflare API Token / DNSPod Token",
+	"linkstar/modules/ddns.ProviderSpec.TokenID":                             "DNSPod Token ID",
+	"linkstar/modules/ddns.ProviderSpec.Type":                                "类型",
+	"linkstar/modules/ddns.ProviderSpec.URL":                                 "url 类型：请求地址模板，如 https://x/update?host={{.Name}}&ip={{.Value}}",
+	"linkstar/modules/ddns.Record":                                           "Record 一条 DNS 记录",
+	"linkstar/modules/ddns.Record.Name":                                      "完整域名，如 home.example.com",
+	"linkstar/modules/ddns.Record.TTL":                                       "秒，0 使用服务商默认值",
+	"linkstar/modules/ddns.Record.Type":                                      "A / SRV / TXT",
+	"linkstar/modules/ddns.Record.Value":                                     "A 为 IP，TXT 为文本，SRV 为目标主机",
+	"linkstar/modules/ddns.Record.Zone":                                      "主域名，如 example.com",
+	"linkstar/modules/ddns.aliDNS":                                           "aliDNS 阿里云解析 OpenAPI（RPC 风格，HMAC-SHA1 签名）",
+	"linkstar/modules/ddns.cloudflare":                                       "cloudflare Cloudflare API v4，使用 API Token 鉴权",
+	"linkstar/modules/ddns.dnsPod":                                           "dnsPod DNSPod API（dnsapi.cn），使用 login_token = \"ID,Token\" 鉴权",
+	"linkstar/modules/ddns.domainState.mu":                                   "串行化同一域名的更新",
+	"linkstar/modules/ddns.invalidFieldError":                                "invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true",
+	"linkstar/modules/ddns.urlTemplate":                                      "urlTemplate 通用 URL 模板，适配花生壳、DuckDNS、自建接口等 URL 与 Body 均为 Go text/template，数据为 urlTemplateData，例如：  \thttps://www.duckdns.org/update?domains={{.RR}}&token=xxx&ip={{.Value}}  SRV 记录的 Content 含空格，放入 URL 时使用 {{urlquery .Content}}",
+	"linkstar/modules/ddns.urlTemplateData.Action":                           "upsert / delete",
+	"linkstar/modules/ddns.urlTemplateData.Content":                          "记录值，SRV 为 \"优先级 权重 端口 目标\"",
+	"linkstar/modules/ddns.urlTemplateData.RR":                               "主机记录",
+	"linkstar/modules/dnsserver.Record":                                      "Record 当前可应答的一条记录",
+	"linkstar/modules/dnsserver.Record.Name":                                 "完整域名",
+	"linkstar/modules/dnsserver.Record.TTL":                                  "TTL（秒）",
+	"linkstar/modules/dnsserver.Record.Type":                                 "A / SRV / TXT / NS / SOA",
+	"linkstar/modules/dnsserver.Record.Value":                                "A 为 IP，SRV 为 \"优先级 权重 端口 目标\"，NS 为主机名，TXT 为文本",
+	"linkstar/modules/dnsserver.Stats":                                       "Stats 查询统计",
+	"linkstar/modules/dnsserver.Stats.Answered":                              "有应答记录",
+	"linkstar/modules/dnsserver.Stats.NXDomain":                              "名称不存在",
+	"linkstar/modules/dnsserver.Stats.Queries":                               "收到的查询",
+	"linkstar/modules/dnsserver.Stats.Refused":                               "不属于本区域或格式错误",
+	"linkstar/modules/dnsserver.Status":                                      "Status 内置 DNS 运行状态",
+	"linkstar/modules/dnsserver.Status.Enabled":                              "是否开启",
+	"linkstar/modules/dnsserver.Status.LastError":                            "最近一次错误（监听、映射）",
+	"linkstar/modules/dnsserver.Status.Listen":                               "监听地址",
+	"linkstar/modules/dnsserver.Status.Mapped":                               "外网 UDP/53 与 TCP/53 是否均映射成功",
+	"linkstar/modules/dnsserver.Status.NS":                                   "NS 主机名",
+	"linkstar/modules/dnsserver.Status.StartedAt":                            "启动时间",
+	"linkstar/modules/dnsserver.Status.UPnP":                                 "是否需要 UPnP 映射",
+	"linkstar/modules/dnsserver.Status.Zone":                                 "委派的子域名",
+	"linkstar/modules/dnsserver.server.records":                              "缓存的区域记录，nil 表示需要重建",
+	"linkstar/modules/dnsserver.server.recordsMu":                            "保护以下字段",
+	"linkstar/modules/event.Bus":                                             "Bus 进程内发布/订阅总线 发布永不阻塞：订阅者缓冲写满时丢弃该订阅者的事件并计数，不影响隧道等发布方",
+	"linkstar/modules/event.Bus.history":                                     "最近的状态事件（不含 Transient 类型），用于续传",
+	"linkstar/modules/event.Bus.historyFrom":                                 "该ID及之后的状态事件都在 history 中",
+	"linkstar/modules/event.ConnectionAccepted":                              "ConnectionAccepted 收到外部连接",
+	"linkstar/modules/event.ConnectionAccepted.RemoteAddr":                   "客户端地址",
+	"linkstar/modules/event.Envelope":                                        "Envelope 带序号的事件",
+	"linkstar/modules/event.Envelope.Data":                                   "事件内容",
+	"linkstar/modules/event.Envelope.ID":                                     "递增的事件ID，SSE 断线重连时通过 Last-Event-ID 续传",
+	"linkstar/modules/event.Envelope.Time":                                   "发布时间",
+	"linkstar/modules/event.Envelope.Type":                                   "事件类型",
+	"linkstar/modules/event.GatewayChanged":                                  "GatewayChanged 默认 UPnP 网关变化",
+	"linkstar/modules/event.GatewayChanged.NewGateway":                       "新网关",
+	"linkstar/modules/event.GatewayChanged.OldGateway":                       "旧网关，如 \"IGDv2 192.168.1.1\"，未发现为空",
+	"linkstar/modules/event.IPBanned":                                        "IPBanned 地址被自动封禁",
+	"linkstar/modules/event.IPBanned.ExpiresAt":                              "解封时间",
+	"linkstar/modules/event.IPBanned.IP":                                     "被封禁的地址",
+	"linkstar/modules/event.IPBanned.Reason":                                 "封禁原因",
+	"linkstar/modules/event.MappingAdded":                                    "MappingAdded UPnP 映射成功",
+	"linkstar/modules/event.MappingAdded.ExternalPort":                       "路由器 WAN 端口",
+	"linkstar/modules/event.MappingAdded.Gateway":                            "使用的网关",
+	"linkstar/modules/event.MappingAdded.InternalPort":                       "本机端口",
+	"linkstar/modules/event.MappingFailed":                                   "MappingFailed UPnP 映射失败（非致命，打洞仍会继续）",
+	"linkstar/modules/event.MappingFailed.Error":                             "失败原因",
+	"linkstar/modules/event.MappingFailed.ExternalPort":                      "路由器 WAN 端口",
+	"linkstar/modules/event.MappingRemoved":                                  "MappingRemoved UPnP 映射删除",
+	"linkstar/modules/event.MappingRemoved.ExternalPort":                     "路由器 WAN 端口",
+	"linkstar/modules/event.MappingRemoved.Gateway":                          "使用的网关",
+	"linkstar/modules/event.PortDrift":                                       "PortDrift 公网端口漂移，服务随后会重新打洞",
+	"linkstar/modules/event.PortDrift.NewPort":                               "新公网端口",
+	"linkstar/modules/event.PortDrift.OldPort":                               "原公网端口",
+	"linkstar/modules/event.PortDrift.PublicIP":                              "公网IP",
+	"linkstar/modules/event.PublicIPChanged":                                 "PublicIPChanged 本机公网IP变化",
+	"linkstar/modules/event.PublicIPChanged.NewIP":                           "新公网IP",
+	"linkstar/modules/event.PublicIPChanged.OldIP":                           "旧公网IP，首次获取为空",
+	"linkstar/modules/event.ServiceOffline":                                  "ServiceOffline 服务下线",
+	"linkstar/modules/event.ServiceOffline.PublicAddr":                       "下线前的公网地址",
+	"linkstar/modules/event.ServiceOffline.Reason":                           "下线原因",
+	"linkstar/modules/event.ServiceOnline":                                   "ServiceOnline 服务打洞成功上线",
+	"linkstar/modules/event.ServiceOnline.OldAddr":                           "上次的公网地址，首次上线为空",
+	"linkstar/modules/event.ServiceOnline.PublicAddr":                        "公网地址 IP:端口",
+	"linkstar/modules/event.ServiceOnline.PublicURL":                         "访问地址",
+	"linkstar/modules/event.ServiceRef":                                      "ServiceRef 事件所属的服务",
+	"linkstar/modules/event.ServiceRef.DeviceID":                             "设备ID",
+	"linkstar/modules/event.ServiceRef.DeviceIP":                             "设备内网IP",
+	"linkstar/modules/event.ServiceRef.DeviceName":                           "设备名称",
+	"linkstar/modules/event.ServiceRef.Protocol":                             "协议 TCP/UDP",
+	"linkstar/modules/event.ServiceRef.ServiceID":                            "服务ID",
+	"linkstar/modules/event.ServiceRef.ServiceName":                          "服务名称",
+	"linkstar/modules/event.ServiceRef.TLS":                                  "是否为 https 服务",
+	"linkstar/modules/event.Subscription":                                    "Subscription 一个订阅",
+	"linkstar/modules/event.Subscription.C":                                  "事件通道，Close 后关闭",
+	"linkstar/modules/event.Subscription.LastID":                             "订阅时最新的事件ID",
+	"linkstar/modules/event/eventtest.Recorder":                              "Recorder 记录总线上发布的事件",
+	"linkstar/modules/event/eventtest.Recorder.notify":                       "每记录一个事件关闭并重建，唤醒等待方",
+	"linkstar/modules/firewall.AutoBan":                                      "AutoBan 自动封禁：统计窗口内连接过多或频繁快速断开（扫描、爆破）的地址临时封禁 内网与回环地址不会被自动封禁",
+	"linkstar/modules/firewall.AutoBan.BanMinutes":                           "封禁时长（分钟），0 为 60",
+	"linkstar/modules/firewall.AutoBan.Enabled":                              "是否启用",
+	"linkstar/modules/firewall.AutoBan.FailWithin":                           "建立后多少秒内断开视为失败，0 为 3",
+	"linkstar/modules/firewall.AutoBan.MaxConnections":                       "窗口内单个地址最多新建的连接数，0 不限制",
+	"linkstar/modules/firewall.AutoBan.MaxFailures":                          "窗口内单个地址最多快速断开的次数，0 不限制",
+	"linkstar/modules/firewall.AutoBan.Window":                               "统计窗口（秒），0 为 60",
+	"linkstar/modules/firewall.Ban":                                          "Ban 一条封禁记录",
+	"linkstar/modules/firewall.Ban.BannedAt":                                 "封禁时间",
+	"linkstar/modules/firewall.Ban.ExpiresAt":                                "解封时间",
+	"linkstar/modules/firewall.Ban.IP":                                       "被封禁的地址",
+	"linkstar/modules/firewall.Ban.Reason":                                   "封禁原因",
+	"linkstar/modules/firewall.Ban.Service":                                  "触发封禁的服务，如 \"NAS - SSH\"",
+	"linkstar/modules/firewall.Config":                                       "Config 防火墙配置文件（config/firewall.json），封禁记录只保存在内存中，重启后清空",
+	"linkstar/modules/firewall.Rules":                                        "Rules 服务自己的允许、禁止列表，启动服务时解析一次，避免每个连接重复解析",
+	"linkstar/modules/firewall.Settings":                                     "Settings 全局访问控制，对所有服务生效",
+	"linkstar/modules/firewall.Settings.Allow":                               "全局允许列表，非空时只接受其中的地址；服务设置了允许列表时以服务为准",
+	"linkstar/modules/firewall.Settings.AutoBan":                             "自动封禁",
+	"linkstar/modules/firewall.Settings.Deny":                                "全局禁止列表",
+	"linkstar/modules/firewall.ipStats":                                      "单个地址在统计窗口内的连接记录",
+	"linkstar/modules/firewall.ipStats.conns":                                "新建连接的时间",
+	"linkstar/modules/firewall.ipStats.failures":                             "快速断开的时间",
+	"linkstar/modules/firewall.trackedConn":                                  "trackedConn 关闭时记录连接存活时间，用于识别快速断开",
+	"linkstar/modules/limiter.Bucket":                                        "Bucket 令牌桶，容量为一秒的令牌；允许透支，透支部分由调用方等待补齐",
+	"linkstar/modules/limiter.Bucket.rate":                                   "每秒令牌数，0 不限制",
+	"linkstar/modules/limiter.Config":                                        "Config 限速配置文件（config/limits.json），服务自己的限制保存在服务配置中",
+	"linkstar/modules/limiter.Config.Global":                                 "所有服务合计的限制",
+	"linkstar/modules/limiter.Limiter":                                       "Limiter 一个作用域（全局或单个服务）的限制状态，修改限制后对已建立的连接立即生效",
+	"linkstar/modules/limiter.Limiter.active":                                "当前并发连接数",
+	"linkstar/modules/limiter.Limiter.conns":                                 "新建连接",
+	"linkstar/modules/limiter.Limiter.down":                                  "上行、下行字节",
+	"linkstar/modules/limiter.Limiter.up":                                    "上行、下行字节",
+	"linkstar/modules/limiter.Limits":                                        "Limits 一组限制，0 表示不限制",
+	"linkstar/modules/limiter.Limits.ConnRate":                               "每秒最多新建连接数",
+	"linkstar/modules/limiter.Limits.Download":                               "从外部客户端接收的带宽（KB/s）",
+	"linkstar/modules/limiter.Limits.MaxConnections":                         "最大并发连接数",
+	"linkstar/modules/limiter.Limits.Upload":                                 "发往外部客户端的带宽（KB/s），占用宽带上行",
+	"linkstar/modules/limiter.Status":                                        "Status 限制及当前使用情况",
+	"linkstar/modules/limiter.Status.Connections":                            "当前并发连接数",
+	"linkstar/modules/limiter.limitedConn":                                   "limitedConn 按服务和全局带宽限速的连接，关闭时归还连接名额",
+	"linkstar/modules/limiter.limitedConn.down":                              "各 Limiter 的上行、下行令牌桶",
+	"linkstar/modules/limiter.limitedConn.up":                                "各 Limiter 的上行、下行令牌桶",
+	"linkstar/modules/metrics.CounterVec":                                    "CounterVec 带标签的计数器",
+	"linkstar/modules/metrics.HistogramVec":                                  "HistogramVec 带标签的直方图",
+	"linkstar/modules/metrics.Writer":                                        "Writer 按 Prometheus 文本格式输出",
+	"linkstar/modules/metrics.histSeries":                                    "直方图的一条时间序列",
+	"linkstar/modules/metrics.histSeries.counts":                             "与 buckets 对应的非累计计数",
+	"linkstar/modules/metrics.series":                                        "一组标签值对应的一条时间序列",
+	"linkstar/modules/redirect.Target":                                       "Target 跳转目标",
+	"linkstar/modules/redirect.Target.Online":                                "是否在线，不在线时 publicURL 为空",
+	"linkstar/modules/redirect.ambiguousError":                               "ambiguousError 同名服务分布在多个设备上",
+	"linkstar/modules/srv.Config":                                            "Config SRV 配置文件（config/srv.json）",
+	"linkstar/modules/srv.Config.Published":                                  "已写入 DNS 的记录",
+	"linkstar/modules/srv.Config.Records":                                    "每个服务至多一条",
+	"linkstar/modules/srv.Published":                                         "Published 已写入 DNS 的记录，持久化以便配置修改、删除或重启后清理旧记录",
+	"linkstar/modules/srv.Published.DeviceID":                                "设备ID",
+	"linkstar/modules/srv.Published.Name":                                    "记录名，如 _minecraft._tcp.mc.example.com",
+	"linkstar/modules/srv.Published.ProviderID":                              "发布时使用的 DNS 服务商",
+	"linkstar/modules/srv.Published.ServiceID":                               "服务ID",
+	"linkstar/modules/srv.Published.TXT":                                     "是否同时发布了 TXT 记录",
+	"linkstar/modules/srv.Published.Zone":                                    "主域名",
+	"linkstar/modules/srv.Record":                                            "Record 服务的 SRV 记录配置",
+	"linkstar/modules/srv.Record.DeviceID":                                   "设备ID",
+	"linkstar/modules/srv.Record.ServiceID":                                  "服务ID",
+	"linkstar/modules/srv.RecordInfo":                                        "RecordInfo SRV 记录及其发布状态",
+	"linkstar/modules/srv.RecordInfo.Status":                                 "发布状态",
+	"linkstar/modules/srv.RecordSpec":                                        "RecordSpec SRV 记录中由用户配置的部分 发布的记录名为 _<Service>._<Proto>.<Name>，如 _minecraft._tcp.mc.example.com",
+	"linkstar/modules/srv.RecordSpec.Enabled":                                "是否启用，停用后删除已发布的记录",
+	"linkstar/modules/srv.RecordSpec.Name":                                   "记录所属域名，如 mc.example.com",
+	"linkstar/modules/srv.RecordSpec.Priority":                               "优先级",
+	"linkstar/modules/srv.RecordSpec.Proto":                                  "传输协议，为空跟随服务协议",
+	"linkstar/modules/srv.RecordSpec.ProviderID":                             "使用的 DNS 服务商（DDNS 中配置）",
+	"linkstar/modules/srv.RecordSpec.Service":                                "服务名，如 minecraft、sip、xmpp-client",
+	"linkstar/modules/srv.RecordSpec.TTL":                                    "TTL（秒），0 使用服务商默认值",
+	"linkstar/modules/srv.RecordSpec.TXT":                                    "同时在同名 TXT 记录中发布完整访问地址",
+	"linkstar/modules/srv.RecordSpec.Target":                                 "目标主机名，需解析到公网IP（通常为 DDNS 域名），为空使用 Name",
+	"linkstar/modules/srv.RecordSpec.Weight":                                 "权重",
+	"linkstar/modules/srv.RecordSpec.Zone":                                   "主域名，如 example.com",
+	"linkstar/modules/srv.RecordStatus":                                      "RecordStatus 发布状态（仅保存在内存中）",
+	"linkstar/modules/srv.RecordStatus.LastError":                            "最近一次失败原因，成功后清空",
+	"linkstar/modules/srv.RecordStatus.LastSyncAt":                           "最近一次成功发布的时间",
+	"linkstar/modules/srv.RecordStatus.Name":                                 "已发布的记录名",
+	"linkstar/modules/srv.RecordStatus.Online":                               "服务是否在线（已获得公网端口）",
+	"linkstar/modules/srv.RecordStatus.Port":                                 "已发布的端口",
+	"linkstar/modules/srv.RecordStatus.Published":                            "DNS 上是否有本程序发布的记录",
+	"linkstar/modules/srv.RecordStatus.Target":                               "已发布的目标主机",
+	"linkstar/modules/srv.RecordStatus.URL":                                  "已发布的 TXT 内容，未开启 TXT 为空",
+	"linkstar/modules/srv.endpoint":                                          "endpoint 服务当前的公网端点",
+	"linkstar/modules/srv.invalidFieldError":                                 "invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true",
+	"linkstar/modules/stun.ActionResult":                                     "ActionResult 单个服务的操作结果",
+	"linkstar/modules/stun.ActionResult.DeviceID":                            "设备ID",
+	"linkstar/modules/stun.ActionResult.Error":                               "失败原因",
+	"linkstar/modules/stun.ActionResult.ServiceID":                           "服务ID",
+	"linkstar/modules/stun.ActionResult.Success":                             "是否成功",
+	"linkstar/modules/stun.ActiveConn":                                       "ActiveConn 一个正在转发的外部连接",
+	"linkstar/modules/stun.ActiveConn.BytesIn":                               "从客户端收到的字节",
+	"linkstar/modules/stun.ActiveConn.BytesOut":                              "发往客户端的字节",
+	"linkstar/modules/stun.ActiveConn.DeviceID":                              "设备ID",
+	"linkstar/modules/stun.ActiveConn.DeviceName":                            "设备名称",
+	"linkstar/modules/stun.ActiveConn.ID":                                    "连接ID，进程内唯一",
+	"linkstar/modules/stun.ActiveConn.RemoteAddr":                            "客户端地址（经 PROXY protocol 还原后的真实地址）",
+	"linkstar/modules/stun.ActiveConn.ServiceID":                             "服务ID",
+	"linkstar/modules/stun.ActiveConn.ServiceName":                           "服务名称",
+	"linkstar/modules/stun.ActiveConn.StartedAt":                             "建立时间",
+	"linkstar/modules/stun.ActiveConn.Target":                                "内网目标，http-proxy 为最近一次请求的目标，尚未选出时为空",
+	"linkstar/modules/stun.GoroutineSummary":                                 "GoroutineSummary goroutine 统计",
+	"linkstar/modules/stun.GoroutineSummary.Services":                        "每个服务 key 下的 goroutine 数",
+	"linkstar/modules/stun.GoroutineSummary.Total":                           "进程 goroutine 总数",
+	"linkstar/modules/stun.GoroutineSummary.Unlabeled":                       "不属于任何服务的 goroutine",
+	"linkstar/modules/stun.OnlineService":                                    "OnlineService 打洞成功且运行中的服务",
+	"linkstar/modules/stun.OnlineService.DeviceID":                           "设备ID",
+	"linkstar/modules/stun.OnlineService.DeviceName":                         "设备名称",
+	"linkstar/modules/stun.OnlineService.Protocol":                           "传输协议",
+	"linkstar/modules/stun.OnlineService.PublicAddr":                         "公网地址 IP:端口",
+	"linkstar/modules/stun.OnlineService.PublicURL":                          "访问地址",
+	"linkstar/modules/stun.OnlineService.ServiceID":                          "服务ID",
+	"linkstar/modules/stun.OnlineService.ServiceName":                        "服务名称",
+	"linkstar/modules/stun.PublicIPInfo.LocalIP":                             "本机内网IP",
+	"linkstar/modules/stun.PublicIPInfo.PublicIP":                            "真实公网IP",
+	"linkstar/modules/stun.RunningService":                                   "RunningService 正在运行的服务",
+	"linkstar/modules/stun.RunningService.Key":                               "\"deviceID-serviceID\"",
+	"linkstar/modules/stun.RunningService.StartedAt":                         "本次启动时间",
+	"linkstar/modules/stun.ServiceTarget":                                    "ServiceTarget 批量操作的目标服务",
+	"linkstar/modules/stun.ServiceTarget.DeviceID":                           "设备ID",
+	"linkstar/modules/stun.ServiceTarget.ServiceID":                          "服务ID",
+	"linkstar/modules/stun.StatusSnapshot":                                   "StatusSnapshot 全量状态快照",
+	"linkstar/modules/stun.StatusSnapshot.Config":                            "当前配置（含服务状态）",
+	"linkstar/modules/stun.StatusSnapshot.Running":                           "运行中的服务",
+	"linkstar/modules/stun.StatusSnapshot.Traffic":                           "各服务的流量统计",
+	"linkstar/modules/stun.StunBinding":                                      "StunBinding 一个服务当前占用的 STUN 套接字绑定",
+	"linkstar/modules/stun.StunBinding.BoundAt":                              "绑定时间",
+	"linkstar/modules/stun.StunBinding.Key":                                  "\"deviceID-serviceID\"",
+	"linkstar/modules/stun.StunBinding.LocalAddr":                            "本机复用端口地址",
+	"linkstar/modules/stun.StunBinding.Protocol":                             "tcp / udp",
+	"linkstar/modules/stun.StunBinding.PublicAddr":                           "STUN 映射出的公网地址",
+	"linkstar/modules/stun.StunBinding.StunServer":                           "使用的 STUN 服务器",
+	"linkstar/modules/stun.UpnpQueue":                                        "unnpQueue upnp队列",
+	"linkstar/modules/stun.UpnpQueueState":                                   "UpnpQueueState upnp队列状态快照",
+	"linkstar/modules/stun.UpnpQueueState.Capacity":                          "队列容量",
+	"linkstar/modules/stun.UpnpQueueState.Failed":                            "执行失败的任务数",
+	"linkstar/modules/stun.UpnpQueueState.LastError":                         "最后一次失败原因",
+	"linkstar/modules/stun.UpnpQueueState.LastRunAt":                         "最后一次执行时间",
+	"linkstar/modules/stun.UpnpQueueState.Pending":                           "排队中的任务数",
+	"linkstar/modules/stun.UpnpQueueState.Processed":                         "已执行任务数",
+	"linkstar/modules/stun.UpnpQueueState.Running":                           "是否有任务正在执行",
+	"linkstar/modules/stun.activeConn":                                       "activeConn 登记在册的连接，关闭时自动注销并写入访问日志；Close 可由其他 goroutine 调用以强制断开",
+	"linkstar/modules/stun.activeConn.clientClosed":                          "读到客户端断开",
+	"linkstar/modules/stun.activeConn.closedAt":                              "在 once 内写入",
+	"linkstar/modules/stun.activeConn.closing":                               "已由本端关闭，之后的读错误不算客户端断开",
+	"linkstar/modules/stun.activeConn.holdRecord":                            "为 true 时由 handle 在转发结束后调用 finish 写访问日志",
+	"linkstar/modules/stun.activeConn.info":                                  "不变的部分",
+	"linkstar/modules/stun.activeConn.reason":                                "明确的关闭原因，先设置的生效",
+	"linkstar/modules/stun.activeConnKey":                                    "请求上下文中保存所属连接的 key",
+	"linkstar/modules/stun.connListener":                                     "connListener 由打洞端口的 Accept 循环投递连接的 net.Listener，供 http.Server 使用",
+	"linkstar/modules/stun.errPortDrift":                                     "errPortDrift 健康检查发现公网端口漂移",
+	"linkstar/modules/stun.httpProxy":                                        "httpProxy http-proxy 服务：在一个打洞端口上按 Host / 路径前缀分发到多个内网 HTTP 目标 路由在每个请求时读取，修改路由不需要重新打洞",
+	"linkstar/modules/stun.httpProxy.counter":                                "记录转发失败次数",
+	"linkstar/modules/stun.peekedConn":                                       "peekedConn 已预读部分数据的连接，Read 先返回预读的数据",
+	"linkstar/modules/stun.proxiedConn":                                      "proxiedConn 地址取自 PROXY protocol 头的连接，RemoteAddr 为真实客户端地址",
+	"linkstar/modules/stun.readOnlyConn":                                     "readOnlyConn 只读连接，握手过程中试图写入（发送 alert）时直接失败，不会发给客户端",
+	"linkstar/modules/stun.serviceEntry":                                     "serviceEntry 记录一个正在运行的服务",
+	"linkstar/modules/stun.serviceEntry.done":                                "goroutine 退出时关闭，用于等待旧实例真正结束",
+	"linkstar/modules/stun.serviceEntry.repunch":                             "通知当前隧道放弃映射、重新打洞",
+	"linkstar/modules/stun.upnpTask":                                         "unppTask upnp单个任务",
+	"linkstar/modules/stun/model.Device.DeviceID":                            "设备ID",
+	"linkstar/modules/stun/model.Device.IP":                                  "设备ip",
+	"linkstar/modules/stun/model.Device.Name":                                "\"本机\" / \"群晖NAS\" / \"树莓派\"",
+	"linkstar/modules/stun/model.Device.Services":                            "该设备上的服务",
+	"linkstar/modules/stun/model.MuxConfig":                                  "MuxConfig mux 服务各协议转发到设备上的端口，为 0 时该协议交给默认目标（internalPort）",
+	"linkstar/modules/stun/model.MuxConfig.HTTP":                             "明文 HTTP，如 80",
+	"linkstar/modules/stun/model.MuxConfig.OpenVPN":                          "OpenVPN（TCP 模式），如 1194",
+	"linkstar/modules/stun/model.MuxConfig.RDP":                              "远程桌面，如 3389",
+	"linkstar/modules/stun/model.MuxConfig.SSH":                              "SSH，如 22",
+	"linkstar/modules/stun/model.MuxConfig.TLS":                              "TLS（HTTPS 等），如 443",
+	"linkstar/modules/stun/model.MuxConfig.Timeout":                          "等待客户端首包的毫秒数，0 为 2000；超时视为服务端先发言的协议，转发到默认目标",
+	"linkstar/modules/stun/model.NatRouterInfo":                              "每个Nat路由信息",
+	"linkstar/modules/stun/model.NatRouterInfo.LanIp":                        "LAN口IP地址",
+	"linkstar/modules/stun/model.NatRouterInfo.NatLevel":                     "NAT层级",
+	"linkstar/modules/stun/model.ProxyRoute":                                 "ProxyRoute http-proxy / sni-proxy 服务的一条路由",
+	"linkstar/modules/stun/model.ProxyRoute.ID":                              "路由ID",
+	"linkstar/modules/stun/model.ProxyRouteSpec":                             "ProxyRouteSpec 路由中由用户配置的部分 匹配优先级：精确 Host > 通配 Host > 不限 Host，同级按路径前缀最长匹配",
+	"linkstar/modules/stun/model.ProxyRouteSpec.Description":                 "描述（可选）",
+	"linkstar/modules/stun/model.ProxyRouteSpec.Host":                        "匹配的 Host（sni-proxy 为 SNI），支持 *.example.com，为空匹配任意",
+	"linkstar/modules/stun/model.ProxyRouteSpec.PathPrefix":                  "匹配的路径前缀，如 /photos，为空匹配任意路径（仅 http-proxy）",
+	"linkstar/modules/stun/model.ProxyRouteSpec.PreserveHost":                "保留原始 Host 头，默认改写为目标地址（仅 http-proxy）",
+	"linkstar/modules/stun/model.ProxyRouteSpec.StripPrefix":                 "转发前去掉路径前缀（仅 http-proxy）",
+	"linkstar/modules/stun/model.ProxyRouteSpec.Target":                      "内网目标，http-proxy 如 http://192.168.1.10:8080，sni-proxy 如 192.168.1.10:5001",
+	"linkstar/modules/stun/model.Service":                                    "Service 单个服务配置 设备、服务均以指针保存，运行中的服务 goroutine 持有的指针不会因切片扩容而失效",
+	"linkstar/modules/stun/model.Service.ExternalPort":                       "外网映射端口,如 2222 (默认与 upnp映射端口一样",
+	"linkstar/modules/stun/model.Service.ID":                                 "服务唯一标识符",
+	"linkstar/modules/stun/model.Service.LastError":                          "最后一次操作的错误信息",
+	"linkstar/modules/stun/model.Service.PunchSuccess":                       "STUN穿透是否成功",
+	"linkstar/modules/stun/model.Service.Routes":                             "http-proxy 服务的路由，通过路由接口单独管理",
+	"linkstar/modules/stun/model.Service.UpdatedAt":                          "最后更新时间",
+	"linkstar/modules/stun/model.ServiceSpec":                                "ServiceSpec 服务中由用户配置的部分",
+	"linkstar/modules/stun/model.ServiceSpec.AcceptProxyProtocol":            "入站连接以 PROXY protocol 头开头（位于其他中继之后），从中取出真实客户端地址",
+	"linkstar/modules/stun/model.ServiceSpec.Allow":                          "允许的客户端 CIDR，非空时只接受其中的地址并取代全局允许列表",
+	"linkstar/modules/stun/model.ServiceSpec.CertID":                         "使用的证书（证书管理中的ID），0 为进程内生成的自签名证书",
+	"linkstar/modules/stun/model.ServiceSpec.Deny":                           "禁止的客户端 CIDR",
+	"linkstar/modules/stun/model.ServiceSpec.Description":                    "服务描述信息 (可选)",
+	"linkstar/modules/stun/model.ServiceSpec.Enabled":                        "服务是否启用 (默认 true)",
+	"linkstar/modules/stun/model.ServiceSpec.HTTPRedirect":                   "同一端口收到明文 HTTP 请求时 301 跳转到 https",
+	"linkstar/modules/stun/model.ServiceSpec.InternalPort":                   "内网端口,如 22；http-proxy / sni-proxy / mux 服务可选，作为未匹配时的默认目标",
+	"linkstar/modules/stun/model.ServiceSpec.Limits":                         "并发连接、新建速率与带宽限制，与全局限制同时生效",
+	"linkstar/modules/stun/model.ServiceSpec.Mux":                            "mux 服务的协议分流配置（仅 mux）",
+	"linkstar/modules/stun/model.ServiceSpec.Name":                           "服务名称,如 \"SSH\" / \"Web管理\" / \"照片库\"",
+	"linkstar/modules/stun/model.ServiceSpec.Protocol":                       "传输协议 \"TCP\"/\"UDP\" (默认 TCP)",
+	"linkstar/modules/stun/model.ServiceSpec.ProxyProtocol":                  "连接内网目标时发送的 PROXY protocol 版本，为空不发送；http-proxy 服务改用 X-Forwarded-For",
+	"linkstar/modules/stun/model.ServiceSpec.TLS":                            "证书",
+	"linkstar/modules/stun/model.ServiceSpec.TLSTerminate":                   "在打洞端口上终止 TLS，以明文转发给内网目标（开启后 tls 自动为 true）",
+	"linkstar/modules/stun/model.ServiceSpec.Type":                           "服务类型 forward 端口转发（默认）/ http-proxy 按 Host 或路径分发 / sni-proxy 按 SNI 透传 TLS / mux 按协议分流",
+	"linkstar/modules/stun/model.ServiceSpec.UPnPMappedPort":                 "UPnP 实际映射成功的端口号",
+	"linkstar/modules/stun/model.ServiceSpec.UseUPnP":                        "是否启用 UPnP 自动端口映射 (默认 true)",
+	"linkstar/modules/stun/model.StunConfig.BestSTUN":                        "最快的STUN服务器",
+	"linkstar/modules/stun/model.StunConfig.CreatedAt":                       "配置创建时间",
+	"linkstar/modules/stun/model.StunConfig.Devices":                         "stun设备列表",
+	"linkstar/modules/stun/model.StunConfig.LocalIP":                         "本机内网IP",
+	"linkstar/modules/stun/model.StunConfig.NatRouterList":                   "路由信息",
+	"linkstar/modules/stun/model.StunConfig.PublicIP":                        "真实公网IP",
+	"linkstar/modules/stun/model.StunConfig.StunServerList":                  "stun服务器列表",
+	"linkstar/modules/stun/model.StunConfig.UpdatedAt":                       "最后更新时间",
+	"linkstar/modules/stun/model.UpnpGateway":                                "upnp网关",
+	"linkstar/modules/stun/model.UpnpGateway.DefaultGateway":                 "默认使用的网关类型",
+	"linkstar/modules/traffic.Config":                                        "Config 流量统计文件（config/traffic.json），每分钟写入一次",
+	"linkstar/modules/traffic.Config.Services":                               "key 为 \"deviceID-serviceID\"",
+	"linkstar/modules/traffic.Counter":                                       "Counter 单个服务的实时计数，定期并入持久化的累计和每日统计",
+	"linkstar/modules/traffic.Counter.active":                                "当前连接数",
+	"linkstar/modules/traffic.Counter.bytesIn":                               "尚未并入统计的增量",
+	"linkstar/modules/traffic.Counter.bytesOut":                              "尚未并入统计的增量",
+	"linkstar/modules/traffic.Counter.conns":                                 "尚未并入统计的增量",
+	"linkstar/modules/traffic.Counter.errs":                                  "尚未并入统计的增量",
+	"linkstar/modules/traffic.Day":                                           "Day 一天的流量",
+	"linkstar/modules/traffic.Day.Date":                                      "日期，如 \"2024-05-01\"",
+	"linkstar/modules/traffic.Stats":                                         "Stats 服务的流量统计",
+	"linkstar/modules/traffic.Stats.Active":                                  "当前连接数",
+	"linkstar/modules/traffic.Stats.Days":                                    "每日流量，按日期升序，仅单个服务的查询返回",
+	"linkstar/modules/traffic.Stats.Key":                                     "\"deviceID-serviceID\"",
+	"linkstar/modules/traffic.Stats.Since":                                   "开始统计（上次重置）的时间",
+	"linkstar/modules/traffic.Totals":                                        "Totals 一段时间内的流量",
+	"linkstar/modules/traffic.Totals.BytesIn":                                "从外部客户端收到的字节",
+	"linkstar/modules/traffic.Totals.BytesOut":                               "发往外部客户端的字节",
+	"linkstar/modules/traffic.Totals.Connections":                            "连接数",
+	"linkstar/modules/traffic.Totals.Errors":                                 "连接内网目标失败次数",
+	"linkstar/modules/traffic.countedConn":                                   "countedConn 统计外部客户端连接的收发字节",
+	"linkstar/modules/traffic.record":                                        "record 持久化的统计",
+	"linkstar/modules/traffic.record.Days":                                   "每日流量，key 为日期",
+	"linkstar/modules/traffic.record.Since":                                  "开始统计的时间",
+	"linkstar/modules/traffic.record.Total":                                  "累计",
+	"linkstar/modules/webhook.Config":                                        "Config webhook 配置文件（config/webhooks.json）",
+	"linkstar/modules/webhook.Config.Webhooks":                               "webhook 列表",
+	"linkstar/modules/webhook.Delivery":                                      "Delivery 一次投递记录",
+	"linkstar/modules/webhook.Delivery.Attempts":                             "已请求次数",
+	"linkstar/modules/webhook.Delivery.CreatedAt":                            "创建时间",
+	"linkstar/modules/webhook.Delivery.Error":                                "最后一次失败原因",
+	"linkstar/modules/webhook.Delivery.Event":                                "事件类型",
+	"linkstar/modules/webhook.Delivery.EventID":                              "事件ID",
+	"linkstar/modules/webhook.Delivery.FinishedAt":                           "完成时间",
+	"linkstar/modules/webhook.Delivery.ID":                                   "投递ID，同时通过 X-LinkStar-Delivery 请求头发送",
+	"linkstar/modules/webhook.Delivery.Request":                              "请求体",
+	"linkstar/modules/webhook.Delivery.Response":                             "最后一次响应体（截断）",
+	"linkstar/modules/webhook.Delivery.Status":                               "pending / success / failed",
+	"linkstar/modules/webhook.Delivery.StatusCode":                           "最后一次响应状态码，请求未完成为 0",
+	"linkstar/modules/webhook.Delivery.URL":                                  "接收地址",
+	"linkstar/modules/webhook.Delivery.WebhookID":                            "webhook ID",
+	"linkstar/modules/webhook.Info":                                          "Info 接口返回的 webhook，不含签名密钥",
+	"linkstar/modules/webhook.Info.HasSecret":                                "是否设置了签名密钥",
+	"linkstar/modules/webhook.Payload":                                       "Payload 默认请求体，也是请求体模板的数据",
+	"linkstar/modules/webhook.Payload.Data":                                  "原始事件",
+	"linkstar/modules/webhook.Payload.Device":                                "事件所属设备",
+	"linkstar/modules/webhook.Payload.Event":                                 "事件类型",
+	"linkstar/modules/webhook.Payload.EventID":                               "事件ID",
+	"linkstar/modules/webhook.Payload.NewAddr":                               "新公网地址 IP:端口（公网IP变化时为IP）",
+	"linkstar/modules/webhook.Payload.OldAddr":                               "旧公网地址 IP:端口（公网IP变化时为IP）",
+	"linkstar/modules/webhook.Payload.PublicURL":                             "访问地址",
+	"linkstar/modules/webhook.Payload.Reason":                                "下线、漂移、映射失败、封禁的原因",
+	"linkstar/modules/webhook.Payload.Service":                               "事件所属服务",
+	"linkstar/modules/webhook.Payload.State":                                 "服务状态 online / offline",
+	"linkstar/modules/webhook.Payload.Time":                                  "事件时间",
+	"linkstar/modules/webhook.PayloadDevice.ID":                              "设备ID",
+	"linkstar/modules/webhook.PayloadDevice.IP":                              "设备内网IP",
+	"linkstar/modules/webhook.PayloadDevice.Name":                            "设备名称",
+	"linkstar/modules/webhook.PayloadService.ID":                             "服务ID",
+	"linkstar/modules/webhook.PayloadService.Name":                           "服务名称",
+	"linkstar/modules/webhook.PayloadService.Protocol":                       "协议",
+	"linkstar/modules/webhook.Spec":                                          "Spec webhook 中由用户配置的部分",
+	"linkstar/modules/webhook.Spec.BodyTemplate":                             "请求体模板（Go text/template，数据为 Payload），为空时发送 Payload 的 JSON",
+	"linkstar/modules/webhook.Spec.DeviceID":                                 "只推送该设备的事件，0 表示全部",
+	"linkstar/modules/webhook.Spec.Enabled":                                  "是否启用",
+	"linkstar/modules/webhook.Spec.Events":                                   "订阅的事件，为空表示除 connection.accepted 外的全部",
+	"linkstar/modules/webhook.Spec.Headers":                                  "自定义请求头",
+	"linkstar/modules/webhook.Spec.MaxRetries":                               "失败后的重试次数",
+	"linkstar/modules/webhook.Spec.Name":                                     "名称",
+	"linkstar/modules/webhook.Spec.Secret":                                   "HMAC-SHA256 签名密钥，为空不签名；接口返回时隐藏",
+	"linkstar/modules/webhook.Spec.ServiceID":                                "只推送该服务的事件，0 表示全部",
+	"linkstar/modules/webhook.Spec.Timeout":                                  "单次请求超时（秒），0 为默认 10 秒",
+	"linkstar/modules/webhook.Spec.URL":                                      "接收地址",
+	"linkstar/modules/webhook.TestEvent":                                     "TestEvent 手动触发的测试事件",
+	"linkstar/modules/webhook.TestEvent.Message":                             "说明",
+	"linkstar/modules/webhook.Webhook":                                       "Webhook 一个推送目标",
+	"linkstar/modules/webhook.Webhook.ID":                                    "webhook ID",
+	"linkstar/utils/res.ErrorBody.Code":                                      "机器可读错误码，如 DEVICE_NOT_FOUND",
+	"linkstar/utils/res.ErrorBody.Details":                                   "附加信息",
+	"linkstar/utils/res.ErrorBody.Message":                                   "错误描述",
+	"linkstar/utils/res.ErrorResponse":                                       "ErrorResponse v2 接口错误响应",
+	"linkstar/utils/res.ListResponse":                                        "ListResponse v2 接口列表响应",
+	"linkstar/utils/validate.FieldError":                                     "FieldError 单个字段的校验错误",
+	"linkstar/utils/validate.FieldError.Field":                               "字段名（json 名称）",
+	"linkstar/utils/validate.FieldError.Message":                             "错误描述",
+	"linkstar/utils/validate.FieldError.Rule":                                "未通过的规则，如 required / min / ip",
+}
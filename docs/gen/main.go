@@ -0,0 +1,132 @@
+// gen 扫描项目源码中结构体字段的注释，生成 docs/field_docs.go，供 OpenAPI 文档描述字段含义
+//
+// 用法：在 docs 目录执行 go generate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const module = "linkstar"
+
+func main() {
+	root := flag.String("root", "..", "项目根目录")
+	out := flag.String("o", "field_docs.go", "输出文件")
+	flag.Parse()
+
+	docs := map[string]string{}
+	err := filepath.WalkDir(*root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// test 目录下是独立的实验模块，docs 是生成目标
+			name := d.Name()
+			if path != *root && (strings.HasPrefix(name, ".") || name == "test" || name == "docs" || name == "web") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		return collect(*root, path, docs)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by docs/gen; DO NOT EDIT.\n\n")
+	buf.WriteString("package docs\n\n")
+	buf.WriteString("// fieldDocs 结构体及字段注释，key: \"包路径.类型\" 或 \"包路径.类型.字段\"\n")
+	buf.WriteString("var fieldDocs = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, docs[k])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collect 收集一个文件内所有结构体及字段的注释
+func collect(root, path string, docs map[string]string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	pkgPath := module
+	if rel != "." {
+		pkgPath = module + "/" + filepath.ToSlash(rel)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			typeKey := pkgPath + "." + ts.Name.Name
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			if text := commentText(doc); text != "" {
+				docs[typeKey] = text
+			}
+
+			for _, field := range st.Fields.List {
+				// 字段说明写在行尾注释，上方的注释多为分组说明，不采用
+				text := commentText(field.Comment)
+				if text == "" {
+					continue
+				}
+				for _, name := range field.Names {
+					docs[typeKey+"."+name.Name] = text
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(group.Text(), "\n", " "))
+}
@@ -0,0 +1,108 @@
+// swaggerui 下载固定版本的 swagger-ui-dist，解出页面用到的静态资源到 docs/swagger-ui，随程序一起嵌入
+//
+// 用法：在 docs 目录执行 go generate；版本号在 docs/swagger-ui/VERSION 中，已下载的版本不会重复下载
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 页面用到的文件
+var assets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+func main() {
+	dir := flag.String("dir", "swagger-ui", "输出目录，需包含 VERSION 文件")
+	flag.Parse()
+
+	data, err := os.ReadFile(filepath.Join(*dir, "VERSION"))
+	if err != nil {
+		log.Fatalf("读取版本号失败: %v", err)
+	}
+	version := strings.TrimSpace(string(data))
+
+	if downloaded(*dir) {
+		return
+	}
+
+	url := fmt.Sprintf("https://registry.npmjs.org/swagger-ui-dist/-/swagger-ui-dist-%s.tgz", version)
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		log.Fatalf("下载 %s 失败: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("下载 %s 失败: %s", url, resp.Status)
+	}
+
+	if err := extract(resp.Body, *dir); err != nil {
+		log.Fatalf("解压 swagger-ui-dist %s 失败: %v", version, err)
+	}
+	if !downloaded(*dir) {
+		log.Fatalf("swagger-ui-dist %s 中缺少页面需要的文件", version)
+	}
+	log.Printf("已更新 swagger-ui-dist %s", version)
+}
+
+// 所有文件都已存在
+func downloaded(dir string) bool {
+	for _, name := range assets {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// 从 npm 包（package/ 目录下）解出需要的文件
+func extract(r io.Reader, dir string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Base(hdr.Name)
+		if path.Dir(hdr.Name) != "package" || !wanted(name) {
+			continue
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func wanted(name string) bool {
+	for _, a := range assets {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
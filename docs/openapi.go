@@ -0,0 +1,467 @@
+package docs
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Operation 一个接口的文档信息，和路由表中的 "METHOD 路径" 对应
+type Operation struct {
+	Summary  string // 接口说明
+	Tag      string // 分组
+	Request  any    // 请求类型：uri 标签为路径参数，form 标签为查询参数，其余 json 字段为请求体
+	Response any    // 成功时返回的数据类型，nil 表示无
+	List     bool   // 返回为列表 {list, count}
+	Status   int    // 成功状态码，默认 200
+	V2       bool   // v2 接口直接返回资源，失败返回 res.ErrorResponse；v1 包裹在 res.Response 中
+	Stream   bool   // 以 text/event-stream 持续推送 Response 类型的事件
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	pathParam    = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+	unsafeName   = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// Build 根据路由表生成 OpenAPI 3 文档，只收录 /api/ 下的路由，ops 中没有的路由只生成基础信息
+func Build(routes gin.RoutesInfo, ops map[string]Operation, errorType any) map[string]any {
+	g := &generator{components: map[string]any{}}
+	paths := map[string]map[string]any{}
+	usedIDs := map[string]bool{}
+
+	var errorSchema map[string]any
+	if errorType != nil {
+		errorSchema = g.schema(reflect.TypeOf(errorType))
+	}
+
+	for _, route := range routes {
+		// 只收录 /api、固定地址跳转 /go 与 Prometheus 指标 /metrics
+		if !strings.HasPrefix(route.Path, "/api/") && route.Path != "/go" && !strings.HasPrefix(route.Path, "/go/") &&
+			route.Path != "/metrics" {
+			continue
+		}
+		op, documented := ops[route.Method+" "+route.Path]
+		if !documented && route.Method == http.MethodHead {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+
+		operationID := handlerName(route.Handler)
+		if operationID == "" || usedIDs[operationID] {
+			operationID = strings.ToLower(route.Method) + unsafeName.ReplaceAllString(strings.ReplaceAll(path, "/", "_"), "")
+		}
+		usedIDs[operationID] = true
+
+		tag := op.Tag
+		if tag == "" {
+			tag = defaultTag(route.Path)
+		}
+
+		item := map[string]any{
+			"operationId": operationID,
+			"summary":     op.Summary,
+			"tags":        []string{tag},
+		}
+
+		var params []any
+		for _, m := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			params = append(params, map[string]any{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		if op.Request != nil {
+			reqType := deref(reflect.TypeOf(op.Request))
+			params = append(params, g.parameters(reqType, params)...)
+			if hasBody(reqType) {
+				item["requestBody"] = map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": g.schema(reqType)},
+					},
+				}
+			}
+		}
+		if len(params) > 0 {
+			item["parameters"] = params
+		}
+
+		item["responses"] = g.responses(route, op, errorSchema)
+		paths[path][strings.ToLower(route.Method)] = item
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "LinkStar API",
+			"description": "LinkStar 内网穿透管理接口。配置了 token 时，需通过 Authorization: Bearer <token> 、token 请求头或 ?token= 查询参数访问",
+			"version":     "2",
+		},
+		"servers": []any{map[string]any{"url": "/"}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": g.components,
+			"securitySchemes": map[string]any{
+				"bearerAuth":  map[string]any{"type": "http", "scheme": "bearer"},
+				"tokenHeader": map[string]any{"type": "apiKey", "in": "header", "name": "token"},
+				"tokenQuery":  map[string]any{"type": "apiKey", "in": "query", "name": "token"},
+			},
+		},
+		// 未配置 token 时接口不鉴权，因此允许空的安全要求
+		"security": []any{
+			map[string]any{},
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"tokenHeader": []string{}},
+			map[string]any{"tokenQuery": []string{}},
+		},
+	}
+}
+
+// 生成响应
+func (g *generator) responses(route gin.RouteInfo, op Operation, errorSchema map[string]any) map[string]any {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	var data map[string]any
+	if op.Response != nil {
+		data = g.schema(reflect.TypeOf(op.Response))
+	}
+	if op.List {
+		data = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"list":  map[string]any{"type": "array", "items": orAny(data)},
+				"count": map[string]any{"type": "integer"},
+			},
+		}
+	}
+
+	responses := map[string]any{}
+	if op.Stream {
+		responses["200"] = map[string]any{
+			"description": "Server-Sent Events 事件流",
+			"content": map[string]any{
+				"text/event-stream": map[string]any{"schema": orAny(data)},
+			},
+		}
+		return responses
+	}
+	if !op.V2 {
+		// v1 接口总是返回 200，通过 code 区分成功失败
+		responses["200"] = jsonResponse("code 为 0 表示成功，非 0 表示失败，失败原因见 msg", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code": map[string]any{"type": "integer", "description": "0 成功，7 失败"},
+				"msg":  map[string]any{"type": "string"},
+				"data": orAny(data),
+			},
+		})
+		return responses
+	}
+
+	if status == http.StatusNoContent || data == nil {
+		responses[itoa(status)] = map[string]any{"description": http.StatusText(status)}
+	} else {
+		responses[itoa(status)] = jsonResponse(http.StatusText(status), data)
+	}
+
+	if errorSchema == nil {
+		return responses
+	}
+	codes := []int{http.StatusUnauthorized, http.StatusInternalServerError}
+	if op.Request != nil {
+		codes = append(codes, http.StatusBadRequest)
+	}
+	if strings.ContainsAny(route.Path, ":*") {
+		codes = append(codes, http.StatusNotFound)
+	}
+	if route.Method == http.MethodPost || route.Method == http.MethodPut {
+		codes = append(codes, http.StatusConflict)
+	}
+	for _, code := range codes {
+		responses[itoa(code)] = jsonResponse(http.StatusText(code), errorSchema)
+	}
+	return responses
+}
+
+type generator struct {
+	components map[string]any
+}
+
+// schema 通过反射生成类型的 JSON Schema，具名结构体放入 components
+func (g *generator) schema(t reflect.Type) map[string]any {
+	t = deref(t)
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "description": "纳秒"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = map[string]any{} // 先占位，防止递归类型死循环
+			g.components[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema 生成结构体的 object schema，匿名嵌入的结构体字段展开
+func (g *generator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	g.collectFields(t, properties, &required)
+
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if doc := fieldDocs[t.PkgPath()+"."+t.Name()]; doc != "" {
+		s["description"] = doc
+	}
+	return s
+}
+
+func (g *generator) collectFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		if f.Anonymous && name == "" && deref(f.Type).Kind() == reflect.Struct {
+			g.collectFields(deref(f.Type), properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(f.Type)
+		applyRules(fs, f.Tag.Get("binding"))
+		if doc := fieldDocs[t.PkgPath()+"."+t.Name()+"."+f.Name]; doc != "" {
+			if _, isRef := fs["$ref"]; isRef {
+				// $ref 不能有同级字段，用 allOf 包一层
+				fs = map[string]any{"allOf": []any{fs}, "description": doc}
+			} else {
+				fs["description"] = doc
+			}
+		}
+		properties[name] = fs
+		if isRequired(f) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// parameters 生成查询参数（form 标签），路径参数已由路由生成
+func (g *generator) parameters(t reflect.Type, existing []any) []any {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var params []any
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("form"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		p := map[string]any{"name": tag, "in": "query", "schema": g.schema(f.Type)}
+		if isRequired(f) {
+			p["required"] = true
+		}
+		if doc := fieldDocs[t.PkgPath()+"."+t.Name()+"."+f.Name]; doc != "" {
+			p["description"] = doc
+		}
+		params = append(params, p)
+	}
+
+	// 路径参数补充说明
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("uri")
+		doc := fieldDocs[t.PkgPath()+"."+t.Name()+"."+f.Name]
+		if tag == "" || doc == "" {
+			continue
+		}
+		for _, p := range existing {
+			if pm := p.(map[string]any); pm["name"] == tag {
+				pm["description"] = doc
+				pm["schema"] = g.schema(f.Type)
+			}
+		}
+	}
+	return params
+}
+
+// 是否有请求体：存在未被 json:"-" 排除且不是查询参数的字段
+func hasBody(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("form") != "" || f.Tag.Get("uri") != "" {
+			continue
+		}
+		if _, ok := jsonName(f); ok && (f.IsExported() || f.Anonymous) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName 返回字段 json 名称，ok=false 表示该字段不参与序列化
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	return strings.Split(tag, ",")[0], true
+}
+
+// applyRules 将 binding 校验规则写入 schema
+func applyRules(s map[string]any, rules string) {
+	if _, isRef := s["$ref"]; isRef || rules == "" {
+		return
+	}
+	// dive 之后的规则作用于数组元素
+	if before, after, found := strings.Cut(rules, "dive"); found {
+		if items, ok := s["items"].(map[string]any); ok {
+			applyRules(items, strings.TrimPrefix(after, ","))
+		}
+		rules = strings.TrimSuffix(before, ",")
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			key := map[string]string{"min": "minimum", "max": "maximum"}[name]
+			if s["type"] == "string" {
+				key = map[string]string{"min": "minLength", "max": "maxLength"}[name]
+			}
+			s[key] = n
+		case "oneof":
+			s["enum"] = strings.Fields(param)
+		case "ip":
+			s["format"] = "ip"
+		case "cidr":
+			s["format"] = "cidr"
+		case "url":
+			s["format"] = "uri"
+		case "fqdn":
+			s["format"] = "hostname"
+		case "startswith":
+			s["pattern"] = "^" + regexp.QuoteMeta(param)
+		case "protocol":
+			s["enum"] = []string{"TCP", "UDP"}
+		}
+	}
+}
+
+func isRequired(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return unsafeName.ReplaceAllString(pkg+"."+t.Name(), "_")
+}
+
+// handlerName "linkstar/api/stun_api.StunApi.StunServiceAddView-fm" -> "StunServiceAddView"
+func handlerName(handler string) string {
+	handler = strings.TrimSuffix(handler, "-fm")
+	if i := strings.LastIndex(handler, "."); i >= 0 {
+		handler = handler[i+1:]
+	}
+	if strings.HasPrefix(handler, "func") {
+		return ""
+	}
+	return handler
+}
+
+// "/api/v2/devices/:id" -> "v2"，"/api/stun/config" -> "stun"
+func defaultTag(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	return parts[0]
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func orAny(s map[string]any) map[string]any {
+	if s == nil {
+		return map[string]any{"type": "object"}
+	}
+	return s
+}
+
+func itoa(code int) string {
+	return strconv.Itoa(code)
+}
@@ -0,0 +1,31 @@
+package docs
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
+
+//go:generate go run ./gen/swaggerui
+
+//go:embed swagger.html
+var swaggerHTML string
+
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// SwaggerAssets Swagger UI 的静态资源（版本见 swagger-ui/VERSION），由 /api/docs/assets/ 提供，不依赖外部 CDN
+var SwaggerAssets, _ = fs.Sub(swaggerUI, "swagger-ui")
+
+// SwaggerHTML Swagger UI 页面，文档地址为 /api/openapi.json
+var SwaggerHTML = []byte(strings.ReplaceAll(swaggerHTML, "{{assets}}", swaggerAssetsURL()))
+
+// 页面加载静态资源的地址：已通过 go generate 打包时使用 /api/docs/assets，
+// 否则回退到 CDN 上相同版本的 swagger-ui-dist
+func swaggerAssetsURL() string {
+	if _, err := fs.Stat(SwaggerAssets, "swagger-ui-bundle.js"); err == nil {
+		return "/api/docs/assets"
+	}
+	version, _ := fs.ReadFile(SwaggerAssets, "VERSION")
+	return "https://unpkg.com/swagger-ui-dist@" + strings.TrimSpace(string(version))
+}
@@ -1 +1,15 @@
 package flags
+
+import "flag"
+
+type Options struct {
+	File string // 配置文件路径
+}
+
+var FlagOptions = new(Options)
+
+// Parse 解析命令行参数
+func Parse() {
+	flag.StringVar(&FlagOptions.File, "f", "config/settings.json", "配置文件路径")
+	flag.Parse()
+}
@@ -1,10 +1,12 @@
 package global
 
 import (
+	"linkstar/conf"
 	"linkstar/modules/stun/model"
 )
 
 var (
+	Config      *conf.Config
 	StunConfig  model.StunConfig
 	UpnpGateway *model.UpnpGateway
 )
@@ -3,9 +3,23 @@ package main
 import (
 	"embed"
 	"linkstar/core"
+	"linkstar/flags"
+	"linkstar/global"
+	"linkstar/modules/acme"
+	"linkstar/modules/certs"
+	"linkstar/modules/ddns"
+	"linkstar/modules/dnsserver"
+	"linkstar/modules/firewall"
+	"linkstar/modules/limiter"
+	"linkstar/modules/redirect"
+	"linkstar/modules/srv"
 	"linkstar/modules/stun"
+	"linkstar/modules/traffic"
+	"linkstar/modules/webhook"
 	"linkstar/routers"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 )
@@ -16,11 +30,41 @@ var webFS embed.FS
 func main() {
 	// 设置时区
 	os.Setenv("TZ", "Asia/Shanghai")
+	flags.Parse()
 	core.InitLogger()
+	global.Config = core.ReadConf()
+	core.ConfigureLogger(global.Config.Log)
 	logrus.Info("LinkStar Run")
 
+	// 先订阅事件总线，再启动服务
+	webhook.Init()
+	certs.Init()
+	firewall.Init()
+	limiter.Init()
+	traffic.Init()
+	flushOnExit()
 	stun.InitSTUN()
+	ddns.Init()
+	acme.Init()
+	srv.Init()
+	dnsserver.Init()
+	redirect.Init()
 
 	routers.Run(webFS)
 
 }
+
+// 收到 Ctrl+C 或 SIGTERM 时先写入未保存的流量统计再退出，需在 traffic.Init 之后调用
+func flushOnExit() {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signalChan
+		logrus.Infof("收到退出信号：%v ,正在保存流量统计", sig)
+		if err := traffic.Flush(); err != nil {
+			logrus.Error("保存流量统计失败：", err)
+		}
+		os.Exit(0)
+	}()
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"linkstar/global"
+	"linkstar/utils/res"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 从请求中取令牌：Authorization: Bearer xxx / token 请求头 / ?token= 查询参数
+func requestToken(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token := c.GetHeader("token"); token != "" {
+		return token
+	}
+	return c.Query("token")
+}
+
+// checkToken 校验令牌，配置的令牌为空时 required 决定是否放行
+func checkToken(c *gin.Context, required bool) bool {
+	token := global.Config.System.Token
+	if token == "" {
+		return !required
+	}
+	return subtle.ConstantTimeCompare([]byte(requestToken(c)), []byte(token)) == 1
+}
+
+// AuthMiddleware API 鉴权，未配置 token 时放行
+func AuthMiddleware(c *gin.Context) {
+	if !checkToken(c, false) {
+		res.FailWithMsg("认证失败", c)
+		c.Abort()
+		return
+	}
+}
+
+// AuthV2Middleware v2 接口鉴权，失败返回 401
+func AuthV2Middleware(c *gin.Context) {
+	if !checkToken(c, false) {
+		res.Error(http.StatusUnauthorized, res.ErrCodeUnauthorized, "认证失败", c)
+		c.Abort()
+		return
+	}
+}
+
+// StrictAuthMiddleware 强制鉴权，未配置 token 时一律拒绝（用于调试接口）
+func StrictAuthMiddleware(c *gin.Context) {
+	if !checkToken(c, true) {
+		res.FailWithMsg("认证失败", c)
+		c.Abort()
+		return
+	}
+}
+
+// ShareAuthMiddleware 固定地址跳转鉴权：配置了分享令牌时需携带分享令牌或 API token，失败返回 401
+func ShareAuthMiddleware(c *gin.Context) {
+	share := global.Config.Redirect.ShareToken
+	if share == "" {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(requestToken(c)), []byte(share)) == 1 || checkToken(c, true) {
+		return
+	}
+	res.Error(http.StatusUnauthorized, res.ErrCodeUnauthorized, "分享令牌无效", c)
+	c.Abort()
+}
@@ -2,15 +2,19 @@ package middleware
 
 import (
 	"linkstar/utils/res"
+	"linkstar/utils/validate"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func BindJsonMiddleware[T any](c *gin.Context) {
 	var cr T
 	err := c.ShouldBind(&cr)
 	if err != nil {
-		res.FailWithError(err, c)
+		errs := validate.Translate(err)
+		res.FailWithDetails(errs.Error(), errs, c)
 		c.Abort()
 		return
 	}
@@ -21,7 +25,8 @@ func BindQueryMiddleware[T any](c *gin.Context) {
 	var cr T
 	err := c.ShouldBindQuery(&cr)
 	if err != nil {
-		res.FailWithError(err, c)
+		errs := validate.Translate(err)
+		res.FailWithDetails(errs.Error(), errs, c)
 		c.Abort()
 		return
 	}
@@ -32,7 +37,8 @@ func BindUriyMiddleware[T any](c *gin.Context) {
 	var cr T
 	err := c.ShouldBindUri(&cr)
 	if err != nil {
-		res.FailWithError(err, c)
+		errs := validate.Translate(err)
+		res.FailWithDetails(errs.Error(), errs, c)
 		c.Abort()
 		return
 	}
@@ -42,3 +48,47 @@ func BindUriyMiddleware[T any](c *gin.Context) {
 func GetBindRequest[T any](c *gin.Context) (cr T) {
 	return c.MustGet("request").(T)
 }
+
+// BindV2Middleware v2 接口绑定：先映射路径参数，有请求体时再绑定 JSON，整体校验失败返回 400
+func BindV2Middleware[T any](c *gin.Context) {
+	var cr T
+	params := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = []string{p.Value}
+	}
+	err := binding.MapFormWithTag(&cr, params, "uri")
+	if err == nil {
+		if c.Request.ContentLength != 0 {
+			err = c.ShouldBindJSON(&cr)
+		} else {
+			err = binding.Validator.ValidateStruct(&cr)
+		}
+	}
+	if err != nil {
+		errs := validate.Translate(err)
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, errs.Error(), errs, c)
+		c.Abort()
+		return
+	}
+	c.Set("request", cr)
+}
+
+// BindV2QueryMiddleware v2 查询接口绑定：映射路径参数和查询参数（form 标签），校验失败返回 400
+func BindV2QueryMiddleware[T any](c *gin.Context) {
+	var cr T
+	params := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = []string{p.Value}
+	}
+	err := binding.MapFormWithTag(&cr, params, "uri")
+	if err == nil {
+		err = c.ShouldBindQuery(&cr)
+	}
+	if err != nil {
+		errs := validate.Translate(err)
+		res.ErrorWithDetails(http.StatusBadRequest, res.ErrCodeInvalidRequest, errs.Error(), errs, c)
+		c.Abort()
+		return
+	}
+	c.Set("request", cr)
+}
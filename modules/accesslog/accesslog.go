@@ -0,0 +1,220 @@
+package accesslog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	logPath  = "logs"       // 与运行日志相同的目录，按日期分子目录
+	fileName = "access.log" // 每行一条 JSON 记录
+)
+
+// 连接关闭原因
+const (
+	ReasonClientClosed      = "client_closed"      // 客户端先断开
+	ReasonServerClosed      = "server_closed"      // 内网目标先断开，或 http-proxy 空闲超时
+	ReasonTargetUnreachable = "target_unreachable" // 连接内网目标失败
+	ReasonRejected          = "rejected"           // 没有选出目标（握手失败、没有匹配的路由）
+	ReasonKilled            = "killed"             // 通过接口断开
+)
+
+var ErrInvalidRange = errors.New("开始时间不能晚于结束时间")
+
+// Record 一个转发连接的访问记录，连接关闭时写入
+type Record struct {
+	Time        time.Time `json:"time"`        // 连接关闭时间
+	StartedAt   time.Time `json:"startedAt"`   // 连接建立时间
+	DeviceID    uint      `json:"deviceId"`    // 设备ID
+	Device      string    `json:"device"`      // 设备名称
+	ServiceID   uint      `json:"serviceId"`   // 服务ID
+	Service     string    `json:"service"`     // 服务名称
+	RemoteAddr  string    `json:"remoteAddr"`  // 客户端地址
+	Target      string    `json:"target"`      // 内网目标，http-proxy 为最后一次请求的目标
+	DurationMs  int64     `json:"durationMs"`  // 持续时间（毫秒）
+	BytesIn     uint64    `json:"bytesIn"`     // 从客户端收到的字节
+	BytesOut    uint64    `json:"bytesOut"`    // 发往客户端的字节
+	CloseReason string    `json:"closeReason"` // 关闭原因
+}
+
+var (
+	mu   sync.Mutex
+	file *os.File
+	date string
+)
+
+// 当天的日志文件路径
+func filePath(date string) string {
+	return filepath.Join(logPath, date, fileName)
+}
+
+// 按日期切换文件，调用方需持有 mu
+func rotate(now time.Time) error {
+	d := now.Format(time.DateOnly)
+	if d == date && file != nil {
+		return nil
+	}
+	if file != nil {
+		file.Close()
+		file = nil
+	}
+	if err := os.MkdirAll(filepath.Join(logPath, d), 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	f, err := os.OpenFile(filePath(d), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %w", fileName, err)
+	}
+	file, date = f, d
+	return nil
+}
+
+// Write 追加一条访问记录，失败只记运行日志
+func Write(r Record) {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := rotate(r.Time); err != nil {
+		logrus.Warnf("写入访问日志失败: %v", err)
+		return
+	}
+	if _, err := file.Write(line); err != nil {
+		logrus.Warnf("写入访问日志失败: %v", err)
+	}
+}
+
+// Filter 查询条件，零值表示不限
+type Filter struct {
+	DeviceID  uint      // 设备ID
+	ServiceID uint      // 服务ID
+	IP        string    // 客户端 IP
+	From, To  time.Time // 关闭时间范围，默认最近 24 小时
+	Limit     int       // 最多返回条数，默认 100
+}
+
+func (f Filter) match(r *Record) bool {
+	if f.DeviceID != 0 && r.DeviceID != f.DeviceID {
+		return false
+	}
+	if f.ServiceID != 0 && r.ServiceID != f.ServiceID {
+		return false
+	}
+	if r.Time.Before(f.From) || r.Time.After(f.To) {
+		return false
+	}
+	if f.IP != "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.Equal(net.ParseIP(f.IP)) {
+			return false
+		}
+	}
+	return true
+}
+
+// 打开一天的日志，之前日期的可能已被压缩为 access.log.gz；都不存在时返回 nil
+func openDay(date string) (io.ReadCloser, error) {
+	fh, err := os.Open(filePath(date))
+	if err == nil {
+		return fh, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	fh, err = os.Open(filePath(date) + ".gz")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(fh)
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{zr, fh}, nil
+}
+
+// 读取一天的日志中符合条件的记录，按时间升序
+func readDay(date string, f Filter) ([]Record, error) {
+	r, err := openDay(date)
+	if r == nil || err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var list []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if json.Unmarshal(scanner.Bytes(), &r) != nil {
+			continue
+		}
+		if f.match(&r) {
+			list = append(list, r)
+		}
+	}
+	return list, scanner.Err()
+}
+
+// Query 按条件查询访问记录，按时间倒序
+func Query(f Filter) ([]Record, error) {
+	if f.To.IsZero() {
+		f.To = time.Now()
+	}
+	if f.From.IsZero() {
+		f.From = f.To.Add(-24 * time.Hour)
+	}
+	if f.From.After(f.To) {
+		return nil, ErrInvalidRange
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	// 从结束日期往前逐日读取，够数即停
+	list := []Record{}
+	first := f.From.In(time.Local).Format(time.DateOnly)
+	for day := f.To.In(time.Local); ; day = day.AddDate(0, 0, -1) {
+		d := day.Format(time.DateOnly)
+		if d < first {
+			break
+		}
+		records, err := readDay(d, f)
+		if err != nil {
+			return nil, fmt.Errorf("读取访问日志 %s 失败: %w", d, err)
+		}
+		slices.Reverse(records)
+		list = append(list, records...)
+		if len(list) >= f.Limit {
+			return list[:f.Limit], nil
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,310 @@
+package acme
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/modules/ddns"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	acmeConfigPath = "config/acme.json"
+
+	// Let's Encrypt 正式环境
+	defaultDirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+)
+
+var (
+	ErrOrderNotFound = errors.New("证书申请不存在")
+	ErrIssuing       = errors.New("证书正在申请中")
+	ErrInvalidConfig = errors.New("配置不合法")
+	ErrSaveConfig    = errors.New("保存 ACME 配置失败")
+)
+
+// invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true
+type invalidFieldError struct {
+	field string
+	msg   string
+}
+
+func (e *invalidFieldError) Error() string { return e.msg }
+func (e *invalidFieldError) Unwrap() error { return ErrInvalidConfig }
+
+func invalidField(field, format string, args ...any) error {
+	return &invalidFieldError{field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// InvalidField 配置不合法时对应的请求字段，其他错误返回空字符串
+func InvalidField(err error) string {
+	var fe *invalidFieldError
+	if errors.As(err, &fe) {
+		return fe.field
+	}
+	return ""
+}
+
+// Config ACME 配置文件（config/acme.json），签发的证书保存在证书管理中
+type Config struct {
+	Settings Settings `json:"settings"` // 账户与 CA 设置
+	Orders   []*Order `json:"orders"`   // 证书申请
+}
+
+// Settings ACME 账户与 CA 设置
+type Settings struct {
+	DirectoryURL    string `json:"directoryUrl" binding:"omitempty,url"`    // ACME 目录地址，为空使用 Let's Encrypt 正式环境
+	Email           string `json:"email" binding:"omitempty,email"`         // 账户联系邮箱
+	SkipTLSVerify   bool   `json:"skipTlsVerify"`                           // 不校验 ACME 服务器证书（仅用于本地 Pebble 测试）
+	RenewBeforeDays int    `json:"renewBeforeDays" binding:"min=0,max=60"`  // 到期前多少天续期，0 为 30 天
+	PropagationWait int    `json:"propagationWait" binding:"min=0,max=600"` // 写入 TXT 记录后等待生效的秒数，0 为 30 秒
+}
+
+func (s Settings) directoryURL() string {
+	if s.DirectoryURL == "" {
+		return defaultDirectoryURL
+	}
+	return s.DirectoryURL
+}
+
+func (s Settings) renewBefore() time.Duration {
+	if s.RenewBeforeDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(s.RenewBeforeDays) * 24 * time.Hour
+}
+
+func (s Settings) propagationWait() time.Duration {
+	if s.PropagationWait <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(s.PropagationWait) * time.Second
+}
+
+// Order 证书申请
+type Order struct {
+	ID uint `json:"id"` // 申请ID
+	OrderSpec
+	CertID uint `json:"certId"` // 签发后保存在证书管理中的证书ID，续期时原地替换
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OrderSpec 证书申请中由用户配置的部分
+type OrderSpec struct {
+	Name       string   `json:"name" binding:"required"`                        // 证书名称
+	Domains    []string `json:"domains" binding:"required,min=1,dive,required"` // 域名，支持 *.example.com
+	ProviderID uint     `json:"providerId" binding:"required"`                  // 写入 _acme-challenge TXT 记录的 DNS 服务商（DDNS 中配置）
+	Zone       string   `json:"zone" binding:"required,fqdn"`                   // 主域名，如 example.com
+	Enabled    bool     `json:"enabled"`                                        // 是否自动申请与续期
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(acmeConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](acmeConfigPath); err != nil {
+			return c, err
+		}
+	}
+	if c.Orders == nil {
+		c.Orders = []*Order{}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(acmeConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(acmeConfigPath, config); err != nil {
+		logrus.Error("ACME 配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 规范化并检查证书申请
+func checkOrder(spec *OrderSpec) error {
+	spec.Zone = strings.ToLower(strings.TrimSuffix(spec.Zone, "."))
+	for i, d := range spec.Domains {
+		d = strings.ToLower(strings.TrimSuffix(d, "."))
+		spec.Domains[i] = d
+		name := strings.TrimPrefix(d, "*.")
+		if name != spec.Zone && !strings.HasSuffix(name, "."+spec.Zone) {
+			return invalidField("domains", "域名 %s 不属于主域名 %s", d, spec.Zone)
+		}
+	}
+	if _, err := ddns.GetProvider(spec.ProviderID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func findOrder(id uint) (int, *Order) {
+	for i, o := range config.Orders {
+		if o.ID == id {
+			return i, o
+		}
+	}
+	return -1, nil
+}
+
+// 服务商是否被证书申请使用，注册到 ddns.ProviderInUse
+func providerInUse(id uint) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, o := range config.Orders {
+		if o.ProviderID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// 证书是否由证书申请管理，注册到 certs.InUse
+func certInUse(id uint) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, o := range config.Orders {
+		if o.CertID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSettings 账户与 CA 设置
+func GetSettings() Settings {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return config.Settings
+}
+
+// UpdateSettings 修改账户与 CA 设置，更换 CA 后下次申请时自动注册账户
+func UpdateSettings(s Settings) (Settings, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config.Settings = s
+	if err := saveConfig(); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+// ListOrders 全部证书申请及其状态
+func ListOrders() []OrderInfo {
+	configMu.Lock()
+	orders := make([]Order, 0, len(config.Orders))
+	for _, o := range config.Orders {
+		orders = append(orders, *o)
+	}
+	configMu.Unlock()
+
+	list := make([]OrderInfo, 0, len(orders))
+	for _, o := range orders {
+		list = append(list, OrderInfo{Order: o, Status: orderStatus(o.ID)})
+	}
+	return list
+}
+
+// GetOrder 按ID查找证书申请
+func GetOrder(id uint) (OrderInfo, error) {
+	configMu.Lock()
+	_, o := findOrder(id)
+	var order Order
+	if o != nil {
+		order = *o
+	}
+	configMu.Unlock()
+
+	if o == nil {
+		return OrderInfo{}, ErrOrderNotFound
+	}
+	return OrderInfo{Order: order, Status: orderStatus(id)}, nil
+}
+
+// AddOrder 新增证书申请，启用时在后台立即申请
+func AddOrder(spec OrderSpec) (OrderInfo, error) {
+	if err := checkOrder(&spec); err != nil {
+		return OrderInfo{}, err
+	}
+
+	configMu.Lock()
+	var maxID uint = 0
+	for _, o := range config.Orders {
+		if o.ID > maxID {
+			maxID = o.ID
+		}
+	}
+	o := &Order{ID: maxID + 1, OrderSpec: spec, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	config.Orders = append(config.Orders, o)
+	err := saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return OrderInfo{}, err
+	}
+
+	if spec.Enabled {
+		go issueOrder(o.ID)
+	}
+	return GetOrder(o.ID)
+}
+
+// UpdateOrder 修改证书申请，域名变化且启用时在后台重新申请
+func UpdateOrder(id uint, spec OrderSpec) (OrderInfo, error) {
+	if err := checkOrder(&spec); err != nil {
+		return OrderInfo{}, err
+	}
+
+	configMu.Lock()
+	_, o := findOrder(id)
+	if o == nil {
+		configMu.Unlock()
+		return OrderInfo{}, ErrOrderNotFound
+	}
+	domainsChanged := strings.Join(o.Domains, ",") != strings.Join(spec.Domains, ",")
+	o.OrderSpec = spec
+	o.UpdatedAt = time.Now()
+	err := saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return OrderInfo{}, err
+	}
+
+	if spec.Enabled && domainsChanged {
+		go issueOrder(id)
+	}
+	return GetOrder(id)
+}
+
+// DeleteOrder 删除证书申请，已签发的证书保留在证书管理中
+func DeleteOrder(id uint) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index, o := findOrder(id)
+	if o == nil {
+		return ErrOrderNotFound
+	}
+	orders := config.Orders
+	config.Orders = append(orders[:index:index], orders[index+1:]...)
+	removeOrderState(id)
+	return saveConfig()
+}
@@ -0,0 +1,56 @@
+package acme
+
+import (
+	"linkstar/modules/certs"
+	"linkstar/modules/ddns"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 检查续期的间隔
+const renewCheckInterval = time.Hour
+
+// Init 读取 ACME 配置并启动自动续期，需在 ddns.Init 与 certs.Init 之后调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取 ACME 配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+
+	ddns.ProviderInUse = append(ddns.ProviderInUse, providerInUse)
+	certs.InUse = append(certs.InUse, certInUse)
+
+	go func() {
+		for {
+			checkRenew()
+			time.Sleep(renewCheckInterval)
+		}
+	}()
+}
+
+// 申请缺少证书或即将到期的证书申请，失败的申请在下一轮重试
+func checkRenew() {
+	configMu.Lock()
+	var due []uint
+	renewBefore := config.Settings.renewBefore()
+	for _, o := range config.Orders {
+		if !o.Enabled {
+			continue
+		}
+		if o.CertID != 0 {
+			if c, err := certs.GetCert(o.CertID); err == nil && time.Now().Before(c.NotAfter.Add(-renewBefore)) {
+				continue
+			}
+		}
+		due = append(due, o.ID)
+	}
+	configMu.Unlock()
+
+	for _, id := range due {
+		issueOrder(id)
+	}
+}
@@ -0,0 +1,365 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"linkstar/modules/certs"
+	"linkstar/modules/ddns"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	xacme "golang.org/x/crypto/acme"
+)
+
+const accountKeyPath = "config/acme/account.key"
+
+// 申请状态
+const (
+	StateIdle    = "idle"    // 未申请
+	StateIssuing = "issuing" // 申请中
+	StateValid   = "valid"   // 已签发
+	StateFailed  = "failed"  // 上次申请失败，等待重试
+)
+
+// OrderStatus 证书申请的运行状态，不持久化
+type OrderStatus struct {
+	State         string    `json:"state"`                   // idle / issuing / valid / failed
+	NotAfter      time.Time `json:"notAfter,omitempty"`      // 当前证书到期时间
+	NextRenewAt   time.Time `json:"nextRenewAt,omitempty"`   // 计划续期时间
+	LastAttemptAt time.Time `json:"lastAttemptAt,omitempty"` // 上次申请时间
+	LastSuccessAt time.Time `json:"lastSuccessAt,omitempty"` // 上次签发成功时间
+	LastError     string    `json:"lastError,omitempty"`     // 上次失败原因
+}
+
+// OrderInfo 证书申请及其状态
+type OrderInfo struct {
+	Order
+	Status OrderStatus `json:"status"`
+}
+
+var (
+	stateMu sync.Mutex
+	states  = map[uint]*OrderStatus{}
+)
+
+func getState(id uint) *OrderStatus {
+	s, ok := states[id]
+	if !ok {
+		s = &OrderStatus{State: StateIdle}
+		states[id] = s
+	}
+	return s
+}
+
+func removeOrderState(id uint) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	delete(states, id)
+}
+
+// 申请状态，补充当前证书的到期与续期时间
+func orderStatus(id uint) OrderStatus {
+	stateMu.Lock()
+	s := *getState(id)
+	stateMu.Unlock()
+
+	configMu.Lock()
+	_, o := findOrder(id)
+	var certID uint
+	if o != nil {
+		certID = o.CertID
+	}
+	renewBefore := config.Settings.renewBefore()
+	configMu.Unlock()
+
+	if certID != 0 {
+		if c, err := certs.GetCert(certID); err == nil {
+			s.NotAfter = c.NotAfter
+			s.NextRenewAt = c.NotAfter.Add(-renewBefore)
+			if s.State == StateIdle {
+				s.State = StateValid
+			}
+		}
+	}
+	return s
+}
+
+// 标记开始申请，已在申请中时返回 false
+func beginIssue(id uint) bool {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s := getState(id)
+	if s.State == StateIssuing {
+		return false
+	}
+	s.State = StateIssuing
+	s.LastAttemptAt = time.Now()
+	return true
+}
+
+func endIssue(id uint, err error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s := getState(id)
+	if err != nil {
+		s.State = StateFailed
+		s.LastError = err.Error()
+		return
+	}
+	s.State = StateValid
+	s.LastError = ""
+	s.LastSuccessAt = time.Now()
+}
+
+// IssueOrder 在后台立即申请（或续期）证书
+func IssueOrder(id uint) error {
+	configMu.Lock()
+	_, o := findOrder(id)
+	configMu.Unlock()
+	if o == nil {
+		return ErrOrderNotFound
+	}
+
+	stateMu.Lock()
+	issuing := getState(id).State == StateIssuing
+	stateMu.Unlock()
+	if issuing {
+		return ErrIssuing
+	}
+	go issueOrder(id)
+	return nil
+}
+
+// 申请证书并保存到证书管理，已有证书时原地替换，使用该证书的服务即时生效
+func issueOrder(id uint) {
+	if !beginIssue(id) {
+		return
+	}
+
+	configMu.Lock()
+	_, o := findOrder(id)
+	var order Order
+	if o != nil {
+		order = *o
+	}
+	settings := config.Settings
+	configMu.Unlock()
+	if o == nil {
+		removeOrderState(id)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	certID, err := obtain(ctx, settings, order)
+	if err == nil {
+		configMu.Lock()
+		if _, o := findOrder(id); o != nil && o.CertID != certID {
+			o.CertID = certID
+			o.UpdatedAt = time.Now()
+			err = saveConfig()
+		}
+		configMu.Unlock()
+	}
+	endIssue(id, err)
+	if err != nil {
+		logrus.Errorf("ACME 证书 %s 申请失败: %v", order.Name, err)
+		return
+	}
+	logrus.Infof("ACME 证书 %s 签发成功 %v", order.Name, order.Domains)
+}
+
+// 按ID获取写入 TXT 记录的 DNS 服务商，测试时替换
+var providerByID = ddns.ProviderByID
+
+// 完成一次 DNS-01 申请，返回保存后的证书ID
+func obtain(ctx context.Context, settings Settings, order Order) (uint, error) {
+	provider, err := providerByID(order.ProviderID)
+	if err != nil {
+		return 0, err
+	}
+	client, err := newClient(ctx, settings)
+	if err != nil {
+		return 0, err
+	}
+
+	o, err := client.AuthorizeOrder(ctx, xacme.DomainIDs(order.Domains...))
+	if err != nil {
+		return 0, fmt.Errorf("创建订单失败: %w", err)
+	}
+	for _, authzURL := range o.AuthzURLs {
+		if err := authorize(ctx, client, provider, settings, order.Zone, authzURL); err != nil {
+			return 0, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return 0, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: strings.TrimPrefix(order.Domains[0], "*.")},
+		DNSNames: order.Domains,
+	}, key)
+	if err != nil {
+		return 0, err
+	}
+	if o, err = client.WaitOrder(ctx, o.URI); err != nil {
+		return 0, fmt.Errorf("等待订单就绪失败: %w", err)
+	}
+	der, _, err := client.CreateOrderCert(ctx, o.FinalizeURL, csr, true)
+	if err != nil {
+		return 0, fmt.Errorf("签发证书失败: %w", err)
+	}
+
+	var certPEM []byte
+	for _, b := range der {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b})...)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return 0, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	c, err := certs.PutCert(order.CertID, order.Name, certs.SourceACME, certPEM, keyPEM)
+	if errors.Is(err, certs.ErrCertNotFound) {
+		// 证书已被删除，重新保存为新证书
+		c, err = certs.PutCert(0, order.Name, certs.SourceACME, certPEM, keyPEM)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return c.ID, nil
+}
+
+// 通过 DNS-01 完成一个域名的授权，结束后删除 TXT 记录
+func authorize(ctx context.Context, client *xacme.Client, provider ddns.Provider, settings Settings, zone, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("获取授权失败: %w", err)
+	}
+	if authz.Status == xacme.StatusValid {
+		return nil
+	}
+
+	var chal *xacme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("%s 不支持 DNS-01 验证", authz.Identifier.Value)
+	}
+	value, err := client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	rec := ddns.Record{
+		Zone:  zone,
+		Name:  "_acme-challenge." + strings.TrimPrefix(authz.Identifier.Value, "*."),
+		Type:  "TXT",
+		Value: value,
+		TTL:   60,
+	}
+	if err := provider.Upsert(ctx, rec); err != nil {
+		return fmt.Errorf("写入 %s TXT 记录失败: %w", rec.Name, err)
+	}
+	defer func() {
+		// 验证结束后清理，使用独立的 context 避免超时后无法删除
+		cleanCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := provider.Delete(cleanCtx, rec); err != nil {
+			logrus.Warnf("删除 %s TXT 记录失败: %v", rec.Name, err)
+		}
+	}()
+
+	select {
+	case <-time.After(settings.propagationWait()):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if _, err := client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("提交验证失败: %w", err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("%s 验证失败: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// 创建 ACME 客户端并注册账户，账户已存在时直接复用
+func newClient(ctx context.Context, settings Settings) (*xacme.Client, error) {
+	key, err := loadAccountKey()
+	if err != nil {
+		return nil, err
+	}
+	client := &xacme.Client{
+		Key:          key,
+		DirectoryURL: settings.directoryURL(),
+		UserAgent:    "linkstar",
+	}
+	if settings.SkipTLSVerify {
+		client.HTTPClient = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}
+	}
+
+	account := &xacme.Account{}
+	if settings.Email != "" {
+		account.Contact = []string{"mailto:" + settings.Email}
+	}
+	if _, err := client.Register(ctx, account, xacme.AcceptTOS); err != nil && !errors.Is(err, xacme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("注册 ACME 账户失败: %w", err)
+	}
+	return client, nil
+}
+
+// 读取账户私钥，不存在时生成
+func loadAccountKey() (crypto.Signer, error) {
+	if data, err := os.ReadFile(accountKeyPath); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("账户私钥 %s 格式错误", accountKeyPath)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(accountKeyPath), 0700); err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(accountKeyPath, data, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,161 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"linkstar/modules/certs"
+	"linkstar/modules/ddns"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// 端到端测试需要本地运行 Pebble（https://github.com/letsencrypt/pebble）：
+//
+//	PEBBLE_VA_ALWAYS_VALID=1 pebble -config test/config/pebble-config.json
+//	LINKSTAR_TEST_PEBBLE=https://localhost:14000/dir go test ./modules/acme
+//
+// 不跳过验证时同时运行 pebble-challtestsrv 并将 Pebble 的 -dnsserver 指向它，
+// 再设置 LINKSTAR_TEST_CHALLTESTSRV=http://localhost:8055，TXT 记录会写入其中
+const (
+	pebbleEnv        = "LINKSTAR_TEST_PEBBLE"
+	challtestsrvEnv  = "LINKSTAR_TEST_CHALLTESTSRV"
+	testZone         = "linkstar.test"
+	testDomain       = "www." + testZone
+	testChallengeRec = "_acme-challenge." + testDomain
+)
+
+// stubProvider 记录收到的 TXT 记录，设置了 challtestsrv 时同步写入
+type stubProvider struct {
+	challtestsrv string
+
+	mu      sync.Mutex
+	upserts []ddns.Record
+	deletes []ddns.Record
+}
+
+func (p *stubProvider) Upsert(ctx context.Context, rec ddns.Record) error {
+	p.mu.Lock()
+	p.upserts = append(p.upserts, rec)
+	p.mu.Unlock()
+	return p.challtest(ctx, "set-txt", map[string]string{"host": rec.Name + ".", "value": rec.Value})
+}
+
+func (p *stubProvider) Delete(ctx context.Context, rec ddns.Record) error {
+	p.mu.Lock()
+	p.deletes = append(p.deletes, rec)
+	p.mu.Unlock()
+	return p.challtest(ctx, "clear-txt", map[string]string{"host": rec.Name + "."})
+}
+
+func (p *stubProvider) challtest(ctx context.Context, path string, body map[string]string) error {
+	if p.challtestsrv == "" {
+		return nil
+	}
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.challtestsrv, "/")+"/"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("challtestsrv %s: HTTP %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *stubProvider) counts() (upserts, deletes int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.upserts), len(p.deletes)
+}
+
+func TestIssueWithPebble(t *testing.T) {
+	directory := os.Getenv(pebbleEnv)
+	if directory == "" {
+		t.Skipf("未设置 %s，跳过 ACME 端到端测试", pebbleEnv)
+	}
+	t.Chdir(t.TempDir())
+	certs.Init()
+
+	provider := &stubProvider{challtestsrv: os.Getenv(challtestsrvEnv)}
+	providerByID = func(id uint) (ddns.Provider, error) {
+		if id != 1 {
+			return nil, ddns.ErrProviderNotFound
+		}
+		return provider, nil
+	}
+	t.Cleanup(func() { providerByID = ddns.ProviderByID })
+
+	configMu.Lock()
+	config = Config{
+		Settings: Settings{DirectoryURL: directory, SkipTLSVerify: true, PropagationWait: 1},
+		Orders: []*Order{{ID: 1, OrderSpec: OrderSpec{
+			Name: "pebble", Domains: []string{testDomain}, ProviderID: 1, Zone: testZone, Enabled: true,
+		}}},
+	}
+	configMu.Unlock()
+
+	// 没有证书时申请
+	checkRenew()
+	info, err := GetOrder(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status.State != StateValid || info.CertID == 0 {
+		t.Fatalf("order = %+v", info)
+	}
+	cert, err := certs.GetCert(info.CertID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Source != certs.SourceACME || !slices.Contains(cert.Domains, testDomain) || !info.Status.NotAfter.Equal(cert.NotAfter) {
+		t.Fatalf("cert = %+v", cert)
+	}
+
+	// 验证时写入并在结束后删除 TXT 记录
+	provider.mu.Lock()
+	upserts, deletes := provider.upserts, provider.deletes
+	provider.mu.Unlock()
+	if len(upserts) != 1 || upserts[0].Type != "TXT" || upserts[0].Name != testChallengeRec || upserts[0].Zone != testZone || upserts[0].Value == "" {
+		t.Fatalf("upserts = %+v", upserts)
+	}
+	if len(deletes) != 1 || deletes[0].Name != testChallengeRec {
+		t.Fatalf("deletes = %+v", deletes)
+	}
+
+	// 未到续期时间不再申请
+	checkRenew()
+	if n, _ := provider.counts(); n != 1 {
+		t.Fatalf("checkRenew issued again before renewal time (%d challenges)", n)
+	}
+
+	// 进入续期窗口后原地替换证书
+	configMu.Lock()
+	config.Settings.RenewBeforeDays = 100000
+	configMu.Unlock()
+	checkRenew()
+	renewed, err := GetOrder(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.CertID != info.CertID || renewed.Status.State != StateValid {
+		t.Fatalf("renewed order = %+v", renewed)
+	}
+	newCert, _ := certs.GetCert(renewed.CertID)
+	if newCert.Fingerprint == cert.Fingerprint {
+		t.Fatal("certificate was not replaced on renewal")
+	}
+	if list := certs.ListCerts(); len(list) != 1 {
+		t.Fatalf("renewal should replace in place, got %d certs", len(list))
+	}
+}
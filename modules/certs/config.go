@@ -0,0 +1,256 @@
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	certsConfigPath = "config/certs.json"
+	certsDir        = "config/certs"
+)
+
+// 证书来源
+const (
+	SourceUpload     = "upload"     // 上传的证书
+	SourceSelfSigned = "selfsigned" // 自签名证书
+	SourceLocalCA    = "localca"    // 本地 CA 签发
+	SourceACME       = "acme"       // ACME 签发（DNS-01）
+)
+
+var (
+	ErrCertNotFound = errors.New("证书不存在")
+	ErrCertInUse    = errors.New("证书正在被服务使用")
+	ErrInvalidCert  = errors.New("证书或私钥不合法")
+	ErrSaveConfig   = errors.New("保存证书失败")
+)
+
+// Config 证书配置文件（config/certs.json），证书与私钥保存在 config/certs/<id>.crt / <id>.key
+type Config struct {
+	Certs []*Cert `json:"certs"`
+}
+
+// Cert 证书信息
+type Cert struct {
+	ID          uint      `json:"id"`          // 证书ID
+	Name        string    `json:"name"`        // 名称
+	Source      string    `json:"source"`      // 来源：upload / selfsigned / localca / acme
+	Domains     []string  `json:"domains"`     // 证书包含的域名与 IP
+	Issuer      string    `json:"issuer"`      // 签发者
+	NotBefore   time.Time `json:"notBefore"`   // 生效时间
+	NotAfter    time.Time `json:"notAfter"`    // 过期时间
+	Fingerprint string    `json:"fingerprint"` // SHA-256 指纹
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(certsConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](certsConfigPath); err != nil {
+			return c, err
+		}
+	}
+	if c.Certs == nil {
+		c.Certs = []*Cert{}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(certsConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(certsConfigPath, config); err != nil {
+		logrus.Error("证书配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+func certPath(id uint) string { return filepath.Join(certsDir, fmt.Sprintf("%d.crt", id)) }
+func keyPath(id uint) string  { return filepath.Join(certsDir, fmt.Sprintf("%d.key", id)) }
+
+// 写入证书与私钥文件，私钥仅本用户可读
+func writePair(id uint, certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(certsDir, 0700); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := os.WriteFile(certPath(id), certPEM, 0644); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := os.WriteFile(keyPath(id), keyPEM, 0600); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 解析证书与私钥，返回可用于握手的证书和叶子证书信息
+func parsePair(certPEM, keyPEM []byte) (*tls.Certificate, *x509.Certificate, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCert, err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCert, err)
+	}
+	pair.Leaf = leaf
+	return &pair, leaf, nil
+}
+
+// 用叶子证书填充证书信息
+func (c *Cert) fill(leaf *x509.Certificate) {
+	c.Domains = append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		c.Domains = append(c.Domains, ip.String())
+	}
+	c.Issuer = leaf.Issuer.CommonName
+	c.NotBefore = leaf.NotBefore
+	c.NotAfter = leaf.NotAfter
+	sum := sha256.Sum256(leaf.Raw)
+	c.Fingerprint = hex.EncodeToString(sum[:])
+}
+
+func findCert(id uint) (int, *Cert) {
+	for i, c := range config.Certs {
+		if c.ID == id {
+			return i, c
+		}
+	}
+	return -1, nil
+}
+
+// InUse 其他模块注册的引用检查，删除证书前调用
+var InUse []func(id uint) bool
+
+// Exists 证书是否存在
+func Exists(id uint) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+	_, c := findCert(id)
+	return c != nil
+}
+
+// ListCerts 全部证书
+func ListCerts() []Cert {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	list := make([]Cert, 0, len(config.Certs))
+	for _, c := range config.Certs {
+		list = append(list, *c)
+	}
+	return list
+}
+
+// GetCert 按ID查找证书
+func GetCert(id uint) (Cert, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, c := findCert(id)
+	if c == nil {
+		return Cert{}, ErrCertNotFound
+	}
+	return *c, nil
+}
+
+// PutCert 保存证书，id 为 0 时新增，否则替换已有证书（正在使用它的服务在下次握手时生效）
+// name 为空时保留原名称
+func PutCert(id uint, name, source string, certPEM, keyPEM []byte) (Cert, error) {
+	pair, leaf, err := parsePair(certPEM, keyPEM)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var c *Cert
+	if id == 0 {
+		var maxID uint = 0
+		for _, c := range config.Certs {
+			if c.ID > maxID {
+				maxID = c.ID
+			}
+		}
+		c = &Cert{ID: maxID + 1, CreatedAt: time.Now()}
+	} else if _, c = findCert(id); c == nil {
+		return Cert{}, ErrCertNotFound
+	}
+
+	if err := writePair(c.ID, certPEM, keyPEM); err != nil {
+		return Cert{}, err
+	}
+	if name != "" {
+		c.Name = name
+	}
+	c.Source = source
+	c.fill(leaf)
+	c.UpdatedAt = time.Now()
+	if id == 0 {
+		config.Certs = append(config.Certs, c)
+	}
+	if err := saveConfig(); err != nil {
+		return Cert{}, err
+	}
+	setCached(c.ID, pair)
+	return *c, nil
+}
+
+// UploadCert 上传证书（PEM，证书可包含中间证书链）
+func UploadCert(name string, certPEM, keyPEM []byte) (Cert, error) {
+	return PutCert(0, name, SourceUpload, certPEM, keyPEM)
+}
+
+// ReplaceCert 替换证书内容，来源改为 upload
+func ReplaceCert(id uint, certPEM, keyPEM []byte) (Cert, error) {
+	return PutCert(id, "", SourceUpload, certPEM, keyPEM)
+}
+
+// DeleteCert 删除证书，仍被使用时返回 ErrCertInUse
+func DeleteCert(id uint) error {
+	for _, inUse := range InUse {
+		if inUse(id) {
+			return ErrCertInUse
+		}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index, c := findCert(id)
+	if c == nil {
+		return ErrCertNotFound
+	}
+	certs := config.Certs
+	config.Certs = append(certs[:index:index], certs[index+1:]...)
+	if err := saveConfig(); err != nil {
+		return err
+	}
+	os.Remove(certPath(id))
+	os.Remove(keyPath(id))
+	setCached(id, nil)
+	return nil
+}
@@ -0,0 +1,14 @@
+package certs
+
+import "github.com/sirupsen/logrus"
+
+// Init 读取证书配置，需在启动服务前调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取证书配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+}
@@ -0,0 +1,186 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	caCertPath = filepath.Join(certsDir, "ca.crt")
+	caKeyPath  = filepath.Join(certsDir, "ca.key")
+)
+
+const defaultValidDays = 365
+
+// 生成 ECDSA P-256 私钥
+func newKey() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// 服务端证书模板，domains 中的 IP 写入 IP SAN，其余写入 DNS SAN
+func leafTemplate(domains []string, days int) (*x509.Certificate, error) {
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = defaultValidDays
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domains[0], Organization: []string{"LinkStar"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 0, days),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, d := range domains {
+		if ip := net.ParseIP(d); ip != nil {
+			tpl.IPAddresses = append(tpl.IPAddresses, ip)
+		} else {
+			tpl.DNSNames = append(tpl.DNSNames, strings.ToLower(d))
+		}
+	}
+	return tpl, nil
+}
+
+// 签发证书，parent 为 nil 时自签名
+func issue(domains []string, days int, parent *x509.Certificate, parentKey crypto.Signer) (certPEM, keyPEM []byte, err error) {
+	key, keyPEM, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	tpl, err := leafTemplate(domains, days)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parent == nil {
+		parent, parentKey = tpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return certPEM, keyPEM, nil
+}
+
+var caMu sync.Mutex
+
+// 读取本地 CA，不存在时生成（有效期 10 年）
+func loadCA() (*x509.Certificate, crypto.Signer, []byte, error) {
+	caMu.Lock()
+	defer caMu.Unlock()
+
+	certPEM, err1 := os.ReadFile(caCertPath)
+	keyPEM, err2 := os.ReadFile(caKeyPath)
+	if err1 == nil && err2 == nil {
+		pair, leaf, err := parsePair(certPEM, keyPEM)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("本地 CA 已损坏: %w", err)
+		}
+		return leaf, pair.PrivateKey.(crypto.Signer), certPEM, nil
+	}
+	if !errors.Is(err1, os.ErrNotExist) && err1 != nil {
+		return nil, nil, nil, err1
+	}
+
+	key, keyPEM, err := newKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	hostname, _ := os.Hostname()
+	tpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "LinkStar Local CA " + hostname, Organization: []string{"LinkStar"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, key.Public(), key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.MkdirAll(certsDir, 0700); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := os.WriteFile(caKeyPath, keyPEM, 0600); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := os.WriteFile(caCertPath, certPEM, 0644); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return ca, key, certPEM, nil
+}
+
+// CACertPEM 本地 CA 证书（PEM），导入到客户端信任列表后，本地 CA 签发的证书即被信任
+func CACertPEM() ([]byte, error) {
+	_, _, certPEM, err := loadCA()
+	return certPEM, err
+}
+
+// GenerateCert 生成自签名证书或由本地 CA 签发证书，days 为 0 时有效期 365 天
+func GenerateCert(name, source string, domains []string, days int) (Cert, error) {
+	if len(domains) == 0 {
+		return Cert{}, fmt.Errorf("%w: 至少需要一个域名或 IP", ErrInvalidCert)
+	}
+
+	var certPEM, keyPEM []byte
+	var err error
+	switch source {
+	case SourceSelfSigned:
+		certPEM, keyPEM, err = issue(domains, days, nil, nil)
+	case SourceLocalCA:
+		ca, caKey, caPEM, caErr := loadCA()
+		if caErr != nil {
+			return Cert{}, caErr
+		}
+		certPEM, keyPEM, err = issue(domains, days, ca, caKey)
+		// 附带 CA 证书，便于客户端校验证书链
+		certPEM = append(certPEM, caPEM...)
+	default:
+		return Cert{}, fmt.Errorf("%w: 不支持的来源 %s", ErrInvalidCert, source)
+	}
+	if err != nil {
+		return Cert{}, err
+	}
+	return PutCert(0, name, source, certPEM, keyPEM)
+}
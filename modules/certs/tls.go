@@ -0,0 +1,80 @@
+package certs
+
+import (
+	"crypto/tls"
+	"linkstar/global"
+	"os"
+	"sync"
+)
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[uint]*tls.Certificate)
+
+	defaultOnce sync.Once
+	defaultCert *tls.Certificate
+	defaultErr  error
+)
+
+func setCached(id uint, pair *tls.Certificate) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if pair == nil {
+		delete(cache, id)
+		return
+	}
+	cache[id] = pair
+}
+
+// Certificate 按ID取证书，id 为 0 时返回进程内生成的默认自签名证书
+func Certificate(id uint) (*tls.Certificate, error) {
+	if id == 0 {
+		defaultOnce.Do(func() {
+			domains := []string{"localhost"}
+			if ip := global.StunConfig.PublicIP; ip != "" {
+				domains = append(domains, ip)
+			}
+			certPEM, keyPEM, err := issue(domains, 0, nil, nil)
+			if err == nil {
+				defaultCert, _, err = parsePair(certPEM, keyPEM)
+			}
+			defaultErr = err
+		})
+		return defaultCert, defaultErr
+	}
+
+	cacheMu.Lock()
+	pair, ok := cache[id]
+	cacheMu.Unlock()
+	if ok {
+		return pair, nil
+	}
+	if !Exists(id) {
+		return nil, ErrCertNotFound
+	}
+
+	certPEM, err := os.ReadFile(certPath(id))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyPath(id))
+	if err != nil {
+		return nil, err
+	}
+	pair, _, err = parsePair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	setCached(id, pair)
+	return pair, nil
+}
+
+// ServerConfig TLS 终止使用的配置，每次握手时取证书，证书替换后新连接立即使用新证书
+func ServerConfig(id uint) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return Certificate(id)
+		},
+	}
+}
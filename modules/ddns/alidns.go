@@ -0,0 +1,153 @@
+package ddns
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// aliDNS 阿里云解析 OpenAPI（RPC 风格，HMAC-SHA1 签名）
+type aliDNS struct {
+	cfg ProviderConfig
+}
+
+type aliRecord struct {
+	RecordID string `json:"RecordId"`
+	RR       string `json:"RR"`
+	Type     string `json:"Type"`
+	Value    string `json:"Value"`
+}
+
+type aliResponse struct {
+	Code          string `json:"Code"`
+	Message       string `json:"Message"`
+	DomainRecords struct {
+		Record []aliRecord `json:"Record"`
+	} `json:"DomainRecords"`
+}
+
+// 阿里云要求的 URL 编码：空格为 %20，* 为 %2A，~ 不编码
+func aliEscape(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+func (p *aliDNS) request(ctx context.Context, action string, params map[string]string) (*aliResponse, error) {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+
+	query := map[string]string{
+		"Action":           action,
+		"Format":           "JSON",
+		"Version":          "2015-01-09",
+		"AccessKeyId":      p.cfg.AccessKeyID,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   hex.EncodeToString(nonce),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, aliEscape(k)+"="+aliEscape(query[k]))
+	}
+	canonical := strings.Join(pairs, "&")
+
+	mac := hmac.New(sha1.New, []byte(p.cfg.AccessKeySecret+"&"))
+	mac.Write([]byte("GET&%2F&" + aliEscape(canonical)))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	base := endpointOr(p.cfg.Endpoint, "https://alidns.aliyuncs.com")
+	req, err := http.NewRequest(http.MethodGet, base+"/?"+canonical+"&Signature="+aliEscape(signature), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp aliResponse
+	if err := doJSON(ctx, req, &resp); err != nil {
+		if resp.Code != "" {
+			return nil, fmt.Errorf("alidns: %s %s", resp.Code, resp.Message)
+		}
+		return nil, fmt.Errorf("alidns: %w", err)
+	}
+	return &resp, nil
+}
+
+func (p *aliDNS) records(ctx context.Context, rec Record) ([]aliRecord, error) {
+	resp, err := p.request(ctx, "DescribeSubDomainRecords", map[string]string{
+		"SubDomain":  rec.Name,
+		"DomainName": rec.Zone,
+		"Type":       rec.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.DomainRecords.Record, nil
+}
+
+func (p *aliDNS) Upsert(ctx context.Context, rec Record) error {
+	existing, err := p.records(ctx, rec)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{
+		"RR":    rec.RR(),
+		"Type":  rec.Type,
+		"Value": rec.content(),
+	}
+	if rec.TTL > 0 {
+		params["TTL"] = strconv.Itoa(rec.TTL)
+	}
+
+	if len(existing) == 0 {
+		params["DomainName"] = rec.Zone
+		_, err = p.request(ctx, "AddDomainRecord", params)
+		return err
+	}
+	for _, r := range existing[1:] {
+		if _, err := p.request(ctx, "DeleteDomainRecord", map[string]string{"RecordId": r.RecordID}); err != nil {
+			return err
+		}
+	}
+	// 值未变化时阿里云会返回 DomainRecordDuplicate，直接跳过
+	if existing[0].Value == rec.content() {
+		return nil
+	}
+	params["RecordId"] = existing[0].RecordID
+	_, err = p.request(ctx, "UpdateDomainRecord", params)
+	return err
+}
+
+func (p *aliDNS) Delete(ctx context.Context, rec Record) error {
+	existing, err := p.records(ctx, rec)
+	if err != nil {
+		return err
+	}
+	for _, r := range existing {
+		if _, err := p.request(ctx, "DeleteDomainRecord", map[string]string{"RecordId": r.RecordID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
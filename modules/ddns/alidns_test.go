@@ -0,0 +1,97 @@
+package ddns
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 本地模拟的阿里云解析 API，校验 HMAC-SHA1 签名
+func newAliDNSServer(t *testing.T, keyID, secret string) (*httptest.Server, *fakeZone) {
+	zone := &fakeZone{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		fail := func(code, msg string) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"Code": code, "Message": msg})
+		}
+
+		keys := make([]string, 0, len(q))
+		for k := range q {
+			if k != "Signature" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, aliEscape(k)+"="+aliEscape(q.Get(k)))
+		}
+		mac := hmac.New(sha1.New, []byte(secret+"&"))
+		mac.Write([]byte("GET&%2F&" + aliEscape(strings.Join(pairs, "&"))))
+		if q.Get("AccessKeyId") != keyID || q.Get("Signature") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			fail("SignatureDoesNotMatch", "Specified signature is not matched with our calculation.")
+			return
+		}
+
+		name := func() string {
+			if rr := q.Get("RR"); rr != "@" {
+				return rr + "." + "example.com"
+			}
+			return "example.com"
+		}
+		switch q.Get("Action") {
+		case "DescribeSubDomainRecords":
+			list := []aliRecord{}
+			for _, rec := range zone.list(q.Get("SubDomain"), q.Get("Type")) {
+				list = append(list, aliRecord{RecordID: rec.ID, Type: rec.Type, Value: rec.Value})
+			}
+			resp := map[string]any{"DomainRecords": map[string]any{"Record": list}}
+			json.NewEncoder(w).Encode(resp)
+		case "AddDomainRecord":
+			id := zone.add(fakeRecord{Name: name(), Type: q.Get("Type"), Value: q.Get("Value")})
+			json.NewEncoder(w).Encode(map[string]string{"RecordId": id})
+		case "UpdateDomainRecord":
+			if !zone.update(q.Get("RecordId"), func(rec *fakeRecord) { rec.Name, rec.Value = name(), q.Get("Value") }) {
+				fail("DomainRecordNotBelongToUser", "record not found")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"RecordId": q.Get("RecordId")})
+		case "DeleteDomainRecord":
+			if !zone.remove(q.Get("RecordId")) {
+				fail("DomainRecordNotBelongToUser", "record not found")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"RecordId": q.Get("RecordId")})
+		default:
+			fail("InvalidAction", q.Get("Action"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, zone
+}
+
+func TestAliDNSUpsertDelete(t *testing.T) {
+	srv, zone := newAliDNSServer(t, "ak", "sk")
+	p, err := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeAliDNS, Endpoint: srv.URL, AccessKeyID: "ak", AccessKeySecret: "sk"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testUpsertDelete(t, p, zone)
+}
+
+func TestAliDNSBadSignature(t *testing.T) {
+	srv, _ := newAliDNSServer(t, "ak", "sk")
+	p, _ := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeAliDNS, Endpoint: srv.URL, AccessKeyID: "ak", AccessKeySecret: "wrong"}})
+
+	err := p.Upsert(testContext(t), Record{Zone: "example.com", Name: "a.example.com", Type: "A", Value: "1.1.1.1"})
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Fatalf("err = %v, want SignatureDoesNotMatch", err)
+	}
+}
@@ -0,0 +1,141 @@
+package ddns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// cloudflare Cloudflare API v4，使用 API Token 鉴权
+type cloudflare struct {
+	cfg ProviderConfig
+}
+
+type cfResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+type cfRecord struct {
+	ID      string         `json:"id,omitempty"`
+	Type    string         `json:"type"`
+	Name    string         `json:"name"`
+	Content string         `json:"content,omitempty"`
+	TTL     int            `json:"ttl"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+func (p *cloudflare) request(ctx context.Context, method, path string, body any, result any) error {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	base := endpointOr(p.cfg.Endpoint, "https://api.cloudflare.com/client/v4")
+	req, err := http.NewRequest(method, base+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp cfResponse
+	if err := doJSON(ctx, req, &resp); err != nil && len(resp.Errors) == 0 {
+		return fmt.Errorf("cloudflare: %w", err)
+	}
+	if !resp.Success {
+		msgs := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%d %s", e.Code, e.Message))
+		}
+		return fmt.Errorf("cloudflare: %s", strings.Join(msgs, "; "))
+	}
+	if result != nil {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
+
+func (p *cloudflare) zoneID(ctx context.Context, zone string) (string, error) {
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	if err := p.request(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(zone), nil, &zones); err != nil {
+		return "", err
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("cloudflare: 未找到域名 %s", zone)
+	}
+	return zones[0].ID, nil
+}
+
+func (p *cloudflare) records(ctx context.Context, zoneID string, rec Record) ([]cfRecord, error) {
+	query := url.Values{"type": {rec.Type}, "name": {rec.Name}}
+	var records []cfRecord
+	err := p.request(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &records)
+	return records, err
+}
+
+func (p *cloudflare) Upsert(ctx context.Context, rec Record) error {
+	zoneID, err := p.zoneID(ctx, rec.Zone)
+	if err != nil {
+		return err
+	}
+	existing, err := p.records(ctx, zoneID, rec)
+	if err != nil {
+		return err
+	}
+
+	body := cfRecord{Type: rec.Type, Name: rec.Name, TTL: rec.TTL}
+	if body.TTL == 0 {
+		body.TTL = 1 // 自动
+	}
+	if rec.Type == "SRV" {
+		body.Data = map[string]any{
+			"priority": rec.Priority, "weight": rec.Weight, "port": rec.Port, "target": rec.Value,
+		}
+	} else {
+		body.Content = rec.Value
+	}
+
+	if len(existing) == 0 {
+		return p.request(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", body, nil)
+	}
+	// 多余的同名记录删除，只保留一条
+	for _, r := range existing[1:] {
+		if err := p.request(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+r.ID, nil, nil); err != nil {
+			return err
+		}
+	}
+	return p.request(ctx, http.MethodPut, "/zones/"+zoneID+"/dns_records/"+existing[0].ID, body, nil)
+}
+
+func (p *cloudflare) Delete(ctx context.Context, rec Record) error {
+	zoneID, err := p.zoneID(ctx, rec.Zone)
+	if err != nil {
+		return err
+	}
+	existing, err := p.records(ctx, zoneID, rec)
+	if err != nil {
+		return err
+	}
+	for _, r := range existing {
+		if err := p.request(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+r.ID, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package ddns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 本地模拟的 Cloudflare API v4
+func newCloudflareServer(t *testing.T, token string) (*httptest.Server, *fakeZone) {
+	zone := &fakeZone{}
+	reply := func(w http.ResponseWriter, code int, result any, errs ...string) {
+		resp := map[string]any{"success": len(errs) == 0, "errors": []any{}, "result": result}
+		for _, e := range errs {
+			resp["errors"] = append(resp["errors"].([]any), map[string]any{"code": 1000, "message": e})
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(resp)
+	}
+	toCF := func(r fakeRecord) cfRecord {
+		return cfRecord{ID: r.ID, Type: r.Type, Name: r.Name, Content: r.Value, TTL: r.TTL}
+	}
+	fromCF := func(body cfRecord) fakeRecord {
+		r := fakeRecord{Name: body.Name, Type: body.Type, Value: body.Content, TTL: body.TTL}
+		if body.Type == "SRV" {
+			d := body.Data
+			r.Value = fmt.Sprintf("%v %v %v %v", d["priority"], d["weight"], d["port"], d["target"])
+		}
+		return r
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			reply(w, http.StatusForbidden, nil, "Invalid API Token")
+			return
+		}
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && path == "/zones":
+			if r.URL.Query().Get("name") != "example.com" {
+				reply(w, http.StatusOK, []any{})
+				return
+			}
+			reply(w, http.StatusOK, []map[string]string{{"id": "z1"}})
+		case r.Method == http.MethodGet && path == "/zones/z1/dns_records":
+			list := []cfRecord{}
+			for _, rec := range zone.list(r.URL.Query().Get("name"), r.URL.Query().Get("type")) {
+				list = append(list, toCF(rec))
+			}
+			reply(w, http.StatusOK, list)
+		case r.Method == http.MethodPost && path == "/zones/z1/dns_records":
+			var body cfRecord
+			json.NewDecoder(r.Body).Decode(&body)
+			reply(w, http.StatusOK, map[string]string{"id": zone.add(fromCF(body))})
+		case strings.HasPrefix(path, "/zones/z1/dns_records/"):
+			id := strings.TrimPrefix(path, "/zones/z1/dns_records/")
+			ok := false
+			if r.Method == http.MethodPut {
+				var body cfRecord
+				json.NewDecoder(r.Body).Decode(&body)
+				ok = zone.update(id, func(rec *fakeRecord) { *rec = fromCF(body); rec.ID = id })
+			} else if r.Method == http.MethodDelete {
+				ok = zone.remove(id)
+			}
+			if !ok {
+				reply(w, http.StatusNotFound, nil, "Record not found")
+				return
+			}
+			reply(w, http.StatusOK, map[string]string{"id": id})
+		default:
+			reply(w, http.StatusNotFound, nil, "no route "+r.Method+" "+path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, zone
+}
+
+func TestCloudflareUpsertDelete(t *testing.T) {
+	srv, zone := newCloudflareServer(t, "tok")
+	p, err := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeCloudflare, Endpoint: srv.URL, Token: "tok"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testUpsertDelete(t, p, zone)
+}
+
+func TestCloudflareErrors(t *testing.T) {
+	srv, _ := newCloudflareServer(t, "tok")
+	ctx := testContext(t)
+
+	bad, _ := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeCloudflare, Endpoint: srv.URL, Token: "wrong"}})
+	err := bad.Upsert(ctx, Record{Zone: "example.com", Name: "a.example.com", Type: "A", Value: "1.1.1.1"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid API Token") {
+		t.Fatalf("err = %v, want API error message", err)
+	}
+
+	p, _ := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeCloudflare, Endpoint: srv.URL, Token: "tok"}})
+	err = p.Upsert(ctx, Record{Zone: "other.com", Name: "a.other.com", Type: "A", Value: "1.1.1.1"})
+	if err == nil || !strings.Contains(err.Error(), "other.com") {
+		t.Fatalf("err = %v, want zone not found", err)
+	}
+}
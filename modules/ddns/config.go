@@ -0,0 +1,455 @@
+package ddns
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const ddnsConfigPath = "config/ddns.json"
+
+var (
+	ErrProviderNotFound = errors.New("DNS 服务商不存在")
+	ErrProviderInUse    = errors.New("DNS 服务商正在被域名使用")
+	ErrDomainNotFound   = errors.New("域名不存在")
+	ErrInvalidConfig    = errors.New("配置不合法")
+	ErrSaveConfig       = errors.New("保存 DDNS 配置失败")
+)
+
+// invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true
+type invalidFieldError struct {
+	field string
+	msg   string
+}
+
+func (e *invalidFieldError) Error() string { return e.msg }
+func (e *invalidFieldError) Unwrap() error { return ErrInvalidConfig }
+
+func invalidField(field, format string, args ...any) error {
+	return &invalidFieldError{field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// InvalidField 配置不合法时对应的请求字段，其他错误返回空字符串
+func InvalidField(err error) string {
+	var fe *invalidFieldError
+	if errors.As(err, &fe) {
+		return fe.field
+	}
+	return ""
+}
+
+// Config DDNS 配置文件（config/ddns.json）
+type Config struct {
+	Providers []*ProviderConfig `json:"providers"` // DNS 服务商账号
+	Domains   []*Domain         `json:"domains"`   // 跟随公网IP更新的域名
+}
+
+// ProviderConfig DNS 服务商账号
+type ProviderConfig struct {
+	ID uint `json:"id"` // 服务商ID
+	ProviderSpec
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ProviderSpec 服务商中由用户配置的部分，不同类型使用不同的凭据字段
+type ProviderSpec struct {
+	Name     string `json:"name" binding:"required"`                                    // 名称
+	Type     string `json:"type" binding:"required,oneof=cloudflare alidns dnspod url"` // 类型
+	Endpoint string `json:"endpoint" binding:"omitempty,url"`                           // 覆盖 API 地址，为空使用官方地址（可指向本地模拟服务）
+
+	// 凭据在接口返回时显示为 Redacted，修改时留空或填 Redacted 表示保留原值
+	Token           string `json:"token"`           // Cloudflare API Token / DNSPod Token
+	TokenID         string `json:"tokenId"`         // DNSPod Token ID
+	AccessKeyID     string `json:"accessKeyId"`     // 阿里云 AccessKey ID
+	AccessKeySecret string `json:"accessKeySecret"` // 阿里云 AccessKey Secret
+
+	URL    string `json:"url"`                                           // url 类型：请求地址模板，如 https://x/update?host={{.Name}}&ip={{.Value}}
+	Method string `json:"method" binding:"omitempty,oneof=GET POST PUT"` // url 类型：请求方法，默认 GET
+	Body   string `json:"body"`                                          // url 类型：请求体模板
+}
+
+// Redacted 接口返回时代替已设置的凭据
+const Redacted = "******"
+
+// 凭据字段
+func (s *ProviderSpec) credentials() []*string {
+	return []*string{&s.Token, &s.TokenID, &s.AccessKeyID, &s.AccessKeySecret}
+}
+
+// 隐藏凭据后的副本，供接口返回
+func (p ProviderConfig) redacted() ProviderConfig {
+	for _, field := range p.credentials() {
+		if *field != "" {
+			*field = Redacted
+		}
+	}
+	return p
+}
+
+// 留空或为 Redacted 的凭据沿用 old 中已保存的值
+func (s *ProviderSpec) keepCredentials(old ProviderSpec) {
+	oldFields := old.credentials()
+	for i, field := range s.credentials() {
+		if *field == "" || *field == Redacted {
+			*field = *oldFields[i]
+		}
+	}
+}
+
+// Domain 跟随公网IP更新 A 记录的域名
+type Domain struct {
+	ID uint `json:"id"` // 域名ID
+	DomainSpec
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DomainSpec 域名中由用户配置的部分
+type DomainSpec struct {
+	ProviderID  uint   `json:"providerId" binding:"required"` // 使用的 DNS 服务商
+	Zone        string `json:"zone" binding:"required,fqdn"`  // 主域名，如 example.com
+	Name        string `json:"name" binding:"required,fqdn"`  // 完整域名，如 home.example.com，与主域名相同表示根域名
+	TTL         int    `json:"ttl" binding:"min=0,max=86400"` // TTL（秒），0 使用服务商默认值
+	MinInterval int    `json:"minInterval" binding:"min=0"`   // 两次更新的最小间隔（秒），0 为默认 60 秒，期间的变化合并为一次更新
+	Enabled     bool   `json:"enabled"`                       // 是否启用
+}
+
+const defaultMinInterval = 60 * time.Second
+
+func (d *DomainSpec) interval() time.Duration {
+	if d.MinInterval <= 0 {
+		return defaultMinInterval
+	}
+	return time.Duration(d.MinInterval) * time.Second
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(ddnsConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](ddnsConfigPath); err != nil {
+			return c, err
+		}
+	}
+	if c.Providers == nil {
+		c.Providers = []*ProviderConfig{}
+	}
+	if c.Domains == nil {
+		c.Domains = []*Domain{}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(ddnsConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(ddnsConfigPath, config); err != nil {
+		logrus.Error("DDNS 配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 检查服务商凭据是否齐全，并确认模板可以解析
+func checkProvider(spec ProviderSpec) error {
+	required := map[string][]string{
+		TypeCloudflare: {"token"},
+		TypeAliDNS:     {"accessKeyId", "accessKeySecret"},
+		TypeDNSPod:     {"tokenId", "token"},
+		TypeURL:        {"url"},
+	}
+	values := map[string]string{
+		"token":           spec.Token,
+		"tokenId":         spec.TokenID,
+		"accessKeyId":     spec.AccessKeyID,
+		"accessKeySecret": spec.AccessKeySecret,
+		"url":             spec.URL,
+	}
+	for _, field := range required[spec.Type] {
+		if values[field] == "" {
+			return invalidField(field, "%s 类型的服务商需要填写 %s", spec.Type, field)
+		}
+	}
+	if spec.Type != TypeURL {
+		return nil
+	}
+	if _, err := template.New("url").Parse(spec.URL); err != nil {
+		return invalidField("url", "url 模板语法错误: %v", err)
+	}
+	if _, err := template.New("body").Parse(spec.Body); err != nil {
+		return invalidField("body", "body 模板语法错误: %v", err)
+	}
+	return nil
+}
+
+// 检查域名配置，调用方需持有 configMu
+func checkDomain(spec *DomainSpec) error {
+	spec.Zone = strings.ToLower(strings.TrimSuffix(spec.Zone, "."))
+	spec.Name = strings.ToLower(strings.TrimSuffix(spec.Name, "."))
+	if spec.Name != spec.Zone && !strings.HasSuffix(spec.Name, "."+spec.Zone) {
+		return invalidField("name", "域名 %s 不属于主域名 %s", spec.Name, spec.Zone)
+	}
+	if _, p := findProvider(spec.ProviderID); p == nil {
+		return ErrProviderNotFound
+	}
+	return nil
+}
+
+func findProvider(id uint) (int, *ProviderConfig) {
+	for i, p := range config.Providers {
+		if p.ID == id {
+			return i, p
+		}
+	}
+	return -1, nil
+}
+
+func findDomain(id uint) (int, *Domain) {
+	for i, d := range config.Domains {
+		if d.ID == id {
+			return i, d
+		}
+	}
+	return -1, nil
+}
+
+// ProviderByID 按ID创建服务商客户端，供 DDNS、SRV、ACME 共用
+func ProviderByID(id uint) (Provider, error) {
+	configMu.Lock()
+	_, p := findProvider(id)
+	var cfg ProviderConfig
+	if p != nil {
+		cfg = *p
+	}
+	configMu.Unlock()
+
+	if p == nil {
+		return nil, ErrProviderNotFound
+	}
+	return NewProvider(cfg)
+}
+
+// ProviderInUse 其他模块注册的引用检查，删除服务商前调用
+var ProviderInUse []func(id uint) bool
+
+// ListProviders 全部服务商，凭据已隐藏
+func ListProviders() []ProviderConfig {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	list := make([]ProviderConfig, 0, len(config.Providers))
+	for _, p := range config.Providers {
+		list = append(list, p.redacted())
+	}
+	return list
+}
+
+// GetProvider 按ID查找服务商，凭据已隐藏
+func GetProvider(id uint) (ProviderConfig, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, p := findProvider(id)
+	if p == nil {
+		return ProviderConfig{}, ErrProviderNotFound
+	}
+	return p.redacted(), nil
+}
+
+// AddProvider 新增服务商
+func AddProvider(spec ProviderSpec) (ProviderConfig, error) {
+	if err := checkProvider(spec); err != nil {
+		return ProviderConfig{}, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var maxID uint = 0
+	for _, p := range config.Providers {
+		if p.ID > maxID {
+			maxID = p.ID
+		}
+	}
+	p := &ProviderConfig{ID: maxID + 1, ProviderSpec: spec, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	config.Providers = append(config.Providers, p)
+	if err := saveConfig(); err != nil {
+		return ProviderConfig{}, err
+	}
+	return p.redacted(), nil
+}
+
+// UpdateProvider 修改服务商，使用它的域名会重新同步；凭据留空时保留原值
+func UpdateProvider(id uint, spec ProviderSpec) (ProviderConfig, error) {
+	configMu.Lock()
+	_, p := findProvider(id)
+	if p == nil {
+		configMu.Unlock()
+		return ProviderConfig{}, ErrProviderNotFound
+	}
+	spec.keepCredentials(p.ProviderSpec)
+	if err := checkProvider(spec); err != nil {
+		configMu.Unlock()
+		return ProviderConfig{}, err
+	}
+	p.ProviderSpec = spec
+	p.UpdatedAt = time.Now()
+	err := saveConfig()
+	result := p.redacted()
+	var domains []uint
+	for _, d := range config.Domains {
+		if d.ProviderID == id {
+			domains = append(domains, d.ID)
+		}
+	}
+	configMu.Unlock()
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+
+	for _, domainID := range domains {
+		resyncDomain(domainID)
+	}
+	return result, nil
+}
+
+// DeleteProvider 删除服务商，仍被使用时返回 ErrProviderInUse
+func DeleteProvider(id uint) error {
+	for _, inUse := range ProviderInUse {
+		if inUse(id) {
+			return ErrProviderInUse
+		}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index, p := findProvider(id)
+	if p == nil {
+		return ErrProviderNotFound
+	}
+	for _, d := range config.Domains {
+		if d.ProviderID == id {
+			return ErrProviderInUse
+		}
+	}
+	providers := config.Providers
+	config.Providers = append(providers[:index:index], providers[index+1:]...)
+	return saveConfig()
+}
+
+// ListDomains 全部域名及其同步状态
+func ListDomains() []DomainInfo {
+	configMu.Lock()
+	domains := make([]Domain, 0, len(config.Domains))
+	for _, d := range config.Domains {
+		domains = append(domains, *d)
+	}
+	configMu.Unlock()
+
+	list := make([]DomainInfo, 0, len(domains))
+	for _, d := range domains {
+		list = append(list, DomainInfo{Domain: d, Status: domainStatus(d.ID)})
+	}
+	return list
+}
+
+// GetDomain 按ID查找域名
+func GetDomain(id uint) (DomainInfo, error) {
+	configMu.Lock()
+	_, d := findDomain(id)
+	var domain Domain
+	if d != nil {
+		domain = *d
+	}
+	configMu.Unlock()
+
+	if d == nil {
+		return DomainInfo{}, ErrDomainNotFound
+	}
+	return DomainInfo{Domain: domain, Status: domainStatus(id)}, nil
+}
+
+// AddDomain 新增域名并立即同步
+func AddDomain(spec DomainSpec) (DomainInfo, error) {
+	configMu.Lock()
+	if err := checkDomain(&spec); err != nil {
+		configMu.Unlock()
+		return DomainInfo{}, err
+	}
+
+	var maxID uint = 0
+	for _, d := range config.Domains {
+		if d.ID > maxID {
+			maxID = d.ID
+		}
+	}
+	d := &Domain{ID: maxID + 1, DomainSpec: spec, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	config.Domains = append(config.Domains, d)
+	err := saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return DomainInfo{}, err
+	}
+
+	resyncDomain(d.ID)
+	return GetDomain(d.ID)
+}
+
+// UpdateDomain 修改域名并重新同步
+func UpdateDomain(id uint, spec DomainSpec) (DomainInfo, error) {
+	configMu.Lock()
+	if err := checkDomain(&spec); err != nil {
+		configMu.Unlock()
+		return DomainInfo{}, err
+	}
+	_, d := findDomain(id)
+	if d == nil {
+		configMu.Unlock()
+		return DomainInfo{}, ErrDomainNotFound
+	}
+	d.DomainSpec = spec
+	d.UpdatedAt = time.Now()
+	err := saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return DomainInfo{}, err
+	}
+
+	resyncDomain(id)
+	return GetDomain(id)
+}
+
+// DeleteDomain 删除域名（DNS 上的记录保留）
+func DeleteDomain(id uint) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index, d := findDomain(id)
+	if d == nil {
+		return ErrDomainNotFound
+	}
+	domains := config.Domains
+	config.Domains = append(domains[:index:index], domains[index+1:]...)
+	removeDomainState(id)
+	return saveConfig()
+}
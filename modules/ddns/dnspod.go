@@ -0,0 +1,132 @@
+package ddns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// dnsPod DNSPod API（dnsapi.cn），使用 login_token = "ID,Token" 鉴权
+type dnsPod struct {
+	cfg ProviderConfig
+}
+
+type dnspodRecord struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type dnspodResponse struct {
+	Status struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+	Records []dnspodRecord `json:"records"`
+}
+
+func (p *dnsPod) request(ctx context.Context, action string, params url.Values) (*dnspodResponse, error) {
+	params.Set("login_token", p.cfg.TokenID+","+p.cfg.Token)
+	params.Set("format", "json")
+
+	base := endpointOr(p.cfg.Endpoint, "https://dnsapi.cn")
+	req, err := http.NewRequest(http.MethodPost, base+"/"+action, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "LinkStar DDNS/1.0")
+
+	var resp dnspodResponse
+	if err := doJSON(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("dnspod: %w", err)
+	}
+	return &resp, nil
+}
+
+// 状态码 1 为成功；Record.List 无记录时返回 10
+func (p *dnsPod) check(resp *dnspodResponse, allowed ...string) error {
+	if resp.Status.Code == "1" {
+		return nil
+	}
+	for _, code := range allowed {
+		if resp.Status.Code == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("dnspod: %s %s", resp.Status.Code, resp.Status.Message)
+}
+
+func (p *dnsPod) records(ctx context.Context, rec Record) ([]dnspodRecord, error) {
+	resp, err := p.request(ctx, "Record.List", url.Values{
+		"domain":      {rec.Zone},
+		"sub_domain":  {rec.RR()},
+		"record_type": {rec.Type},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.check(resp, "10"); err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
+
+func (p *dnsPod) Upsert(ctx context.Context, rec Record) error {
+	existing, err := p.records(ctx, rec)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{
+		"domain":      {rec.Zone},
+		"sub_domain":  {rec.RR()},
+		"record_type": {rec.Type},
+		"record_line": {"默认"},
+		"value":       {rec.content()},
+	}
+	if rec.TTL > 0 {
+		params.Set("ttl", strconv.Itoa(rec.TTL))
+	}
+
+	action := "Record.Create"
+	if len(existing) > 0 {
+		for _, r := range existing[1:] {
+			if err := p.remove(ctx, rec.Zone, r.ID); err != nil {
+				return err
+			}
+		}
+		action = "Record.Modify"
+		params.Set("record_id", existing[0].ID)
+	}
+	resp, err := p.request(ctx, action, params)
+	if err != nil {
+		return err
+	}
+	return p.check(resp)
+}
+
+func (p *dnsPod) remove(ctx context.Context, zone, id string) error {
+	resp, err := p.request(ctx, "Record.Remove", url.Values{"domain": {zone}, "record_id": {id}})
+	if err != nil {
+		return err
+	}
+	return p.check(resp)
+}
+
+func (p *dnsPod) Delete(ctx context.Context, rec Record) error {
+	existing, err := p.records(ctx, rec)
+	if err != nil {
+		return err
+	}
+	for _, r := range existing {
+		if err := p.remove(ctx, rec.Zone, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
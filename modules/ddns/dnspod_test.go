@@ -0,0 +1,83 @@
+package ddns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 本地模拟的 DNSPod API（dnsapi.cn）
+func newDNSPodServer(t *testing.T, loginToken string) (*httptest.Server, *fakeZone) {
+	zone := &fakeZone{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		reply := func(code, msg string, records []dnspodRecord) {
+			resp := map[string]any{"status": map[string]string{"code": code, "message": msg}}
+			if records != nil {
+				resp["records"] = records
+			}
+			json.NewEncoder(w).Encode(resp)
+		}
+		if r.Method != http.MethodPost || r.PostForm.Get("login_token") != loginToken {
+			reply("-1", "登录失败", nil)
+			return
+		}
+
+		name := r.PostForm.Get("domain")
+		if sub := r.PostForm.Get("sub_domain"); sub != "@" {
+			name = sub + "." + name
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/") {
+		case "Record.List":
+			var list []dnspodRecord
+			for _, rec := range zone.list(name, r.PostForm.Get("record_type")) {
+				list = append(list, dnspodRecord{ID: rec.ID, Type: rec.Type, Value: rec.Value})
+			}
+			if len(list) == 0 {
+				reply("10", "记录列表为空", nil)
+				return
+			}
+			reply("1", "Action completed successful", list)
+		case "Record.Create":
+			zone.add(fakeRecord{Name: name, Type: r.PostForm.Get("record_type"), Value: r.PostForm.Get("value")})
+			reply("1", "Action completed successful", nil)
+		case "Record.Modify":
+			if !zone.update(r.PostForm.Get("record_id"), func(rec *fakeRecord) { rec.Value = r.PostForm.Get("value") }) {
+				reply("8", "记录ID错误", nil)
+				return
+			}
+			reply("1", "Action completed successful", nil)
+		case "Record.Remove":
+			if !zone.remove(r.PostForm.Get("record_id")) {
+				reply("8", "记录ID错误", nil)
+				return
+			}
+			reply("1", "Action completed successful", nil)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, zone
+}
+
+func TestDNSPodUpsertDelete(t *testing.T) {
+	srv, zone := newDNSPodServer(t, "123,tok")
+	p, err := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeDNSPod, Endpoint: srv.URL, TokenID: "123", Token: "tok"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testUpsertDelete(t, p, zone)
+}
+
+func TestDNSPodLoginFailed(t *testing.T) {
+	srv, _ := newDNSPodServer(t, "123,tok")
+	p, _ := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeDNSPod, Endpoint: srv.URL, TokenID: "123", Token: "wrong"}})
+
+	err := p.Upsert(testContext(t), Record{Zone: "example.com", Name: "a.example.com", Type: "A", Value: "1.1.1.1"})
+	if err == nil || !strings.Contains(err.Error(), "-1") {
+		t.Fatalf("err = %v, want login failure", err)
+	}
+}
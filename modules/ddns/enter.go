@@ -0,0 +1,28 @@
+package ddns
+
+import (
+	"linkstar/global"
+	"linkstar/modules/event"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Init 读取 DDNS 配置，按当前公网IP同步一次并订阅后续变化
+// 需在 stun.InitSTUN 获取到公网IP之后调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取 DDNS 配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+
+	event.Handle(16, func(env event.Envelope) {
+		setPublicIP(env.Data.(event.PublicIPChanged).NewIP)
+	}, event.KindPublicIPChanged)
+
+	if ip := global.StunConfig.PublicIP; ip != "" {
+		setPublicIP(ip)
+	}
+}
@@ -0,0 +1,109 @@
+package ddns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 服务商类型
+const (
+	TypeCloudflare = "cloudflare"
+	TypeAliDNS     = "alidns"
+	TypeDNSPod     = "dnspod"
+	TypeURL        = "url" // 自定义 URL 模板
+)
+
+var ErrUnknownProviderType = errors.New("不支持的 DNS 服务商类型")
+
+// Record 一条 DNS 记录
+type Record struct {
+	Zone  string // 主域名，如 example.com
+	Name  string // 完整域名，如 home.example.com
+	Type  string // A / SRV / TXT
+	Value string // A 为 IP，TXT 为文本，SRV 为目标主机
+	TTL   int    // 秒，0 使用服务商默认值
+
+	// 仅 SRV
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+}
+
+// RR 主机记录，如 home.example.com 在 example.com 下为 home，与主域名相同时为 @
+func (r Record) RR() string {
+	name := strings.TrimSuffix(r.Name, ".")
+	zone := strings.TrimSuffix(r.Zone, ".")
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
+
+// SRV 记录的值 "优先级 权重 端口 目标"，A / TXT 直接为 Value
+func (r Record) content() string {
+	if r.Type == "SRV" {
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Value)
+	}
+	return r.Value
+}
+
+// Provider DNS 服务商
+// 同一个 Name + Type 只维护一条记录：Upsert 覆盖已有记录，Delete 删除全部同名同类型记录
+type Provider interface {
+	Upsert(ctx context.Context, rec Record) error
+	Delete(ctx context.Context, rec Record) error
+}
+
+// NewProvider 按配置创建服务商
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	switch cfg.Type {
+	case TypeCloudflare:
+		return &cloudflare{cfg: cfg}, nil
+	case TypeAliDNS:
+		return &aliDNS{cfg: cfg}, nil
+	case TypeDNSPod:
+		return &dnsPod{cfg: cfg}, nil
+	case TypeURL:
+		return newURLTemplate(cfg)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProviderType, cfg.Type)
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// 发送请求并解析 JSON 响应，非 2xx 时返回响应内容
+func doJSON(ctx context.Context, req *http.Request, out any) error {
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil && resp.StatusCode < 300 {
+			return fmt.Errorf("解析响应失败: %w", err)
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// endpoint 未配置时使用默认地址
+func endpointOr(endpoint, def string) string {
+	if endpoint == "" {
+		return def
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
@@ -0,0 +1,133 @@
+package ddns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeZone 模拟服务商保存的记录，各服务商的本地模拟服务共用
+type fakeZone struct {
+	mu      sync.Mutex
+	nextID  int
+	records []fakeRecord
+}
+
+type fakeRecord struct {
+	ID    string
+	Name  string // 完整域名
+	Type  string
+	Value string
+	TTL   int
+}
+
+func (z *fakeZone) list(name, typ string) []fakeRecord {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	var list []fakeRecord
+	for _, r := range z.records {
+		if r.Name == name && r.Type == typ {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+func (z *fakeZone) add(r fakeRecord) string {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.nextID++
+	r.ID = fmt.Sprint(z.nextID)
+	z.records = append(z.records, r)
+	return r.ID
+}
+
+func (z *fakeZone) update(id string, fn func(r *fakeRecord)) bool {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	for i := range z.records {
+		if z.records[i].ID == id {
+			fn(&z.records[i])
+			return true
+		}
+	}
+	return false
+}
+
+func (z *fakeZone) remove(id string) bool {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	i := slices.IndexFunc(z.records, func(r fakeRecord) bool { return r.ID == id })
+	if i < 0 {
+		return false
+	}
+	z.records = slices.Delete(z.records, i, i+1)
+	return true
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// 各服务商共用的 Upsert / Delete 流程：新建、覆盖、合并重复记录、删除；模拟服务将 SRV 的值保存为 content()
+func testUpsertDelete(t *testing.T, p Provider, zone *fakeZone) {
+	ctx := testContext(t)
+	rec := Record{Zone: "example.com", Name: "home.example.com", Type: "A", Value: "1.1.1.1", TTL: 600}
+
+	if err := p.Upsert(ctx, rec); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got := zone.list(rec.Name, "A")
+	if len(got) != 1 || got[0].Value != "1.1.1.1" {
+		t.Fatalf("after create: %+v", got)
+	}
+
+	rec.Value = "2.2.2.2"
+	if err := p.Upsert(ctx, rec); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got := zone.list(rec.Name, "A"); len(got) != 1 || got[0].Value != "2.2.2.2" {
+		t.Fatalf("after update: %+v", got)
+	}
+
+	// 手工留下的重复记录被合并为一条
+	zone.add(fakeRecord{Name: rec.Name, Type: "A", Value: "9.9.9.9"})
+	rec.Value = "3.3.3.3"
+	if err := p.Upsert(ctx, rec); err != nil {
+		t.Fatalf("dedupe: %v", err)
+	}
+	if got := zone.list(rec.Name, "A"); len(got) != 1 || got[0].Value != "3.3.3.3" {
+		t.Fatalf("after dedupe: %+v", got)
+	}
+
+	srv := Record{Zone: "example.com", Name: "_ssh._tcp.example.com", Type: "SRV", Value: "home.example.com", Priority: 1, Weight: 2, Port: 2222}
+	if err := p.Upsert(ctx, srv); err != nil {
+		t.Fatalf("srv: %v", err)
+	}
+	if got := zone.list(srv.Name, "SRV"); len(got) != 1 || got[0].Value != srv.content() {
+		t.Fatalf("srv record: %+v, want value %q", got, srv.content())
+	}
+
+	if err := p.Delete(ctx, rec); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got := zone.list(rec.Name, "A"); len(got) != 0 {
+		t.Fatalf("after delete: %+v", got)
+	}
+	// 没有记录时删除不报错
+	if err := p.Delete(ctx, rec); err != nil {
+		t.Fatalf("delete missing: %v", err)
+	}
+	if got := zone.list(srv.Name, "SRV"); len(got) != 1 {
+		t.Fatalf("delete removed other records: %+v", got)
+	}
+}
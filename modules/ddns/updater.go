@@ -0,0 +1,186 @@
+package ddns
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrNoPublicIP = errors.New("尚未获取到公网IP")
+
+// DomainStatus 域名同步状态（仅保存在内存中）
+type DomainStatus struct {
+	IP            string    `json:"ip"`            // 最近一次成功写入的 IP
+	Pending       bool      `json:"pending"`       // 受最小间隔限制，等待下次更新
+	NextUpdateAt  time.Time `json:"nextUpdateAt"`  // 下次更新时间
+	LastAttemptAt time.Time `json:"lastAttemptAt"` // 最近一次请求服务商的时间
+	LastSuccessAt time.Time `json:"lastSuccessAt"` // 最近一次成功的时间
+	LastError     string    `json:"lastError"`     // 最近一次失败原因，成功后清空
+	Updates       int       `json:"updates"`       // 成功更新次数
+}
+
+// DomainInfo 域名及其同步状态
+type DomainInfo struct {
+	Domain
+	Status DomainStatus `json:"status"` // 同步状态
+}
+
+type domainState struct {
+	mu     sync.Mutex // 串行化同一域名的更新
+	status DomainStatus
+	timer  *time.Timer
+}
+
+var (
+	stateMu  sync.Mutex // 保护 states、publicIP 以及各 domainState 的 status/timer
+	states   = make(map[uint]*domainState)
+	publicIP string
+)
+
+func stateOf(id uint) *domainState {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	st, ok := states[id]
+	if !ok {
+		st = &domainState{}
+		states[id] = st
+	}
+	return st
+}
+
+func domainStatus(id uint) DomainStatus {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if st, ok := states[id]; ok {
+		return st.status
+	}
+	return DomainStatus{}
+}
+
+func removeDomainState(id uint) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if st, ok := states[id]; ok && st.timer != nil {
+		st.timer.Stop()
+	}
+	delete(states, id)
+}
+
+// 间隔 wait 后再同步，已有等待中的同步时不重复安排，调用方需持有 stateMu
+func (st *domainState) schedule(id uint, wait time.Duration) {
+	if st.timer != nil {
+		return
+	}
+	st.status.Pending = true
+	st.status.NextUpdateAt = time.Now().Add(wait)
+	st.timer = time.AfterFunc(wait, func() {
+		stateMu.Lock()
+		st.timer = nil
+		stateMu.Unlock()
+		syncDomain(id, false)
+	})
+}
+
+// 配置变化后清空已写入的 IP，后台重新同步
+func resyncDomain(id uint) {
+	st := stateOf(id)
+	stateMu.Lock()
+	st.status.IP = ""
+	stateMu.Unlock()
+	go syncDomain(id, false)
+}
+
+// 公网IP变化，同步全部域名
+func setPublicIP(ip string) {
+	stateMu.Lock()
+	publicIP = ip
+	stateMu.Unlock()
+
+	configMu.Lock()
+	ids := make([]uint, 0, len(config.Domains))
+	for _, d := range config.Domains {
+		ids = append(ids, d.ID)
+	}
+	configMu.Unlock()
+
+	for _, id := range ids {
+		go syncDomain(id, false)
+	}
+}
+
+// syncDomain 将域名的 A 记录更新为当前公网IP
+// force 为 false 时跳过已是最新的域名，并受最小间隔限制（期间的变化合并到下次更新）
+func syncDomain(id uint, force bool) error {
+	info, err := GetDomain(id)
+	if err != nil {
+		return err
+	}
+	d := info.Domain
+	if !d.Enabled && !force {
+		return nil
+	}
+
+	st := stateOf(id)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stateMu.Lock()
+	ip := publicIP
+	if ip == "" {
+		stateMu.Unlock()
+		return ErrNoPublicIP
+	}
+	if !force {
+		if st.status.IP == ip && st.status.LastError == "" {
+			stateMu.Unlock()
+			return nil
+		}
+		if wait := d.interval() - time.Since(st.status.LastAttemptAt); wait > 0 {
+			st.schedule(id, wait)
+			stateMu.Unlock()
+			return nil
+		}
+	}
+	st.status.Pending = false
+	st.status.NextUpdateAt = time.Time{}
+	st.status.LastAttemptAt = time.Now()
+	stateMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	provider, err := ProviderByID(d.ProviderID)
+	if err == nil {
+		err = provider.Upsert(ctx, Record{Zone: d.Zone, Name: d.Name, Type: "A", Value: ip, TTL: d.TTL})
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if err != nil {
+		st.status.LastError = err.Error()
+		// 失败后按最小间隔重试
+		st.schedule(id, d.interval())
+		logrus.Warnf("[DDNS %s] 更新失败: %v", d.Name, err)
+		return err
+	}
+	st.status.IP = ip
+	st.status.LastError = ""
+	st.status.LastSuccessAt = time.Now()
+	st.status.Updates++
+	logrus.Infof("[DDNS %s] 已更新为 %s", d.Name, ip)
+	return nil
+}
+
+// SyncDomain 立即同步域名（不受最小间隔限制），返回同步后的状态
+func SyncDomain(id uint) (DomainInfo, error) {
+	if err := syncDomain(id, true); err != nil {
+		return DomainInfo{}, err
+	}
+	return GetDomain(id)
+}
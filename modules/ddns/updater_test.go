@@ -0,0 +1,122 @@
+package ddns
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 使用内存配置，避免读写 config/ddns.json
+func useConfig(t *testing.T, c Config) {
+	configMu.Lock()
+	old := config
+	config = c
+	configMu.Unlock()
+
+	stateMu.Lock()
+	oldIP := publicIP
+	states = make(map[uint]*domainState)
+	stateMu.Unlock()
+
+	t.Cleanup(func() {
+		stateMu.Lock()
+		for _, st := range states {
+			if st.timer != nil {
+				st.timer.Stop()
+			}
+		}
+		states = make(map[uint]*domainState)
+		publicIP = oldIP
+		stateMu.Unlock()
+
+		configMu.Lock()
+		config = old
+		configMu.Unlock()
+	})
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMinIntervalCoalesces(t *testing.T) {
+	srv, requests := newTemplateServer(t, http.StatusOK)
+	useConfig(t, Config{
+		Providers: []*ProviderConfig{{ID: 1, ProviderSpec: ProviderSpec{Type: TypeURL, URL: srv.URL + "/?ip={{.Value}}"}}},
+		Domains:   []*Domain{{ID: 1, DomainSpec: DomainSpec{ProviderID: 1, Zone: "example.com", Name: "home.example.com", MinInterval: 1, Enabled: true}}},
+	})
+
+	setPublicIP("1.1.1.1")
+	waitFor(t, time.Second, func() bool { return domainStatus(1).Updates == 1 })
+
+	// 最小间隔内的两次变化只安排一次更新
+	setPublicIP("2.2.2.2")
+	waitFor(t, time.Second, func() bool { return domainStatus(1).Pending })
+	setPublicIP("3.3.3.3")
+	time.Sleep(100 * time.Millisecond)
+	if st := domainStatus(1); !st.Pending || st.IP != "1.1.1.1" || len(requests()) != 1 {
+		t.Fatalf("status = %+v, requests = %d", st, len(requests()))
+	}
+
+	waitFor(t, 2*time.Second, func() bool { return domainStatus(1).Updates == 2 })
+	time.Sleep(100 * time.Millisecond)
+	got := requests()
+	if len(got) != 2 || got[1].query != "ip=3.3.3.3" {
+		t.Fatalf("requests = %+v, want second update with latest IP", got)
+	}
+	if st := domainStatus(1); st.Pending || st.IP != "3.3.3.3" {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestDisabledDomainSkipped(t *testing.T) {
+	srv, requests := newTemplateServer(t, http.StatusOK)
+	useConfig(t, Config{
+		Providers: []*ProviderConfig{{ID: 1, ProviderSpec: ProviderSpec{Type: TypeURL, URL: srv.URL + "/?ip={{.Value}}"}}},
+		Domains:   []*Domain{{ID: 1, DomainSpec: DomainSpec{ProviderID: 1, Zone: "example.com", Name: "home.example.com"}}},
+	})
+
+	setPublicIP("1.1.1.1")
+	time.Sleep(100 * time.Millisecond)
+	if n := len(requests()); n != 0 {
+		t.Fatalf("disabled domain sent %d requests", n)
+	}
+	if _, err := SyncDomain(1); err != nil || len(requests()) != 1 {
+		t.Fatalf("manual sync: err = %v, requests = %d", err, len(requests()))
+	}
+}
+
+func TestCredentialsRedacted(t *testing.T) {
+	t.Chdir(t.TempDir())
+	useConfig(t, Config{Providers: []*ProviderConfig{}, Domains: []*Domain{}})
+
+	created, err := AddProvider(ProviderSpec{Type: TypeAliDNS, AccessKeyID: "ak", AccessKeySecret: "sk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(ListProviders())
+	if strings.Contains(string(data), `"ak"`) || strings.Contains(string(data), `"sk"`) {
+		t.Fatalf("list leaks credentials: %s", data)
+	}
+
+	// 修改时留空或原样提交隐藏值则保留原凭据
+	if _, err := UpdateProvider(created.ID, ProviderSpec{Type: TypeAliDNS, AccessKeyID: Redacted}); err != nil {
+		t.Fatal(err)
+	}
+	configMu.Lock()
+	_, p := findProvider(created.ID)
+	id, secret := p.AccessKeyID, p.AccessKeySecret
+	configMu.Unlock()
+	if id != "ak" || secret != "sk" {
+		t.Fatalf("stored credentials = %q, %q", id, secret)
+	}
+}
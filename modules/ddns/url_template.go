@@ -0,0 +1,83 @@
+package ddns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// urlTemplate 通用 URL 模板，适配花生壳、DuckDNS、自建接口等
+// URL 与 Body 均为 Go text/template，数据为 urlTemplateData，例如：
+//
+//	https://www.duckdns.org/update?domains={{.RR}}&token=xxx&ip={{.Value}}
+//
+// SRV 记录的 Content 含空格，放入 URL 时使用 {{urlquery .Content}}
+type urlTemplate struct {
+	cfg  ProviderConfig
+	url  *template.Template
+	body *template.Template
+}
+
+type urlTemplateData struct {
+	Action string // upsert / delete
+	Record
+	RR      string // 主机记录
+	Content string // 记录值，SRV 为 "优先级 权重 端口 目标"
+}
+
+func newURLTemplate(cfg ProviderConfig) (*urlTemplate, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("url 模板不能为空")
+	}
+	p := &urlTemplate{cfg: cfg}
+
+	var err error
+	if p.url, err = template.New("url").Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("url 模板语法错误: %w", err)
+	}
+	if cfg.Body != "" {
+		if p.body, err = template.New("body").Parse(cfg.Body); err != nil {
+			return nil, fmt.Errorf("body 模板语法错误: %w", err)
+		}
+	}
+	return p, nil
+}
+
+func (p *urlTemplate) call(ctx context.Context, action string, rec Record) error {
+	data := urlTemplateData{Action: action, Record: rec, RR: rec.RR(), Content: rec.content()}
+
+	var u bytes.Buffer
+	if err := p.url.Execute(&u, data); err != nil {
+		return fmt.Errorf("渲染 url 模板失败: %w", err)
+	}
+	var body bytes.Buffer
+	if p.body != nil {
+		if err := p.body.Execute(&body, data); err != nil {
+			return fmt.Errorf("渲染 body 模板失败: %w", err)
+		}
+	}
+
+	method := strings.ToUpper(p.cfg.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequest(method, strings.TrimSpace(u.String()), &body)
+	if err != nil {
+		return err
+	}
+	if p.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return doJSON(ctx, req, nil)
+}
+
+func (p *urlTemplate) Upsert(ctx context.Context, rec Record) error {
+	return p.call(ctx, "upsert", rec)
+}
+
+func (p *urlTemplate) Delete(ctx context.Context, rec Record) error {
+	return p.call(ctx, "delete", rec)
+}
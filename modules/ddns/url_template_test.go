@@ -0,0 +1,79 @@
+package ddns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type templateRequest struct {
+	method, path, query, contentType, body string
+}
+
+func newTemplateServer(t *testing.T, status int) (*httptest.Server, func() []templateRequest) {
+	var (
+		mu       sync.Mutex
+		requests []templateRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, templateRequest{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("Content-Type"), string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, "nochg")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []templateRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]templateRequest(nil), requests...)
+	}
+}
+
+func TestURLTemplateUpsertDelete(t *testing.T) {
+	srv, requests := newTemplateServer(t, http.StatusOK)
+	p, err := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{
+		Type:   TypeURL,
+		URL:    srv.URL + "/{{.Action}}?host={{.RR}}&type={{.Type}}&value={{urlquery .Content}}",
+		Method: "POST",
+		Body:   `{"name":"{{.Name}}","ttl":{{.TTL}}}`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := testContext(t)
+
+	if err := p.Upsert(ctx, Record{Zone: "example.com", Name: "home.example.com", Type: "A", Value: "1.1.1.1", TTL: 60}); err != nil {
+		t.Fatal(err)
+	}
+	srvRec := Record{Zone: "example.com", Name: "_ssh._tcp.example.com", Type: "SRV", Value: "home.example.com", Priority: 1, Weight: 2, Port: 2222}
+	if err := p.Delete(ctx, srvRec); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []templateRequest{
+		{"POST", "/upsert", "host=home&type=A&value=1.1.1.1", "application/json", `{"name":"home.example.com","ttl":60}`},
+		{"POST", "/delete", "host=_ssh._tcp&type=SRV&value=1+2+2222+home.example.com", "application/json", `{"name":"_ssh._tcp.example.com","ttl":0}`},
+	}
+	got := requests()
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestURLTemplateErrorStatus(t *testing.T) {
+	srv, _ := newTemplateServer(t, http.StatusUnauthorized)
+	p, _ := NewProvider(ProviderConfig{ProviderSpec: ProviderSpec{Type: TypeURL, URL: srv.URL + "/?ip={{.Value}}"}})
+
+	if err := p.Upsert(testContext(t), Record{Zone: "example.com", Name: "example.com", Type: "A", Value: "1.1.1.1"}); err == nil {
+		t.Fatal("non-2xx response should fail")
+	}
+}
@@ -0,0 +1,244 @@
+package dnsserver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"linkstar/global"
+	"linkstar/modules/event"
+	"linkstar/modules/stun"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrNotEnabled = errors.New("内置 DNS 未开启")
+
+const (
+	defaultListen = ":5353"
+	defaultTTL    = 30
+)
+
+type server struct {
+	zone   string
+	ns     string
+	ttl    uint32
+	serial uint32
+	listen string
+
+	recordsMu sync.Mutex // 保护以下字段
+	publicIP  string
+	records   []Record // 缓存的区域记录，nil 表示需要重建
+	builtAt   time.Time
+}
+
+// Status 内置 DNS 运行状态
+type Status struct {
+	Enabled   bool      `json:"enabled"`   // 是否开启
+	Zone      string    `json:"zone"`      // 委派的子域名
+	NS        string    `json:"ns"`        // NS 主机名
+	Listen    string    `json:"listen"`    // 监听地址
+	StartedAt time.Time `json:"startedAt"` // 启动时间
+	UPnP      bool      `json:"upnp"`      // 是否需要 UPnP 映射
+	Mapped    bool      `json:"mapped"`    // 外网 UDP/53 与 TCP/53 是否均映射成功
+	LastError string    `json:"lastError"` // 最近一次错误（监听、映射）
+	Stats
+}
+
+var (
+	current   *server
+	statusMu  sync.Mutex
+	status    Status
+	mappingMu sync.Mutex // 串行化 UPnP 映射
+)
+
+func setError(err error) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	status.LastError = err.Error()
+}
+
+// Init 按 settings.json 中的 dns 配置启动内置 DNS，未开启时直接返回
+func Init() {
+	c := global.Config.DNS
+	if !c.Enable {
+		return
+	}
+
+	zone := strings.ToLower(strings.Trim(c.Zone, "."))
+	if zone == "" {
+		logrus.Error("内置 DNS 未配置 zone，已跳过启动")
+		return
+	}
+	s := &server{
+		zone:   zone,
+		ns:     strings.ToLower(strings.Trim(c.NS, ".")),
+		ttl:    c.TTL,
+		serial: uint32(time.Now().Unix()),
+		listen: c.Listen,
+
+		publicIP: global.StunConfig.PublicIP,
+	}
+	if s.ns == "" {
+		s.ns = "ns." + zone
+	}
+	if s.ttl == 0 {
+		s.ttl = defaultTTL
+	}
+	if s.listen == "" {
+		s.listen = defaultListen
+	}
+	current = s
+
+	statusMu.Lock()
+	status = Status{Enabled: true, Zone: s.zone, NS: s.ns, Listen: s.listen, UPnP: c.UPnP, StartedAt: time.Now()}
+	statusMu.Unlock()
+
+	// 区域记录只在服务上下线、公网IP变化时重建
+	event.Handle(16, func(env event.Envelope) {
+		if e, ok := env.Data.(event.PublicIPChanged); ok {
+			s.setPublicIP(e.NewIP)
+			return
+		}
+		s.invalidate()
+	}, event.KindServiceOnline, event.KindServiceOffline, event.KindPublicIPChanged)
+
+	if err := s.start(); err != nil {
+		logrus.Errorf("内置 DNS 启动失败: %v", err)
+		setError(err)
+		return
+	}
+	logrus.Infof("内置 DNS 已启动：%s（区域 %s）", s.listen, s.zone)
+
+	if c.UPnP {
+		go s.mapPort()
+		// 网关变化后重新映射
+		event.Handle(4, func(event.Envelope) { s.mapPort() }, event.KindGatewayChanged)
+	}
+}
+
+// 同时监听 UDP 与 TCP，端口为 0 时 TCP 使用 UDP 分配到的端口
+func (s *server) start() error {
+	pc, err := net.ListenPacket("udp", s.listen)
+	if err != nil {
+		return err
+	}
+	if _, port, _ := net.SplitHostPort(s.listen); port == "0" {
+		s.listen = pc.LocalAddr().String()
+	}
+	ln, err := net.Listen("tcp", s.listen)
+	if err != nil {
+		pc.Close()
+		return err
+	}
+	go s.serveUDP(pc)
+	go s.serveTCP(ln)
+	return nil
+}
+
+func (s *server) serveUDP(pc net.PacketConn) {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			logrus.Errorf("内置 DNS UDP 监听退出: %v", err)
+			setError(err)
+			return
+		}
+		if resp := s.handle(buf[:n], true); resp != nil {
+			pc.WriteTo(resp, addr)
+		}
+	}
+}
+
+func (s *server) serveTCP(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			logrus.Errorf("内置 DNS TCP 监听退出: %v", err)
+			setError(err)
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// TCP 报文前有 2 字节长度，同一连接可发送多个查询
+func (s *server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+		var size uint16
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		req := make([]byte, size)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		resp := s.handle(req, false)
+		if resp == nil {
+			return
+		}
+		out := binary.BigEndian.AppendUint16(nil, uint16(len(resp)))
+		if _, err := conn.Write(append(out, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// 将外网 UDP/53 与 TCP/53 映射到监听端口，截断的应答需要客户端改用 TCP 重试
+func (s *server) mapPort() {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+
+	_, portStr, _ := net.SplitHostPort(s.listen)
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 {
+		setError(fmt.Errorf("监听地址 %s 端口不合法", s.listen))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mapped := true
+	for _, proto := range []string{"UDP", "TCP"} {
+		if err := stun.AddPortMappingQueue(ctx, 53, uint16(port), proto, "LinkStar DNS"); err != nil {
+			mapped = false
+			setError(fmt.Errorf("UPnP 映射 %s/53 失败: %w", proto, err))
+			logrus.Warnf("内置 DNS UPnP 映射 %s/53 失败: %v", proto, err)
+			continue
+		}
+		logrus.Infof("内置 DNS 已通过 UPnP 映射 %s/53 -> %d", proto, port)
+	}
+
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	status.Mapped = mapped
+}
+
+// GetStatus 运行状态与查询统计
+func GetStatus() Status {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	st := status
+	st.Stats = stats()
+	return st
+}
+
+// ListRecords 当前可应答的全部记录
+func ListRecords() ([]Record, error) {
+	s := current
+	if s == nil {
+		return nil, ErrNotEnabled
+	}
+	return s.zoneRecords(), nil
+}
@@ -0,0 +1,138 @@
+package dnsserver
+
+import (
+	"fmt"
+	"linkstar/modules/stun"
+	"linkstar/modules/stun/model"
+	"net/netip"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Record 当前可应答的一条记录
+type Record struct {
+	Name  string `json:"name"`  // 完整域名
+	Type  string `json:"type"`  // A / SRV / TXT / NS / SOA
+	TTL   uint32 `json:"ttl"`   // TTL（秒）
+	Value string `json:"value"` // A 为 IP，SRV 为 "优先级 权重 端口 目标"，NS 为主机名，TXT 为文本
+
+	ip     [4]byte
+	port   uint16
+	target string
+}
+
+// 设备、服务名转换为 DNS 标签：小写，非字母数字替换为 -，为空时使用 fallback
+func label(name, fallback string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	s := strings.Trim(b.String(), "-")
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "-")
+	}
+	if s == "" || len(s) > 63 {
+		return fallback
+	}
+	return s
+}
+
+// 服务是否运行中，测试时替换
+var serviceRunning = stun.IsServiceRunning
+
+// zoneRecords 缓存的区域记录
+// 服务上下线、公网IP变化时失效；设备或服务改名没有事件，缓存最多保留一个 TTL
+func (s *server) zoneRecords() []Record {
+	s.recordsMu.Lock()
+	defer s.recordsMu.Unlock()
+
+	if s.records == nil || time.Since(s.builtAt) >= time.Duration(s.ttl)*time.Second {
+		s.records = buildRecords(s.zone, s.ns, s.ttl, s.serial, s.publicIP, stun.ListDevices())
+		s.builtAt = time.Now()
+	}
+	return s.records
+}
+
+// 使缓存失效，下次查询时重建
+func (s *server) invalidate() {
+	s.recordsMu.Lock()
+	defer s.recordsMu.Unlock()
+	s.records = nil
+}
+
+func (s *server) setPublicIP(ip string) {
+	s.recordsMu.Lock()
+	defer s.recordsMu.Unlock()
+	s.publicIP = ip
+	s.records = nil
+}
+
+// buildRecords 根据公网IP和在线服务生成区域内的全部记录：
+//
+//	<zone>                        SOA / NS / A
+//	<ns>                          A（NS 主机名位于区域内时）
+//	<设备>.<zone>                 A
+//	<服务>.<设备>.<zone>          A / SRV / TXT（访问地址）
+//	_<服务>._<协议>.<设备>.<zone> SRV
+//
+// 公网IP未知时只返回 SOA / NS；只有打洞成功且运行中的服务才有记录
+func buildRecords(zone, ns string, ttl uint32, serial uint32, publicIP string, devices []*model.Device) []Record {
+	records := []Record{
+		{Name: zone, Type: "SOA", TTL: ttl, Value: fmt.Sprintf("%s hostmaster.%s %d", ns, zone, serial)},
+		{Name: zone, Type: "NS", TTL: ttl, Value: ns, target: ns},
+	}
+
+	addr, err := netip.ParseAddr(publicIP)
+	if err != nil || !addr.Is4() {
+		return records
+	}
+	ip := addr.As4()
+	a := func(name string) Record {
+		return Record{Name: name, Type: "A", TTL: ttl, Value: addr.String(), ip: ip}
+	}
+
+	records = append(records, a(zone))
+	if ns != zone && strings.HasSuffix(ns, "."+zone) {
+		records = append(records, a(ns))
+	}
+	for _, device := range devices {
+		deviceName := label(device.Name, fmt.Sprintf("d%d", device.DeviceID)) + "." + zone
+		records = append(records, a(deviceName))
+
+		for _, service := range device.Services {
+			if !service.PunchSuccess || service.ExternalPort == 0 || !serviceRunning(device.DeviceID, service.ID) {
+				continue
+			}
+			svcLabel := label(service.Name, fmt.Sprintf("s%d", service.ID))
+			name := svcLabel + "." + deviceName
+			proto := strings.ToLower(service.Protocol)
+			if proto == "" {
+				proto = "tcp"
+			}
+			scheme := "http"
+			if service.TLS {
+				scheme = "https"
+			}
+			srv := func(owner string) Record {
+				return Record{
+					Name: owner, Type: "SRV", TTL: ttl,
+					Value: fmt.Sprintf("0 0 %d %s", service.ExternalPort, name),
+					port:  service.ExternalPort, target: name,
+				}
+			}
+
+			records = append(records,
+				a(name),
+				srv(name),
+				srv("_"+svcLabel+"._"+proto+"."+deviceName),
+				Record{Name: name, Type: "TXT", TTL: ttl, Value: fmt.Sprintf("%s://%s:%d", scheme, name, service.ExternalPort)},
+			)
+		}
+	}
+	return records
+}
@@ -0,0 +1,184 @@
+package dnsserver
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// 无 EDNS 时 UDP 应答的最大长度
+const maxUDPSize = 512
+
+// Stats 查询统计
+type Stats struct {
+	Queries  uint64 `json:"queries"`  // 收到的查询
+	Answered uint64 `json:"answered"` // 有应答记录
+	NXDomain uint64 `json:"nxdomain"` // 名称不存在
+	Refused  uint64 `json:"refused"`  // 不属于本区域或格式错误
+}
+
+var queries, answered, nxdomain, refused atomic.Uint64
+
+func stats() Stats {
+	return Stats{
+		Queries:  queries.Load(),
+		Answered: answered.Load(),
+		NXDomain: nxdomain.Load(),
+		Refused:  refused.Load(),
+	}
+}
+
+// 各记录类型对应的查询类型
+var recordTypes = map[string]dnsmessage.Type{
+	"A":   dnsmessage.TypeA,
+	"NS":  dnsmessage.TypeNS,
+	"SOA": dnsmessage.TypeSOA,
+	"SRV": dnsmessage.TypeSRV,
+	"TXT": dnsmessage.TypeTXT,
+}
+
+// handle 处理一个查询报文并返回应答，报文无法解析时返回 nil
+// udp 为 true 时应答超过 512 字节会截断并设置 TC，客户端随后改用 TCP
+func (s *server) handle(req []byte, udp bool) []byte {
+	var p dnsmessage.Parser
+	header, err := p.Start(req)
+	if err != nil || header.Response {
+		return nil
+	}
+	queries.Add(1)
+
+	resp := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               header.ID,
+			Response:         true,
+			OpCode:           header.OpCode,
+			RecursionDesired: header.RecursionDesired,
+		},
+	}
+	q, err := p.Question()
+	if err != nil || header.OpCode != 0 {
+		refused.Add(1)
+		resp.Header.RCode = dnsmessage.RCodeNotImplemented
+		if err != nil {
+			resp.Header.RCode = dnsmessage.RCodeFormatError
+		}
+		return s.pack(resp, udp)
+	}
+	resp.Questions = []dnsmessage.Question{q}
+
+	qname := strings.ToLower(strings.TrimSuffix(q.Name.String(), "."))
+	if q.Class != dnsmessage.ClassINET || (qname != s.zone && !strings.HasSuffix(qname, "."+s.zone)) {
+		refused.Add(1)
+		resp.Header.RCode = dnsmessage.RCodeRefused
+		return s.pack(resp, udp)
+	}
+	resp.Header.Authoritative = true
+
+	records := s.zoneRecords()
+	var found bool
+	var targets []string
+	for _, r := range records {
+		if r.Name != qname {
+			continue
+		}
+		found = true
+		if q.Type != dnsmessage.TypeALL && recordTypes[r.Type] != q.Type {
+			continue
+		}
+		if rr, ok := s.resource(r); ok {
+			resp.Answers = append(resp.Answers, rr)
+		}
+		if r.target != "" {
+			targets = append(targets, r.target)
+		}
+	}
+
+	switch {
+	case !found:
+		nxdomain.Add(1)
+		resp.Header.RCode = dnsmessage.RCodeNameError
+	case len(resp.Answers) > 0:
+		answered.Add(1)
+	}
+	if len(resp.Answers) == 0 {
+		// 否定应答附带 SOA，供解析器缓存
+		if rr, ok := s.resource(records[0]); ok {
+			resp.Authorities = append(resp.Authorities, rr)
+		}
+	}
+
+	// SRV / NS 目标在区域内时附带其 A 记录
+	for _, target := range targets {
+		for _, r := range records {
+			if r.Name == target && r.Type == "A" {
+				if rr, ok := s.resource(r); ok {
+					resp.Additionals = append(resp.Additionals, rr)
+				}
+			}
+		}
+	}
+	return s.pack(resp, udp)
+}
+
+func (s *server) pack(resp dnsmessage.Message, udp bool) []byte {
+	out, err := resp.Pack()
+	if err != nil {
+		return nil
+	}
+	if udp && len(out) > maxUDPSize {
+		resp.Header.Truncated = true
+		resp.Answers, resp.Authorities, resp.Additionals = nil, nil, nil
+		out, _ = resp.Pack()
+	}
+	return out
+}
+
+// 转换为 dnsmessage 资源记录
+func (s *server) resource(r Record) (dnsmessage.Resource, bool) {
+	name, err := dnsmessage.NewName(r.Name + ".")
+	if err != nil {
+		return dnsmessage.Resource{}, false
+	}
+	h := dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: r.TTL}
+
+	var body dnsmessage.ResourceBody
+	switch r.Type {
+	case "A":
+		body = &dnsmessage.AResource{A: r.ip}
+	case "NS":
+		ns, err := dnsmessage.NewName(r.target + ".")
+		if err != nil {
+			return dnsmessage.Resource{}, false
+		}
+		body = &dnsmessage.NSResource{NS: ns}
+	case "SOA":
+		ns, err1 := dnsmessage.NewName(s.ns + ".")
+		mbox, err2 := dnsmessage.NewName("hostmaster." + s.zone + ".")
+		if err1 != nil || err2 != nil {
+			return dnsmessage.Resource{}, false
+		}
+		body = &dnsmessage.SOAResource{
+			NS: ns, MBox: mbox, Serial: s.serial,
+			Refresh: 3600, Retry: 600, Expire: 86400, MinTTL: s.ttl,
+		}
+	case "SRV":
+		target, err := dnsmessage.NewName(r.target + ".")
+		if err != nil {
+			return dnsmessage.Resource{}, false
+		}
+		body = &dnsmessage.SRVResource{Port: r.port, Target: target}
+	case "TXT":
+		// 单个字符串最长 255 字节
+		var txt []string
+		for v := r.Value; v != ""; {
+			n := min(len(v), 255)
+			txt = append(txt, v[:n])
+			v = v[n:]
+		}
+		body = &dnsmessage.TXTResource{TXT: txt}
+	default:
+		return dnsmessage.Resource{}, false
+	}
+	return dnsmessage.Resource{Header: h, Body: body}, true
+}
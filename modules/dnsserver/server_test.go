@@ -0,0 +1,213 @@
+package dnsserver
+
+import (
+	"encoding/binary"
+	"io"
+	"linkstar/global"
+	"linkstar/modules/stun/model"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var (
+	setupOnce  sync.Once
+	sshRunning atomic.Bool
+)
+
+// 在 127.0.0.1 随机端口启动一个区域为 dyn.example.com 的服务器
+// 设备 NAS 下 web 服务在线，ssh 服务由 sshRunning 控制
+func startTestServer(t *testing.T) *server {
+	// 各测试的服务器不会退出，设备与运行状态只设置一次
+	setupOnce.Do(func() {
+		global.StunConfig.Devices = []*model.Device{{
+			DeviceID: 1, Name: "NAS",
+			Services: []*model.Service{
+				{ID: 2, ServiceSpec: model.ServiceSpec{Name: "Web 管理", Protocol: "TCP", TLS: true}, ExternalPort: 40001, PunchSuccess: true},
+				{ID: 3, ServiceSpec: model.ServiceSpec{Name: "ssh"}, ExternalPort: 40002, PunchSuccess: true},
+			},
+		}}
+		serviceRunning = func(deviceID, serviceID uint) bool { return serviceID == 2 || sshRunning.Load() }
+	})
+	sshRunning.Store(false)
+
+	s := &server{zone: "dyn.example.com", ns: "ns.dyn.example.com", ttl: 60, serial: 1, listen: "127.0.0.1:0", publicIP: "203.0.113.7"}
+	if err := s.start(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func query(t *testing.T, network, addr, name string, typ dnsmessage.Type) dnsmessage.Message {
+	t.Helper()
+	req := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(name), Type: typ, Class: dnsmessage.ClassINET}},
+	}
+	msg, err := req.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	var resp []byte
+	if network == "tcp" {
+		conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...))
+		var size uint16
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			t.Fatal(err)
+		}
+		resp = make([]byte, size)
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		conn.Write(msg)
+		resp = make([]byte, 1500)
+		n, err := conn.Read(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp = resp[:n]
+	}
+
+	var m dnsmessage.Message
+	if err := m.Unpack(resp); err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.ID != 42 || !m.Header.Response {
+		t.Fatalf("header = %+v", m.Header)
+	}
+	return m
+}
+
+func TestQueryA(t *testing.T) {
+	s := startTestServer(t)
+
+	for _, name := range []string{"dyn.example.com.", "nas.dyn.example.com.", "web.nas.dyn.example.com.", "NAS.Dyn.Example.com."} {
+		m := query(t, "udp", s.listen, name, dnsmessage.TypeA)
+		if m.RCode != dnsmessage.RCodeSuccess || !m.Authoritative || len(m.Answers) != 1 {
+			t.Fatalf("%s: rcode = %v, answers = %d", name, m.RCode, len(m.Answers))
+		}
+		a := m.Answers[0].Body.(*dnsmessage.AResource)
+		if a.A != [4]byte{203, 0, 113, 7} || m.Answers[0].Header.TTL != 60 {
+			t.Fatalf("%s: answer = %+v", name, m.Answers[0])
+		}
+	}
+}
+
+func TestQuerySRV(t *testing.T) {
+	s := startTestServer(t)
+
+	m := query(t, "udp", s.listen, "_web._tcp.nas.dyn.example.com.", dnsmessage.TypeSRV)
+	if len(m.Answers) != 1 {
+		t.Fatalf("answers = %+v", m.Answers)
+	}
+	srv := m.Answers[0].Body.(*dnsmessage.SRVResource)
+	if srv.Port != 40001 || srv.Target.String() != "web.nas.dyn.example.com." {
+		t.Fatalf("srv = %+v", srv)
+	}
+	// 目标在区域内时附带 A 记录
+	if len(m.Additionals) != 1 || m.Additionals[0].Header.Name.String() != "web.nas.dyn.example.com." {
+		t.Fatalf("additionals = %+v", m.Additionals)
+	}
+}
+
+func TestQueryTXT(t *testing.T) {
+	s := startTestServer(t)
+
+	m := query(t, "tcp", s.listen, "web.nas.dyn.example.com.", dnsmessage.TypeTXT)
+	if len(m.Answers) != 1 {
+		t.Fatalf("answers = %+v", m.Answers)
+	}
+	if txt := m.Answers[0].Body.(*dnsmessage.TXTResource).TXT; strings.Join(txt, "") != "https://web.nas.dyn.example.com:40001" {
+		t.Fatalf("txt = %q", txt)
+	}
+}
+
+func TestQuerySOA(t *testing.T) {
+	s := startTestServer(t)
+
+	m := query(t, "udp", s.listen, "dyn.example.com.", dnsmessage.TypeSOA)
+	if len(m.Answers) != 1 {
+		t.Fatalf("answers = %+v", m.Answers)
+	}
+	soa := m.Answers[0].Body.(*dnsmessage.SOAResource)
+	if soa.NS.String() != "ns.dyn.example.com." || soa.MBox.String() != "hostmaster.dyn.example.com." || soa.Serial != 1 || soa.MinTTL != 60 {
+		t.Fatalf("soa = %+v", soa)
+	}
+}
+
+func TestQueryNegative(t *testing.T) {
+	s := startTestServer(t)
+
+	// 未运行的服务没有记录
+	m := query(t, "udp", s.listen, "ssh.nas.dyn.example.com.", dnsmessage.TypeA)
+	if m.RCode != dnsmessage.RCodeNameError || len(m.Authorities) != 1 || m.Authorities[0].Header.Type != dnsmessage.TypeSOA {
+		t.Fatalf("rcode = %v, authorities = %+v", m.RCode, m.Authorities)
+	}
+	// 名称存在但没有该类型的记录
+	m = query(t, "udp", s.listen, "nas.dyn.example.com.", dnsmessage.TypeSRV)
+	if m.RCode != dnsmessage.RCodeSuccess || len(m.Answers) != 0 || len(m.Authorities) != 1 {
+		t.Fatalf("nodata: rcode = %v, answers = %d", m.RCode, len(m.Answers))
+	}
+	m = query(t, "udp", s.listen, "example.org.", dnsmessage.TypeA)
+	if m.RCode != dnsmessage.RCodeRefused || m.Authoritative {
+		t.Fatalf("out of zone: rcode = %v", m.RCode)
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	s := startTestServer(t)
+
+	// 同名大量 A 记录使应答超过 512 字节
+	s.zoneRecords()
+	s.recordsMu.Lock()
+	for i := 0; i < 40; i++ {
+		s.records = append(s.records, Record{Name: "many.dyn.example.com", Type: "A", TTL: 60, ip: [4]byte{10, 0, 0, byte(i)}})
+	}
+	s.recordsMu.Unlock()
+
+	m := query(t, "udp", s.listen, "many.dyn.example.com.", dnsmessage.TypeA)
+	if !m.Truncated || len(m.Answers) != 0 || len(m.Questions) != 1 {
+		t.Fatalf("udp: truncated = %v, answers = %d", m.Truncated, len(m.Answers))
+	}
+	m = query(t, "tcp", s.listen, "many.dyn.example.com.", dnsmessage.TypeA)
+	if m.Truncated || len(m.Answers) != 40 {
+		t.Fatalf("tcp: truncated = %v, answers = %d", m.Truncated, len(m.Answers))
+	}
+}
+
+func TestZoneCache(t *testing.T) {
+	s := startTestServer(t)
+
+	if n := len(query(t, "udp", s.listen, "ssh.nas.dyn.example.com.", dnsmessage.TypeA).Answers); n != 0 {
+		t.Fatalf("offline service answered %d records", n)
+	}
+	// 服务上线后缓存失效才会出现新记录
+	sshRunning.Store(true)
+	if n := len(query(t, "udp", s.listen, "ssh.nas.dyn.example.com.", dnsmessage.TypeA).Answers); n != 0 {
+		t.Fatal("records should come from the cache until invalidated")
+	}
+	s.invalidate()
+	if n := len(query(t, "udp", s.listen, "ssh.nas.dyn.example.com.", dnsmessage.TypeA).Answers); n != 1 {
+		t.Fatalf("answers = %d after invalidate, want 1", n)
+	}
+
+	s.setPublicIP("198.51.100.1")
+	a := query(t, "udp", s.listen, "dyn.example.com.", dnsmessage.TypeA).Answers[0].Body.(*dnsmessage.AResource)
+	if a.A != [4]byte{198, 51, 100, 1} {
+		t.Fatalf("A = %v after public IP change", a.A)
+	}
+}
@@ -0,0 +1,151 @@
+package event
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Envelope 带序号的事件
+type Envelope struct {
+	ID   uint64    `json:"id"`   // 递增的事件ID，SSE 断线重连时通过 Last-Event-ID 续传
+	Type Kind      `json:"type"` // 事件类型
+	Time time.Time `json:"time"` // 发布时间
+	Data Event     `json:"data"` // 事件内容
+}
+
+// Bus 进程内发布/订阅总线
+// 发布永不阻塞：订阅者缓冲写满时丢弃该订阅者的事件并计数，不影响隧道等发布方
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Envelope // 最近的状态事件（不含 Transient 类型），用于续传
+	historyFrom uint64     // 该ID及之后的状态事件都在 history 中
+	historySize int
+	subs        map[*Subscription]struct{}
+}
+
+// NewBus 创建总线，historySize 为保留用于续传的事件数
+func NewBus(historySize int) *Bus {
+	return &Bus{
+		historySize: historySize,
+		historyFrom: 1,
+		subs:        make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish 发布事件
+func (b *Bus) Publish(e Event) Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	env := Envelope{ID: b.lastID, Type: e.Kind(), Time: time.Now(), Data: e}
+
+	if b.historySize > 0 && !env.Type.Transient() {
+		if len(b.history) >= b.historySize {
+			b.historyFrom = b.history[0].ID + 1
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, env)
+	}
+
+	for sub := range b.subs {
+		if !sub.match(env.Type) {
+			continue
+		}
+		select {
+		case sub.ch <- env:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+	return env
+}
+
+// Subscribe 订阅事件，buffer 为缓冲大小，kinds 为空表示订阅全部类型
+func (b *Bus) Subscribe(buffer int, kinds ...Kind) *Subscription {
+	sub, _, _ := b.SubscribeSince(0, buffer, kinds...)
+	return sub
+}
+
+// SubscribeSince 订阅并取回 lastID 之后的历史事件，Transient 类型的事件不续传
+// 历史已不完整（或 lastID 为 0、来自上一次运行）时 ok 为 false，调用方应先发送全量状态
+func (b *Bus) SubscribeSince(lastID uint64, buffer int, kinds ...Kind) (sub *Subscription, replay []Envelope, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Envelope, buffer)
+	sub = &Subscription{C: ch, LastID: b.lastID, bus: b, ch: ch}
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, k := range kinds {
+			sub.kinds[k] = true
+		}
+	}
+	b.subs[sub] = struct{}{}
+
+	switch {
+	case lastID == 0 || lastID > b.lastID:
+		return sub, nil, false
+	case lastID == b.lastID:
+		return sub, nil, true
+	case b.historySize == 0 || lastID+1 < b.historyFrom:
+		return sub, nil, false
+	}
+	for _, env := range b.history {
+		if env.ID > lastID && sub.match(env.Type) {
+			replay = append(replay, env)
+		}
+	}
+	return sub, replay, true
+}
+
+// Handle 订阅并在独立 goroutine 中逐个处理事件，Close 后退出
+func (b *Bus) Handle(buffer int, fn func(Envelope), kinds ...Kind) *Subscription {
+	sub := b.Subscribe(buffer, kinds...)
+	go func() {
+		for env := range sub.C {
+			fn(env)
+		}
+	}()
+	return sub
+}
+
+// LastID 最新的事件ID
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Subscription 一个订阅
+type Subscription struct {
+	C      <-chan Envelope // 事件通道，Close 后关闭
+	LastID uint64          // 订阅时最新的事件ID
+
+	bus     *Bus
+	ch      chan Envelope
+	kinds   map[Kind]bool
+	dropped atomic.Uint64
+}
+
+func (s *Subscription) match(k Kind) bool {
+	return s.kinds == nil || s.kinds[k]
+}
+
+// Dropped 因缓冲写满被丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close 取消订阅，可重复调用
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subs[s]; ok {
+		delete(s.bus.subs, s)
+		close(s.ch)
+	}
+}
@@ -0,0 +1,147 @@
+package event_test
+
+import (
+	"linkstar/modules/event"
+	"linkstar/modules/event/eventtest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func online(name string) event.ServiceOnline {
+	return event.ServiceOnline{ServiceRef: event.ServiceRef{ServiceName: name}}
+}
+
+func accepted(addr string) event.ConnectionAccepted {
+	return event.ConnectionAccepted{RemoteAddr: addr}
+}
+
+func TestPublishDelivers(t *testing.T) {
+	bus := event.NewBus(16)
+	rec := eventtest.NewRecorder(bus)
+	defer rec.Close()
+	onlyOffline := eventtest.NewRecorder(bus, event.KindServiceOffline)
+	defer onlyOffline.Close()
+
+	bus.Publish(online("ssh"))
+	bus.Publish(event.ServiceOffline{ServiceRef: event.ServiceRef{ServiceName: "ssh"}, Reason: "stopped"})
+
+	off := eventtest.Expect[event.ServiceOffline](t, rec, time.Second)
+	if off.Reason != "stopped" {
+		t.Fatalf("Reason = %q", off.Reason)
+	}
+	if got := rec.Kinds(); !slices.Equal(got, []event.Kind{event.KindServiceOnline, event.KindServiceOffline}) {
+		t.Fatalf("Kinds = %v", got)
+	}
+
+	eventtest.Expect[event.ServiceOffline](t, onlyOffline, time.Second)
+	eventtest.ExpectNone[event.ServiceOnline](t, onlyOffline, 50*time.Millisecond)
+}
+
+func TestOverflowDrops(t *testing.T) {
+	bus := event.NewBus(0)
+	slow := bus.Subscribe(2)
+	defer slow.Close()
+	rec := eventtest.NewRecorder(bus)
+	defer rec.Close()
+
+	for i := 0; i < 4; i++ {
+		bus.Publish(online("ssh"))
+	}
+	bus.Publish(event.ServiceOffline{})
+
+	// 缓冲写满后发布不阻塞，多出的事件只对该订阅者丢弃
+	if got := slow.Dropped(); got != 3 {
+		t.Fatalf("Dropped = %d, want 3", got)
+	}
+	if got := len(slow.C); got != 2 {
+		t.Fatalf("buffered = %d, want 2", got)
+	}
+	eventtest.Expect[event.ServiceOffline](t, rec, time.Second)
+	if got := len(rec.Envelopes()); got != 5 {
+		t.Fatalf("recorder got %d events, want 5", got)
+	}
+}
+
+func TestSubscribeSinceReplay(t *testing.T) {
+	bus := event.NewBus(8)
+	first := bus.Publish(online("a"))
+	bus.Publish(accepted("1.2.3.4:5"))
+	bus.Publish(event.ServiceOffline{ServiceRef: event.ServiceRef{ServiceName: "a"}})
+	bus.Publish(online("b"))
+
+	sub, replay, ok := bus.SubscribeSince(first.ID, 8, event.KindServiceOnline)
+	defer sub.Close()
+	if !ok {
+		t.Fatal("history should be complete")
+	}
+	if len(replay) != 1 || replay[0].Data.(event.ServiceOnline).ServiceName != "b" {
+		t.Fatalf("replay = %+v, want online b", replay)
+	}
+
+	// 重放之后发布的事件走通道
+	rec := eventtest.NewRecorder(bus)
+	defer rec.Close()
+	bus.Publish(online("c"))
+	if env := <-sub.C; env.Data.(event.ServiceOnline).ServiceName != "c" {
+		t.Fatalf("live event = %+v", env)
+	}
+	eventtest.Expect[event.ServiceOnline](t, rec, time.Second)
+}
+
+func TestTransientNotInHistory(t *testing.T) {
+	bus := event.NewBus(2)
+	start := bus.Publish(online("start"))
+	bus.Publish(online("a"))
+	bus.Publish(online("b"))
+	for i := 0; i < 100; i++ {
+		bus.Publish(accepted("1.2.3.4:5"))
+	}
+
+	// 大量连接事件不会挤掉状态事件
+	sub, replay, ok := bus.SubscribeSince(start.ID, 8)
+	defer sub.Close()
+	if !ok {
+		t.Fatal("connection events should not evict state events")
+	}
+	if len(replay) != 2 || replay[0].Data.(event.ServiceOnline).ServiceName != "a" || replay[1].Data.(event.ServiceOnline).ServiceName != "b" {
+		t.Fatalf("replay = %+v", replay)
+	}
+}
+
+func TestSubscribeSinceGap(t *testing.T) {
+	bus := event.NewBus(2)
+	ids := make([]uint64, 0, 4)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		ids = append(ids, bus.Publish(online(name)).ID)
+	}
+
+	cases := []struct {
+		name   string
+		lastID uint64
+		ok     bool
+		replay int
+	}{
+		{"fresh client", 0, false, 0},
+		{"from previous run", ids[3] + 10, false, 0},
+		{"evicted", ids[0], false, 0},
+		{"oldest kept", ids[1], true, 2},
+		{"up to date", ids[3], true, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub, replay, ok := bus.SubscribeSince(tc.lastID, 1)
+			defer sub.Close()
+			if ok != tc.ok || len(replay) != tc.replay {
+				t.Fatalf("ok = %v, replay = %d; want %v, %d", ok, len(replay), tc.ok, tc.replay)
+			}
+		})
+	}
+
+	// 不保留历史时只有已是最新才算完整
+	empty := event.NewBus(0)
+	last := empty.Publish(online("a"))
+	if _, _, ok := empty.SubscribeSince(last.ID-1, 1); ok {
+		t.Fatal("bus without history cannot replay")
+	}
+}
@@ -0,0 +1,20 @@
+package event
+
+// Default 全局总线，stun 模块的状态变化都发布到这里
+// 测试时可替换为 NewBus 创建的新总线
+var Default = NewBus(256)
+
+// Publish 发布到全局总线
+func Publish(e Event) Envelope {
+	return Default.Publish(e)
+}
+
+// Subscribe 订阅全局总线
+func Subscribe(buffer int, kinds ...Kind) *Subscription {
+	return Default.Subscribe(buffer, kinds...)
+}
+
+// Handle 订阅全局总线并在独立 goroutine 中处理事件
+func Handle(buffer int, fn func(Envelope), kinds ...Kind) *Subscription {
+	return Default.Handle(buffer, fn, kinds...)
+}
@@ -0,0 +1,122 @@
+package event
+
+import "time"
+
+// Kind 事件类型，同时作为 SSE 的 event 名
+type Kind string
+
+const (
+	KindServiceOnline      Kind = "service.online"      // 服务打洞成功上线
+	KindServiceOffline     Kind = "service.offline"     // 服务下线
+	KindPortDrift          Kind = "service.portDrift"   // 健康检查发现公网端口漂移
+	KindPublicIPChanged    Kind = "publicIP.changed"    // 本机公网IP变化
+	KindMappingAdded       Kind = "mapping.added"       // UPnP 映射成功
+	KindMappingRemoved     Kind = "mapping.removed"     // UPnP 映射删除
+	KindMappingFailed      Kind = "mapping.failed"      // UPnP 映射失败
+	KindGatewayChanged     Kind = "gateway.changed"     // 默认 UPnP 网关变化
+	KindConnectionAccepted Kind = "connection.accepted" // 收到外部连接
+	KindIPBanned           Kind = "ip.banned"           // 地址被自动封禁
+)
+
+// Transient 连接级事件，数量随外部访问增长，不进入续传历史，避免挤掉上下线等状态事件
+func (k Kind) Transient() bool {
+	return k == KindConnectionAccepted || k == KindIPBanned
+}
+
+// Event 所有事件实现该接口
+type Event interface {
+	Kind() Kind
+}
+
+// ServiceRef 事件所属的服务
+type ServiceRef struct {
+	DeviceID    uint   `json:"deviceId"`    // 设备ID
+	DeviceName  string `json:"deviceName"`  // 设备名称
+	DeviceIP    string `json:"deviceIP"`    // 设备内网IP
+	ServiceID   uint   `json:"serviceId"`   // 服务ID
+	ServiceName string `json:"serviceName"` // 服务名称
+	Protocol    string `json:"protocol"`    // 协议 TCP/UDP
+	TLS         bool   `json:"tls"`         // 是否为 https 服务
+}
+
+// ServiceOnline 服务打洞成功上线
+type ServiceOnline struct {
+	ServiceRef
+	PublicAddr string `json:"publicAddr"` // 公网地址 IP:端口
+	OldAddr    string `json:"oldAddr"`    // 上次的公网地址，首次上线为空
+	PublicURL  string `json:"publicURL"`  // 访问地址
+}
+
+// ServiceOffline 服务下线
+type ServiceOffline struct {
+	ServiceRef
+	PublicAddr string `json:"publicAddr"` // 下线前的公网地址
+	Reason     string `json:"reason"`     // 下线原因
+}
+
+// PortDrift 公网端口漂移，服务随后会重新打洞
+type PortDrift struct {
+	ServiceRef
+	PublicIP string `json:"publicIP"` // 公网IP
+	OldPort  int    `json:"oldPort"`  // 原公网端口
+	NewPort  int    `json:"newPort"`  // 新公网端口
+}
+
+// PublicIPChanged 本机公网IP变化
+type PublicIPChanged struct {
+	OldIP string `json:"oldIP"` // 旧公网IP，首次获取为空
+	NewIP string `json:"newIP"` // 新公网IP
+}
+
+// MappingAdded UPnP 映射成功
+type MappingAdded struct {
+	ServiceRef
+	ExternalPort uint16 `json:"externalPort"` // 路由器 WAN 端口
+	InternalPort uint16 `json:"internalPort"` // 本机端口
+	Gateway      string `json:"gateway"`      // 使用的网关
+}
+
+// MappingRemoved UPnP 映射删除
+type MappingRemoved struct {
+	ServiceRef
+	ExternalPort uint16 `json:"externalPort"` // 路由器 WAN 端口
+	Gateway      string `json:"gateway"`      // 使用的网关
+}
+
+// MappingFailed UPnP 映射失败（非致命，打洞仍会继续）
+type MappingFailed struct {
+	ServiceRef
+	ExternalPort uint16 `json:"externalPort"` // 路由器 WAN 端口
+	Error        string `json:"error"`        // 失败原因
+}
+
+// GatewayChanged 默认 UPnP 网关变化
+type GatewayChanged struct {
+	OldGateway string `json:"oldGateway"` // 旧网关，如 "IGDv2 192.168.1.1"，未发现为空
+	NewGateway string `json:"newGateway"` // 新网关
+}
+
+// ConnectionAccepted 收到外部连接
+type ConnectionAccepted struct {
+	ServiceRef
+	RemoteAddr string `json:"remoteAddr"` // 客户端地址
+}
+
+// IPBanned 地址被自动封禁
+type IPBanned struct {
+	ServiceRef
+	IP        string    `json:"ip"`        // 被封禁的地址
+	Reason    string    `json:"reason"`    // 封禁原因
+	ExpiresAt time.Time `json:"expiresAt"` // 解封时间
+}
+
+func (ServiceOnline) Kind() Kind      { return KindServiceOnline }
+func (ServiceOffline) Kind() Kind     { return KindServiceOffline }
+func (PortDrift) Kind() Kind          { return KindPortDrift }
+func (PublicIPChanged) Kind() Kind    { return KindPublicIPChanged }
+func (MappingAdded) Kind() Kind       { return KindMappingAdded }
+func (MappingRemoved) Kind() Kind     { return KindMappingRemoved }
+func (MappingFailed) Kind() Kind      { return KindMappingFailed }
+func (GatewayChanged) Kind() Kind     { return KindGatewayChanged }
+func (ConnectionAccepted) Kind() Kind { return KindConnectionAccepted }
+func (IPBanned) Kind() Kind           { return KindIPBanned }
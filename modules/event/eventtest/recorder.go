@@ -0,0 +1,140 @@
+// Package eventtest 提供断言事件总线发布内容的测试辅助
+//
+//	bus := event.NewBus(0)
+//	event.Default = bus
+//	rec := eventtest.NewRecorder(bus)
+//	defer rec.Close()
+//	... 触发状态变化 ...
+//	drift := eventtest.Expect[event.PortDrift](t, rec, time.Second)
+package eventtest
+
+import (
+	"linkstar/modules/event"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Recorder 记录总线上发布的事件
+type Recorder struct {
+	sub *event.Subscription
+
+	mu     sync.Mutex
+	events []event.Envelope
+	notify chan struct{} // 每记录一个事件关闭并重建，唤醒等待方
+	done   chan struct{}
+}
+
+// NewRecorder 开始记录 bus 上的事件，kinds 为空表示全部类型
+func NewRecorder(bus *event.Bus, kinds ...event.Kind) *Recorder {
+	r := &Recorder{
+		sub:    bus.Subscribe(1024, kinds...),
+		notify: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(r.done)
+		for env := range r.sub.C {
+			r.mu.Lock()
+			r.events = append(r.events, env)
+			close(r.notify)
+			r.notify = make(chan struct{})
+			r.mu.Unlock()
+		}
+	}()
+	return r
+}
+
+// Close 停止记录
+func (r *Recorder) Close() {
+	r.sub.Close()
+	<-r.done
+}
+
+// Envelopes 已记录的事件（含序号）
+func (r *Recorder) Envelopes() []event.Envelope {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]event.Envelope(nil), r.events...)
+}
+
+// Events 已记录的事件
+func (r *Recorder) Events() []event.Event {
+	envs := r.Envelopes()
+	events := make([]event.Event, len(envs))
+	for i, env := range envs {
+		events[i] = env.Data
+	}
+	return events
+}
+
+// Kinds 已记录事件的类型，按发布顺序
+func (r *Recorder) Kinds() []event.Kind {
+	envs := r.Envelopes()
+	kinds := make([]event.Kind, len(envs))
+	for i, env := range envs {
+		kinds[i] = env.Type
+	}
+	return kinds
+}
+
+// Reset 清空已记录的事件
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// Wait 等待第一个满足 match 的事件，超时返回 false
+func (r *Recorder) Wait(timeout time.Duration, match func(event.Event) bool) (event.Event, bool) {
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		for _, env := range r.events {
+			if match(env.Data) {
+				r.mu.Unlock()
+				return env.Data, true
+			}
+		}
+		notify := r.notify
+		r.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return nil, false
+		}
+	}
+}
+
+// WaitFor 等待第一个 T 类型的事件
+func WaitFor[T event.Event](r *Recorder, timeout time.Duration) (T, bool) {
+	e, ok := r.Wait(timeout, func(e event.Event) bool {
+		_, ok := e.(T)
+		return ok
+	})
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return e.(T), true
+}
+
+// Expect 断言 timeout 内发布了 T 类型的事件并返回它
+func Expect[T event.Event](t testing.TB, r *Recorder, timeout time.Duration) T {
+	t.Helper()
+	e, ok := WaitFor[T](r, timeout)
+	if !ok {
+		var zero T
+		t.Fatalf("%v 内未收到 %s 事件，已收到: %v", timeout, zero.Kind(), r.Kinds())
+	}
+	return e
+}
+
+// ExpectNone 断言 wait 时间内没有发布 T 类型的事件
+func ExpectNone[T event.Event](t testing.TB, r *Recorder, wait time.Duration) {
+	t.Helper()
+	if e, ok := WaitFor[T](r, wait); ok {
+		t.Fatalf("不应收到 %s 事件: %+v", e.Kind(), e)
+	}
+}
@@ -0,0 +1,197 @@
+package firewall
+
+import (
+	"linkstar/modules/event"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Ban 一条封禁记录
+type Ban struct {
+	IP        string    `json:"ip"`        // 被封禁的地址
+	Reason    string    `json:"reason"`    // 封禁原因
+	Service   string    `json:"service"`   // 触发封禁的服务，如 "NAS - SSH"
+	BannedAt  time.Time `json:"bannedAt"`  // 封禁时间
+	ExpiresAt time.Time `json:"expiresAt"` // 解封时间
+}
+
+// 单个地址在统计窗口内的连接记录
+type ipStats struct {
+	conns    []time.Time // 新建连接的时间
+	failures []time.Time // 快速断开的时间
+}
+
+var (
+	banMu sync.Mutex
+	bans  = map[string]*Ban{}
+	stats = map[string]*ipStats{}
+)
+
+// 去掉窗口之前的记录
+func prune(times []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(since) {
+		i++
+	}
+	return times[i:]
+}
+
+// 是否为不自动封禁的内网、回环地址
+func exempt(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+}
+
+// 被封禁时返回封禁记录，过期的记录顺带删除，调用方需持有 banMu
+func activeBan(ip string) *Ban {
+	b, ok := bans[ip]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(b.ExpiresAt) {
+		delete(bans, ip)
+		return nil
+	}
+	return b
+}
+
+// 封禁地址并发布事件，调用方需持有 banMu
+func ban(ip string, reason string, ref event.ServiceRef, d time.Duration) {
+	now := time.Now()
+	b := &Ban{
+		IP:        ip,
+		Reason:    reason,
+		Service:   ref.DeviceName + " - " + ref.ServiceName,
+		BannedAt:  now,
+		ExpiresAt: now.Add(d),
+	}
+	bans[ip] = b
+	delete(stats, ip)
+	logrus.Warnf("[%s] 自动封禁 %s 至 %s: %s", b.Service, ip, b.ExpiresAt.Format(time.DateTime), reason)
+	event.Publish(event.IPBanned{ServiceRef: ref, IP: ip, Reason: reason, ExpiresAt: b.ExpiresAt})
+}
+
+// 记录一次新建连接，超过阈值时封禁并返回 true
+func recordConn(ip net.IP, ref event.ServiceRef) bool {
+	a := GetSettings().AutoBan
+	if !a.Enabled || a.MaxConnections <= 0 || exempt(ip) {
+		return false
+	}
+	key := ip.String()
+	now := time.Now()
+
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	if activeBan(key) != nil {
+		return true
+	}
+	s := stats[key]
+	if s == nil {
+		s = &ipStats{}
+		stats[key] = s
+	}
+	s.conns = append(prune(s.conns, now.Add(-a.window())), now)
+	if len(s.conns) > a.MaxConnections {
+		ban(key, "连接过于频繁", ref, a.banTime())
+		return true
+	}
+	return false
+}
+
+// 记录一次连接关闭，建立后很快断开的计为失败，超过阈值时封禁
+func recordClose(ip net.IP, ref event.ServiceRef, lived time.Duration) {
+	a := GetSettings().AutoBan
+	if !a.Enabled || a.MaxFailures <= 0 || exempt(ip) || lived >= a.failWithin() {
+		return
+	}
+	key := ip.String()
+	now := time.Now()
+
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	if activeBan(key) != nil {
+		return
+	}
+	s := stats[key]
+	if s == nil {
+		s = &ipStats{}
+		stats[key] = s
+	}
+	s.failures = append(prune(s.failures, now.Add(-a.window())), now)
+	if len(s.failures) > a.MaxFailures {
+		ban(key, "连接频繁快速断开", ref, a.banTime())
+	}
+}
+
+// 定期清理过期的封禁和统计记录
+func cleanup() {
+	window := GetSettings().AutoBan.window()
+	now := time.Now()
+
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	for ip := range bans {
+		activeBan(ip)
+	}
+	for ip, s := range stats {
+		s.conns = prune(s.conns, now.Add(-window))
+		s.failures = prune(s.failures, now.Add(-window))
+		if len(s.conns) == 0 && len(s.failures) == 0 {
+			delete(stats, ip)
+		}
+	}
+}
+
+// ListBans 当前生效的封禁，按解封时间排序
+func ListBans() []Ban {
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	list := make([]Ban, 0, len(bans))
+	for ip := range bans {
+		if b := activeBan(ip); b != nil {
+			list = append(list, *b)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ExpiresAt.Before(list[j].ExpiresAt) })
+	return list
+}
+
+// Unban 解除单个地址的封禁
+func Unban(ip string) error {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	if activeBan(ip) == nil {
+		return ErrBanNotFound
+	}
+	delete(bans, ip)
+	delete(stats, ip)
+	return nil
+}
+
+// ClearBans 解除全部封禁，返回解除的数量
+func ClearBans() int {
+	banMu.Lock()
+	defer banMu.Unlock()
+
+	n := 0
+	for ip := range bans {
+		if activeBan(ip) != nil {
+			n++
+		}
+	}
+	bans = map[string]*Ban{}
+	stats = map[string]*ipStats{}
+	return n
+}
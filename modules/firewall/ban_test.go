@@ -0,0 +1,92 @@
+package firewall
+
+import (
+	"errors"
+	"linkstar/modules/event"
+	"linkstar/modules/event/eventtest"
+	"net"
+	"testing"
+	"time"
+)
+
+func useSettings(t *testing.T, s Settings) {
+	configMu.Lock()
+	old := config.Settings
+	applySettings(s)
+	configMu.Unlock()
+	ClearBans()
+	t.Cleanup(func() {
+		configMu.Lock()
+		applySettings(old)
+		configMu.Unlock()
+		ClearBans()
+	})
+}
+
+// 只提供远端地址的连接
+type addrConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c *addrConn) RemoteAddr() net.Addr { return c.remote }
+func (c *addrConn) Close() error         { return nil }
+
+func connFrom(ip string) net.Conn {
+	return &addrConn{remote: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}}
+}
+
+func TestTrackRejectsConnectionThatTriggersBan(t *testing.T) {
+	useSettings(t, Settings{AutoBan: AutoBan{Enabled: true, MaxConnections: 2}})
+	rec := eventtest.NewRecorder(event.Default, event.KindIPBanned)
+	defer rec.Close()
+	ref := event.ServiceRef{DeviceName: "NAS", ServiceName: "SSH"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Track(connFrom("203.0.113.9"), ref); err != nil {
+			t.Fatalf("connection %d: %v", i+1, err)
+		}
+	}
+	if _, err := Track(connFrom("203.0.113.9"), ref); !errors.Is(err, ErrBanned) {
+		t.Fatalf("connection over the limit: err = %v, want ErrBanned", err)
+	}
+	if banned := eventtest.Expect[event.IPBanned](t, rec, time.Second); banned.IP != "203.0.113.9" {
+		t.Fatalf("IPBanned = %+v", banned)
+	}
+	if err := Check(connFrom("203.0.113.9").RemoteAddr(), nil); !errors.Is(err, ErrBanned) {
+		t.Fatalf("Check after ban: %v", err)
+	}
+
+	// 内网地址不计数
+	for i := 0; i < 5; i++ {
+		if _, err := Track(connFrom("192.168.1.2"), ref); err != nil {
+			t.Fatalf("private address banned: %v", err)
+		}
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	useSettings(t, Settings{Allow: []string{"198.51.100.0/24"}, Deny: []string{"198.51.100.66/32"}})
+	addr := func(ip string) net.Addr { return connFrom(ip).RemoteAddr() }
+
+	cases := []struct {
+		name  string
+		ip    string
+		rules *Rules
+		want  error
+	}{
+		{"global allow", "198.51.100.1", nil, nil},
+		{"not in global allow", "203.0.113.1", nil, ErrNotAllowed},
+		{"global deny", "198.51.100.66", nil, ErrDenied},
+		{"service allow overrides global", "203.0.113.1", NewRules([]string{"203.0.113.0/24"}, nil), nil},
+		{"service allow excludes global", "198.51.100.1", NewRules([]string{"203.0.113.0/24"}, nil), ErrNotAllowed},
+		{"service deny", "198.51.100.7", NewRules(nil, []string{"198.51.100.7/32"}), ErrDenied},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Check(addr(tc.ip), tc.rules); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/utils/utilsFile"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const firewallConfigPath = "config/firewall.json"
+
+var (
+	ErrBanned      = errors.New("地址已被封禁")
+	ErrDenied      = errors.New("地址在禁止列表中")
+	ErrNotAllowed  = errors.New("地址不在允许列表中")
+	ErrBanNotFound = errors.New("该地址没有被封禁")
+	ErrSaveConfig  = errors.New("保存防火墙配置失败")
+)
+
+// Config 防火墙配置文件（config/firewall.json），封禁记录只保存在内存中，重启后清空
+type Config struct {
+	Settings Settings `json:"settings"`
+}
+
+// Settings 全局访问控制，对所有服务生效
+type Settings struct {
+	Allow   []string `json:"allow" binding:"omitempty,dive,cidr"` // 全局允许列表，非空时只接受其中的地址；服务设置了允许列表时以服务为准
+	Deny    []string `json:"deny" binding:"omitempty,dive,cidr"`  // 全局禁止列表
+	AutoBan AutoBan  `json:"autoBan"`                             // 自动封禁
+}
+
+// AutoBan 自动封禁：统计窗口内连接过多或频繁快速断开（扫描、爆破）的地址临时封禁
+// 内网与回环地址不会被自动封禁
+type AutoBan struct {
+	Enabled        bool `json:"enabled"`                            // 是否启用
+	Window         int  `json:"window" binding:"min=0"`             // 统计窗口（秒），0 为 60
+	MaxConnections int  `json:"maxConnections" binding:"min=0"`     // 窗口内单个地址最多新建的连接数，0 不限制
+	MaxFailures    int  `json:"maxFailures" binding:"min=0"`        // 窗口内单个地址最多快速断开的次数，0 不限制
+	FailWithin     int  `json:"failWithin" binding:"min=0,max=300"` // 建立后多少秒内断开视为失败，0 为 3
+	BanMinutes     int  `json:"banMinutes" binding:"min=0"`         // 封禁时长（分钟），0 为 60
+}
+
+func (a AutoBan) window() time.Duration {
+	if a.Window <= 0 {
+		return time.Minute
+	}
+	return time.Duration(a.Window) * time.Second
+}
+
+func (a AutoBan) failWithin() time.Duration {
+	if a.FailWithin <= 0 {
+		return 3 * time.Second
+	}
+	return time.Duration(a.FailWithin) * time.Second
+}
+
+func (a AutoBan) banTime() time.Duration {
+	if a.BanMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(a.BanMinutes) * time.Minute
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+
+	// 解析后的全局列表，随配置更新
+	globalAllow []*net.IPNet
+	globalDeny  []*net.IPNet
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(firewallConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](firewallConfigPath); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(firewallConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(firewallConfigPath, config); err != nil {
+		logrus.Error("防火墙配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 解析 CIDR 列表，忽略不合法的条目（已由 binding 校验）
+func parseCIDRs(list []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		if _, n, err := net.ParseCIDR(s); err == nil {
+			nets = append(nets, n)
+		}
+	}
+	return nets
+}
+
+// 应用配置，调用方需持有 configMu
+func applySettings(s Settings) {
+	config.Settings = s
+	globalAllow = parseCIDRs(s.Allow)
+	globalDeny = parseCIDRs(s.Deny)
+}
+
+// GetSettings 全局访问控制设置
+func GetSettings() Settings {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return config.Settings
+}
+
+// UpdateSettings 修改全局访问控制设置，立即对新连接生效
+func UpdateSettings(s Settings) (Settings, error) {
+	if s.Allow == nil {
+		s.Allow = []string{}
+	}
+	if s.Deny == nil {
+		s.Deny = []string{}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	old := config.Settings
+	applySettings(s)
+	if err := saveConfig(); err != nil {
+		applySettings(old)
+		return Settings{}, err
+	}
+	return s, nil
+}
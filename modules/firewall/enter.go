@@ -0,0 +1,118 @@
+package firewall
+
+import (
+	"linkstar/modules/event"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Init 读取防火墙配置并启动过期记录清理，需在启动服务前调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取防火墙配置失败: %v", err)
+	}
+	configMu.Lock()
+	applySettings(c.Settings)
+	configMu.Unlock()
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			cleanup()
+		}
+	}()
+}
+
+// 连接的客户端 IP
+func remoteIP(addr net.Addr) net.IP {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules 服务自己的允许、禁止列表，启动服务时解析一次，避免每个连接重复解析
+type Rules struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// NewRules 解析服务的 CIDR 列表，调用方需保证 allow / deny 在解析期间不被修改
+func NewRules(allow, deny []string) *Rules {
+	return &Rules{allow: parseCIDRs(allow), deny: parseCIDRs(deny)}
+}
+
+// Check 按封禁、禁止列表、允许列表依次检查客户端地址，rules 为服务自己的列表，可为 nil
+// 服务设置了允许列表时以服务为准，否则使用全局允许列表，两者都为空时不限制
+func Check(addr net.Addr, rules *Rules) error {
+	ip := remoteIP(addr)
+	if ip == nil {
+		return nil
+	}
+
+	banMu.Lock()
+	banned := activeBan(ip.String()) != nil
+	banMu.Unlock()
+	if banned {
+		return ErrBanned
+	}
+
+	configMu.Lock()
+	gAllow, gDeny := globalAllow, globalDeny
+	configMu.Unlock()
+
+	if rules == nil {
+		rules = &Rules{}
+	}
+	if containsIP(gDeny, ip) || containsIP(rules.deny, ip) {
+		return ErrDenied
+	}
+	allowNets := gAllow
+	if len(rules.allow) > 0 {
+		allowNets = rules.allow
+	}
+	if len(allowNets) > 0 && !containsIP(allowNets, ip) {
+		return ErrNotAllowed
+	}
+	return nil
+}
+
+// trackedConn 关闭时记录连接存活时间，用于识别快速断开
+type trackedConn struct {
+	net.Conn
+	ip       net.IP
+	ref      event.ServiceRef
+	openedAt time.Time
+	once     sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { recordClose(c.ip, c.ref, time.Since(c.openedAt)) })
+	return c.Conn.Close()
+}
+
+// Track 记录一次已放行的连接并返回包装后的连接，供自动封禁统计
+// 该连接使地址超过阈值被封禁时返回 ErrBanned，调用方应关闭连接
+func Track(conn net.Conn, ref event.ServiceRef) (net.Conn, error) {
+	ip := remoteIP(conn.RemoteAddr())
+	if ip == nil {
+		return conn, nil
+	}
+	if recordConn(ip, ref) {
+		return nil, ErrBanned
+	}
+	return &trackedConn{Conn: conn, ip: ip, ref: ref, openedAt: time.Now()}, nil
+}
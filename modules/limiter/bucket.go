@@ -0,0 +1,112 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket 令牌桶，容量为一秒的令牌；允许透支，透支部分由调用方等待补齐
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒令牌数，0 不限制
+	tokens float64
+	last   time.Time
+}
+
+// 按经过的时间补充令牌，调用方需持有 mu
+func (b *Bucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+	}
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+}
+
+// SetRate 修改速率，0 不限制
+func (b *Bucket) SetRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if rate <= 0 {
+		b.rate, b.tokens, b.last = 0, 0, time.Time{}
+		return
+	}
+	if b.rate <= 0 {
+		// 从不限制切换过来时桶是满的
+		b.rate, b.tokens, b.last = rate, rate, time.Now()
+		return
+	}
+	b.refill(time.Now())
+	b.rate = rate
+	if b.tokens > rate {
+		b.tokens = rate
+	}
+}
+
+// Rate 当前速率，0 不限制
+func (b *Bucket) Rate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// 取 n 个令牌，返回需要等待的时间
+func (b *Bucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Allow 不等待地取一个令牌，令牌不足时返回 false
+func (b *Bucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return true
+	}
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// 从多个桶同时取 n 个令牌，等待其中最久的一个
+func wait(n int, buckets ...*Bucket) {
+	var d time.Duration
+	for _, b := range buckets {
+		if w := b.reserve(n); w > d {
+			d = w
+		}
+	}
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// 单次读写的最大字节数，取最小速率的 1/10 秒，避免一次读写透支过多造成卡顿；都不限制时返回 0
+func chunk(buckets ...*Bucket) int {
+	var lowest float64
+	for _, b := range buckets {
+		if r := b.Rate(); r > 0 && (lowest == 0 || r < lowest) {
+			lowest = r
+		}
+	}
+	if lowest == 0 {
+		return 0
+	}
+	return max(1024, min(32*1024, int(lowest/10)))
+}
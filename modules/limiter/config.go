@@ -0,0 +1,85 @@
+package limiter
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const limitsConfigPath = "config/limits.json"
+
+var ErrSaveConfig = errors.New("保存限速配置失败")
+
+// Config 限速配置文件（config/limits.json），服务自己的限制保存在服务配置中
+type Config struct {
+	Global Limits `json:"global"` // 所有服务合计的限制
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+
+	// 所有服务共享的全局 Limiter
+	global = New(Limits{})
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(limitsConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](limitsConfigPath); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(limitsConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(limitsConfigPath, config); err != nil {
+		logrus.Error("限速配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// Init 读取全局限制，需在启动服务前调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取限速配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+	global.Update(c.Global)
+}
+
+// GetGlobal 全局限制及当前并发连接数
+func GetGlobal() Status {
+	return global.Status()
+}
+
+// UpdateGlobal 修改全局限制，立即生效，不重启服务
+func UpdateGlobal(limits Limits) (Status, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	old := config.Global
+	config.Global = limits
+	if err := saveConfig(); err != nil {
+		config.Global = old
+		return Status{}, err
+	}
+	global.Update(limits)
+	return global.Status(), nil
+}
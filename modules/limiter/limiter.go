@@ -0,0 +1,174 @@
+package limiter
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+var (
+	ErrTooManyConnections = errors.New("并发连接数已达上限")
+	ErrConnRate           = errors.New("新建连接过于频繁")
+)
+
+// Limits 一组限制，0 表示不限制
+type Limits struct {
+	MaxConnections int `json:"maxConnections" binding:"min=0"` // 最大并发连接数
+	ConnRate       int `json:"connRate" binding:"min=0"`       // 每秒最多新建连接数
+	Upload         int `json:"upload" binding:"min=0"`         // 发往外部客户端的带宽（KB/s），占用宽带上行
+	Download       int `json:"download" binding:"min=0"`       // 从外部客户端接收的带宽（KB/s）
+}
+
+// Status 限制及当前使用情况
+type Status struct {
+	Limits
+	Connections int `json:"connections"` // 当前并发连接数
+}
+
+// Limiter 一个作用域（全局或单个服务）的限制状态，修改限制后对已建立的连接立即生效
+type Limiter struct {
+	mu       sync.Mutex
+	limits   Limits
+	active   int    // 当前并发连接数
+	conns    Bucket // 新建连接
+	up, down Bucket // 上行、下行字节
+}
+
+// New 按限制创建 Limiter
+func New(limits Limits) *Limiter {
+	l := &Limiter{}
+	l.Update(limits)
+	return l
+}
+
+// Update 修改限制，已建立的连接立即按新带宽限速，超出的并发连接不会被断开
+func (l *Limiter) Update(limits Limits) {
+	l.mu.Lock()
+	l.limits = limits
+	l.mu.Unlock()
+
+	l.conns.SetRate(float64(limits.ConnRate))
+	l.up.SetRate(float64(limits.Upload) * 1024)
+	l.down.SetRate(float64(limits.Download) * 1024)
+}
+
+// Status 当前限制及并发连接数
+func (l *Limiter) Status() Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Status{Limits: l.limits, Connections: l.active}
+}
+
+// 占用一个连接名额
+func (l *Limiter) acquire() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limits.MaxConnections > 0 && l.active >= l.limits.MaxConnections {
+		return ErrTooManyConnections
+	}
+	if !l.conns.Allow() {
+		return ErrConnRate
+	}
+	l.active++
+	return nil
+}
+
+func (l *Limiter) release() {
+	l.mu.Lock()
+	l.active--
+	l.mu.Unlock()
+}
+
+// limitedConn 按服务和全局带宽限速的连接，关闭时归还连接名额
+type limitedConn struct {
+	net.Conn
+	limiters []*Limiter
+	up, down []*Bucket // 各 Limiter 的上行、下行令牌桶
+	once     sync.Once
+}
+
+// 读取外部客户端发来的数据，计入下行
+func (c *limitedConn) Read(p []byte) (int, error) {
+	if n := chunk(c.down...); n > 0 && len(p) > n {
+		p = p[:n]
+	}
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		wait(n, c.down...)
+	}
+	return n, err
+}
+
+// 向外部客户端发送数据，计入上行
+func (c *limitedConn) Write(p []byte) (int, error) {
+	size := chunk(c.up...)
+	written := 0
+	for len(p) > 0 {
+		b := p
+		if size > 0 && len(b) > size {
+			b = b[:size]
+		}
+		wait(len(b), c.up...)
+		n, err := c.Conn.Write(b)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(func() {
+		for _, l := range c.limiters {
+			l.release()
+		}
+	})
+	return c.Conn.Close()
+}
+
+// Accept 按全局和服务的限制放行一个入站连接，返回限速后的连接；超出并发或新建速率时返回错误，由调用方关闭连接
+func Accept(conn net.Conn, service *Limiter) (net.Conn, error) {
+	limiters := []*Limiter{global, service}
+	for i, l := range limiters {
+		if err := l.acquire(); err != nil {
+			for _, acquired := range limiters[:i] {
+				acquired.release()
+			}
+			return nil, err
+		}
+	}
+	c := &limitedConn{Conn: conn, limiters: limiters}
+	for _, l := range limiters {
+		c.up = append(c.up, &l.up)
+		c.down = append(c.down, &l.down)
+	}
+	return c, nil
+}
+
+var (
+	servicesMu sync.Mutex
+	services   = map[string]*Limiter{}
+)
+
+// Service 取服务的 Limiter，不存在时创建一个不限制的
+func Service(key string) *Limiter {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+
+	l, ok := services[key]
+	if !ok {
+		l = New(Limits{})
+		services[key] = l
+	}
+	return l
+}
+
+// Remove 服务删除后移除其 Limiter，已建立的连接不受影响
+func Remove(key string) {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	delete(services, key)
+}
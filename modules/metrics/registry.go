@@ -0,0 +1,265 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 手写的 Prometheus 文本格式（0.0.4），只实现 counter / gauge / histogram
+
+// 已注册的指标，按名称输出
+var (
+	registryMu sync.Mutex
+	registry   = map[string]metric{}
+	collectors []func(w *Writer)
+)
+
+type metric interface {
+	write(w *Writer)
+}
+
+func register(name string, m metric) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic("metrics: 重复注册 " + name)
+	}
+	registry[name] = m
+}
+
+// RegisterCollector 注册抓取时计算的指标（状态、队列深度等）
+func RegisterCollector(fn func(w *Writer)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	collectors = append(collectors, fn)
+}
+
+// 一组标签值对应的一条时间序列
+type series struct {
+	values []string
+	value  float64
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	name, help string
+	labels     []string
+	mu         sync.Mutex
+	series     map[string]*series
+}
+
+// NewCounterVec 创建并注册计数器，名称应以 _total 结尾
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, series: map[string]*series{}}
+	register(name, c)
+	return c
+}
+
+// Inc 计数加一，values 与注册时的标签一一对应
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add 计数增加 v
+func (c *CounterVec) Add(v float64, values ...string) {
+	key := strings.Join(values, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{values: values}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *CounterVec) write(w *Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.Header(c.name, "counter", c.help)
+	for _, s := range sortedSeries(c.series) {
+		w.Sample(c.name, pairs(c.labels, s.values), s.value)
+	}
+}
+
+// 直方图的一条时间序列
+type histSeries struct {
+	values []string
+	counts []uint64 // 与 buckets 对应的非累计计数
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+	mu         sync.Mutex
+	series     map[string]*histSeries
+}
+
+// DurationBuckets 适合网络操作耗时（秒）的桶
+var DurationBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewHistogramVec 创建并注册直方图，buckets 需升序
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, series: map[string]*histSeries{}}
+	register(name, h)
+	return h
+}
+
+// Observe 记录一次观测值
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := strings.Join(values, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histSeries{values: values, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *HistogramVec) write(w *Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w.Header(h.name, "histogram", h.help)
+
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		labels := pairs(h.labels, s.values)
+		var cumulative uint64
+		for i, b := range h.buckets {
+			cumulative += s.counts[i]
+			w.Sample(h.name+"_bucket", append(labels, "le", formatFloat(b)), float64(cumulative))
+		}
+		w.Sample(h.name+"_bucket", append(labels, "le", "+Inf"), float64(s.count))
+		w.Sample(h.name+"_sum", labels, s.sum)
+		w.Sample(h.name+"_count", labels, float64(s.count))
+	}
+}
+
+func sortedSeries(m map[string]*series) []*series {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]*series, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, m[k])
+	}
+	return list
+}
+
+// 标签名与值交替排列
+func pairs(names, values []string) []string {
+	p := make([]string, 0, len(names)*2+2)
+	for i, n := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		p = append(p, n, v)
+	}
+	return p
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// Writer 按 Prometheus 文本格式输出
+type Writer struct {
+	w io.Writer
+}
+
+// Header 输出指标的 HELP 和 TYPE 行
+func (w *Writer) Header(name, typ, help string) {
+	fmt.Fprintf(w.w, "# HELP %s %s\n# TYPE %s %s\n", name, helpEscaper.Replace(help), name, typ)
+}
+
+// Sample 输出一个样本，labels 为标签名与值交替排列
+func (w *Writer) Sample(name string, labels []string, v float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(labelEscaper.Replace(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(v))
+	b.WriteByte('\n')
+	io.WriteString(w.w, b.String())
+}
+
+// Gauge 输出只有一个样本的 gauge
+func (w *Writer) Gauge(name, help string, v float64) {
+	w.Header(name, "gauge", help)
+	w.Sample(name, nil, v)
+}
+
+// WriteTo 输出全部指标：已注册的计数器和直方图、抓取时计算的指标、Go 运行时指标
+func WriteTo(out io.Writer) {
+	w := &Writer{w: out}
+
+	registryMu.Lock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, registry[name])
+	}
+	fns := append([]func(w *Writer){}, collectors...)
+	registryMu.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+	for _, fn := range fns {
+		fn(w)
+	}
+	writeRuntime(w)
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+var startTime = time.Now()
+
+// Go 运行时与进程指标
+func writeRuntime(w *Writer) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	w.Header("go_info", "gauge", "Go 版本")
+	w.Sample("go_info", []string{"version", runtime.Version()}, 1)
+	w.Gauge("go_goroutines", "当前 goroutine 数", float64(runtime.NumGoroutine()))
+	w.Gauge("go_threads", "创建过的系统线程数", float64(pprof.Lookup("threadcreate").Count()))
+	w.Gauge("go_memstats_alloc_bytes", "堆上已分配且仍在使用的字节", float64(m.Alloc))
+	w.Gauge("go_memstats_heap_inuse_bytes", "堆中正在使用的 span 字节", float64(m.HeapInuse))
+	w.Gauge("go_memstats_heap_objects", "堆上的对象数", float64(m.HeapObjects))
+	w.Gauge("go_memstats_sys_bytes", "从操作系统获取的字节", float64(m.Sys))
+	w.Header("go_memstats_alloc_bytes_total", "counter", "累计分配的堆字节")
+	w.Sample("go_memstats_alloc_bytes_total", nil, float64(m.TotalAlloc))
+	w.Header("go_gc_cycles_total", "counter", "完成的 GC 次数")
+	w.Sample("go_gc_cycles_total", nil, float64(m.NumGC))
+	w.Header("go_gc_pause_seconds_total", "counter", "GC 停顿累计时长")
+	w.Sample("go_gc_pause_seconds_total", nil, float64(m.PauseTotalNs)/1e9)
+	w.Gauge("process_start_time_seconds", "进程启动时间（Unix 秒）", float64(startTime.UnixNano())/1e9)
+}
@@ -0,0 +1,119 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"linkstar/global"
+	"linkstar/modules/event"
+	"linkstar/modules/stun"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrServiceNotFound = errors.New("服务不存在")
+	ErrServiceOffline  = errors.New("服务当前不在线")
+	ErrAmbiguous       = errors.New("存在多个同名服务，请通过 device 参数指定设备")
+)
+
+// Target 跳转目标
+type Target struct {
+	stun.OnlineService
+	Online bool `json:"online"` // 是否在线，不在线时 publicURL 为空
+}
+
+// ambiguousError 同名服务分布在多个设备上
+type ambiguousError struct {
+	devices []string
+}
+
+func (e *ambiguousError) Error() string {
+	return fmt.Sprintf("%s：%s", ErrAmbiguous.Error(), strings.Join(e.devices, "、"))
+}
+func (e *ambiguousError) Unwrap() error { return ErrAmbiguous }
+
+// 名称匹配：忽略大小写，空格与 - 视为相同
+func match(name, want string) bool {
+	normalize := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "-")
+	}
+	return normalize(name) == normalize(want)
+}
+
+// Lookup 按服务名查找跳转目标，device 为设备名或设备ID，同名服务分布在多个设备上时必填
+func Lookup(name, device string) (Target, error) {
+	var found []Target
+	for _, d := range stun.ListDevices() {
+		if device != "" && !match(d.Name, device) && strconv.FormatUint(uint64(d.DeviceID), 10) != device {
+			continue
+		}
+		for _, s := range d.Services {
+			if match(s.Name, name) {
+				found = append(found, Target{OnlineService: stun.OnlineService{
+					DeviceID: d.DeviceID, DeviceName: d.Name, ServiceID: s.ID, ServiceName: s.Name, Protocol: s.Protocol,
+				}})
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Target{}, ErrServiceNotFound
+	case 1:
+	default:
+		devices := make([]string, 0, len(found))
+		for _, t := range found {
+			devices = append(devices, t.DeviceName)
+		}
+		return Target{}, &ambiguousError{devices: devices}
+	}
+
+	t := found[0]
+	for _, s := range stun.OnlineServices() {
+		if s.DeviceID == t.DeviceID && s.ServiceID == t.ServiceID {
+			return Target{OnlineService: s, Online: true}, nil
+		}
+	}
+	return t, ErrServiceOffline
+}
+
+// List 全部在线服务
+func List() []Target {
+	online := stun.OnlineServices()
+	list := make([]Target, 0, len(online))
+	for _, s := range online {
+		list = append(list, Target{OnlineService: s, Online: true})
+	}
+	return list
+}
+
+// Init 按配置通过 UPnP 映射独立监听端口，独立监听本身由 routers 启动
+func Init() {
+	c := global.Config.Redirect
+	if c.Listen == "" || c.UPnPPort == 0 {
+		return
+	}
+	_, portStr, _ := net.SplitHostPort(c.Listen)
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 {
+		logrus.Errorf("跳转服务监听地址 %s 端口不合法", c.Listen)
+		return
+	}
+
+	mapPort := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := stun.AddPortMappingQueue(ctx, c.UPnPPort, uint16(port), "TCP", "LinkStar Redirect"); err != nil {
+			logrus.Warnf("跳转服务 UPnP 映射 %d -> %d 失败: %v", c.UPnPPort, port, err)
+			return
+		}
+		logrus.Infof("跳转服务已通过 UPnP 映射 %d -> %d", c.UPnPPort, port)
+	}
+	go mapPort()
+	// 网关变化后重新映射
+	event.Handle(4, func(event.Envelope) { mapPort() }, event.KindGatewayChanged)
+}
@@ -0,0 +1,299 @@
+package srv
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/modules/ddns"
+	"linkstar/modules/stun"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const srvConfigPath = "config/srv.json"
+
+var (
+	ErrRecordNotFound = errors.New("服务未配置 SRV 记录")
+	ErrInvalidConfig  = errors.New("配置不合法")
+	ErrSaveConfig     = errors.New("保存 SRV 配置失败")
+)
+
+// invalidFieldError 某个字段不合法，errors.Is(err, ErrInvalidConfig) 为 true
+type invalidFieldError struct {
+	field string
+	msg   string
+}
+
+func (e *invalidFieldError) Error() string { return e.msg }
+func (e *invalidFieldError) Unwrap() error { return ErrInvalidConfig }
+
+func invalidField(field, format string, args ...any) error {
+	return &invalidFieldError{field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// InvalidField 配置不合法时对应的请求字段，其他错误返回空字符串
+func InvalidField(err error) string {
+	var fe *invalidFieldError
+	if errors.As(err, &fe) {
+		return fe.field
+	}
+	return ""
+}
+
+// Config SRV 配置文件（config/srv.json）
+type Config struct {
+	Records   []*Record    `json:"records"`   // 每个服务至多一条
+	Published []*Published `json:"published"` // 已写入 DNS 的记录
+}
+
+// Published 已写入 DNS 的记录，持久化以便配置修改、删除或重启后清理旧记录
+type Published struct {
+	DeviceID   uint   `json:"deviceId"`   // 设备ID
+	ServiceID  uint   `json:"serviceId"`  // 服务ID
+	ProviderID uint   `json:"providerId"` // 发布时使用的 DNS 服务商
+	Zone       string `json:"zone"`       // 主域名
+	Name       string `json:"name"`       // 记录名，如 _minecraft._tcp.mc.example.com
+	TXT        bool   `json:"txt"`        // 是否同时发布了 TXT 记录
+}
+
+// Record 服务的 SRV 记录配置
+type Record struct {
+	DeviceID  uint `json:"deviceId"`  // 设备ID
+	ServiceID uint `json:"serviceId"` // 服务ID
+	RecordSpec
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RecordSpec SRV 记录中由用户配置的部分
+// 发布的记录名为 _<Service>._<Proto>.<Name>，如 _minecraft._tcp.mc.example.com
+type RecordSpec struct {
+	ProviderID uint   `json:"providerId" binding:"required"`                   // 使用的 DNS 服务商（DDNS 中配置）
+	Zone       string `json:"zone" binding:"required,fqdn"`                    // 主域名，如 example.com
+	Name       string `json:"name" binding:"required,fqdn"`                    // 记录所属域名，如 mc.example.com
+	Service    string `json:"service" binding:"required"`                      // 服务名，如 minecraft、sip、xmpp-client
+	Proto      string `json:"proto" binding:"omitempty,oneof=tcp udp TCP UDP"` // 传输协议，为空跟随服务协议
+	Target     string `json:"target" binding:"omitempty,fqdn"`                 // 目标主机名，需解析到公网IP（通常为 DDNS 域名），为空使用 Name
+	Priority   uint16 `json:"priority"`                                        // 优先级
+	Weight     uint16 `json:"weight"`                                          // 权重
+	TTL        int    `json:"ttl" binding:"min=0,max=86400"`                   // TTL（秒），0 使用服务商默认值
+	TXT        bool   `json:"txt"`                                             // 同时在同名 TXT 记录中发布完整访问地址
+	Enabled    bool   `json:"enabled"`                                         // 是否启用，停用后删除已发布的记录
+}
+
+// 记录名，如 _minecraft._tcp.mc.example.com
+func (r *Record) fqdn() string {
+	return "_" + r.Service + "._" + r.Proto + "." + r.Name
+}
+
+func (r *Record) target() string {
+	if r.Target != "" {
+		return r.Target
+	}
+	return r.Name
+}
+
+var serviceLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+var (
+	configMu sync.Mutex
+	config   Config
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(srvConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](srvConfigPath); err != nil {
+			return c, err
+		}
+	}
+	if c.Records == nil {
+		c.Records = []*Record{}
+	}
+	if c.Published == nil {
+		c.Published = []*Published{}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(srvConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(srvConfigPath, config); err != nil {
+		logrus.Error("SRV 配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 规范化并检查配置，Proto 为空时取服务的协议
+func checkRecord(spec *RecordSpec, protocol string) error {
+	spec.Zone = strings.ToLower(strings.TrimSuffix(spec.Zone, "."))
+	spec.Name = strings.ToLower(strings.TrimSuffix(spec.Name, "."))
+	spec.Target = strings.ToLower(strings.TrimSuffix(spec.Target, "."))
+	spec.Service = strings.ToLower(strings.TrimPrefix(spec.Service, "_"))
+	spec.Proto = strings.ToLower(strings.TrimPrefix(spec.Proto, "_"))
+	if spec.Proto == "" {
+		spec.Proto = strings.ToLower(protocol)
+	}
+	if spec.Proto == "" {
+		spec.Proto = "tcp"
+	}
+
+	if !serviceLabel.MatchString(spec.Service) {
+		return invalidField("service", "服务名只能包含小写字母、数字和 -，如 minecraft")
+	}
+	if spec.Name != spec.Zone && !strings.HasSuffix(spec.Name, "."+spec.Zone) {
+		return invalidField("name", "域名 %s 不属于主域名 %s", spec.Name, spec.Zone)
+	}
+	return nil
+}
+
+func findRecord(deviceID, serviceID uint) (int, *Record) {
+	for i, r := range config.Records {
+		if r.DeviceID == deviceID && r.ServiceID == serviceID {
+			return i, r
+		}
+	}
+	return -1, nil
+}
+
+func findPublished(deviceID, serviceID uint) (int, *Published) {
+	for i, p := range config.Published {
+		if p.DeviceID == deviceID && p.ServiceID == serviceID {
+			return i, p
+		}
+	}
+	return -1, nil
+}
+
+// 更新服务已发布的记录并持久化，pub 为 nil 表示已全部删除；调用方需持有 configMu
+func setPublished(deviceID, serviceID uint, pub *Published) error {
+	index, old := findPublished(deviceID, serviceID)
+	switch {
+	case pub == nil && old == nil:
+		return nil
+	case pub == nil:
+		list := config.Published
+		config.Published = append(list[:index:index], list[index+1:]...)
+	case old == nil:
+		config.Published = append(config.Published, pub)
+	case *old == *pub:
+		return nil
+	default:
+		*old = *pub
+	}
+	return saveConfig()
+}
+
+// 服务商是否被 SRV 记录使用（含尚未删除的已发布记录），注册到 ddns.ProviderInUse
+func providerInUse(id uint) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, r := range config.Records {
+		if r.ProviderID == id {
+			return true
+		}
+	}
+	for _, p := range config.Published {
+		if p.ProviderID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRecords 全部 SRV 记录及其发布状态
+func ListRecords() []RecordInfo {
+	configMu.Lock()
+	records := make([]Record, 0, len(config.Records))
+	for _, r := range config.Records {
+		records = append(records, *r)
+	}
+	configMu.Unlock()
+
+	list := make([]RecordInfo, 0, len(records))
+	for _, r := range records {
+		list = append(list, RecordInfo{Record: r, Status: recordStatus(r.DeviceID, r.ServiceID)})
+	}
+	return list
+}
+
+// GetRecord 查询服务的 SRV 记录
+func GetRecord(deviceID, serviceID uint) (RecordInfo, error) {
+	configMu.Lock()
+	_, r := findRecord(deviceID, serviceID)
+	var record Record
+	if r != nil {
+		record = *r
+	}
+	configMu.Unlock()
+
+	if r == nil {
+		return RecordInfo{}, ErrRecordNotFound
+	}
+	return RecordInfo{Record: record, Status: recordStatus(deviceID, serviceID)}, nil
+}
+
+// SetRecord 新增或替换服务的 SRV 记录，并在后台重新发布
+func SetRecord(deviceID, serviceID uint, spec RecordSpec) (RecordInfo, error) {
+	_, svc, err := stun.GetService(deviceID, serviceID)
+	if err != nil {
+		return RecordInfo{}, err
+	}
+	if err := checkRecord(&spec, svc.Protocol); err != nil {
+		return RecordInfo{}, err
+	}
+	if _, err := ddns.GetProvider(spec.ProviderID); err != nil {
+		return RecordInfo{}, err
+	}
+
+	configMu.Lock()
+	_, r := findRecord(deviceID, serviceID)
+	if r == nil {
+		r = &Record{DeviceID: deviceID, ServiceID: serviceID, CreatedAt: time.Now()}
+		config.Records = append(config.Records, r)
+	}
+	r.RecordSpec = spec
+	r.UpdatedAt = time.Now()
+	err = saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return RecordInfo{}, err
+	}
+
+	go syncRecord(deviceID, serviceID, true)
+	return GetRecord(deviceID, serviceID)
+}
+
+// DeleteRecord 删除服务的 SRV 配置，并在后台删除已发布的记录
+func DeleteRecord(deviceID, serviceID uint) error {
+	configMu.Lock()
+	index, r := findRecord(deviceID, serviceID)
+	if r == nil {
+		configMu.Unlock()
+		return ErrRecordNotFound
+	}
+	records := config.Records
+	config.Records = append(records[:index:index], records[index+1:]...)
+	err := saveConfig()
+	configMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	go syncRecord(deviceID, serviceID, false)
+	return nil
+}
@@ -1 +1,63 @@
 package srv
+
+import (
+	"linkstar/modules/ddns"
+	"linkstar/modules/event"
+	"linkstar/modules/stun"
+	"net"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Init 读取 SRV 配置，按服务当前端点发布一次并订阅服务上下线
+// 需在 ddns.Init 之后调用
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取 SRV 配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+
+	ddns.ProviderInUse = append(ddns.ProviderInUse, providerInUse)
+
+	event.Handle(64, func(env event.Envelope) {
+		switch e := env.Data.(type) {
+		case event.ServiceOnline:
+			_, portStr, err := net.SplitHostPort(e.PublicAddr)
+			if err != nil {
+				return
+			}
+			port, _ := strconv.Atoi(portStr)
+			if setEndpoint(e.DeviceID, e.ServiceID, endpoint{port: uint16(port), tls: e.TLS}) {
+				syncRecord(e.DeviceID, e.ServiceID, false)
+			}
+		case event.ServiceOffline:
+			removeEndpoint(e.DeviceID, e.ServiceID)
+		}
+	}, event.KindServiceOnline, event.KindServiceOffline)
+
+	// 订阅前已上线的服务
+	for _, device := range stun.ListDevices() {
+		for _, service := range device.Services {
+			if service.PunchSuccess && service.ExternalPort != 0 && stun.IsServiceRunning(device.DeviceID, service.ID) {
+				setEndpoint(device.DeviceID, service.ID, endpoint{port: service.ExternalPort, tls: service.TLS})
+			}
+		}
+	}
+	// 已删除配置但上次未能清理的记录也需同步
+	configMu.Lock()
+	keys := make(map[[2]uint]bool)
+	for _, r := range config.Records {
+		keys[[2]uint{r.DeviceID, r.ServiceID}] = true
+	}
+	for _, p := range config.Published {
+		keys[[2]uint{p.DeviceID, p.ServiceID}] = true
+	}
+	configMu.Unlock()
+	for k := range keys {
+		go syncRecord(k[0], k[1], false)
+	}
+}
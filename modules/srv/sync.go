@@ -0,0 +1,236 @@
+package srv
+
+import (
+	"context"
+	"fmt"
+	"linkstar/modules/ddns"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const retryInterval = time.Minute
+
+// RecordStatus 发布状态（仅保存在内存中）
+type RecordStatus struct {
+	Online     bool      `json:"online"`     // 服务是否在线（已获得公网端口）
+	Published  bool      `json:"published"`  // DNS 上是否有本程序发布的记录
+	Name       string    `json:"name"`       // 已发布的记录名
+	Target     string    `json:"target"`     // 已发布的目标主机
+	Port       uint16    `json:"port"`       // 已发布的端口
+	URL        string    `json:"url"`        // 已发布的 TXT 内容，未开启 TXT 为空
+	LastSyncAt time.Time `json:"lastSyncAt"` // 最近一次成功发布的时间
+	LastError  string    `json:"lastError"`  // 最近一次失败原因，成功后清空
+}
+
+// RecordInfo SRV 记录及其发布状态
+type RecordInfo struct {
+	Record
+	Status RecordStatus `json:"status"` // 发布状态
+}
+
+// endpoint 服务当前的公网端点
+type endpoint struct {
+	port uint16
+	tls  bool
+}
+
+type recordState struct {
+	status RecordStatus
+	retry  *time.Timer
+}
+
+var (
+	stateMu   sync.Mutex // 保护 endpoints 和 states
+	endpoints = make(map[string]endpoint)
+	states    = make(map[string]*recordState)
+
+	syncMu sync.Mutex // 串行化对 DNS 服务商的写入
+)
+
+func serviceKey(deviceID, serviceID uint) string {
+	return fmt.Sprintf("%d-%d", deviceID, serviceID)
+}
+
+func recordStatus(deviceID, serviceID uint) RecordStatus {
+	key := serviceKey(deviceID, serviceID)
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	var status RecordStatus
+	if st, ok := states[key]; ok {
+		status = st.status
+	}
+	_, status.Online = endpoints[key]
+	return status
+}
+
+// 更新服务的公网端点，端口未变化时返回 false
+func setEndpoint(deviceID, serviceID uint, ep endpoint) bool {
+	key := serviceKey(deviceID, serviceID)
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if old, ok := endpoints[key]; ok && old == ep {
+		return false
+	}
+	endpoints[key] = ep
+	return true
+}
+
+func removeEndpoint(deviceID, serviceID uint) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	delete(endpoints, serviceKey(deviceID, serviceID))
+}
+
+func publishURL(ep endpoint, host string) string {
+	scheme := "http"
+	if ep.tls {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, host, ep.port)
+}
+
+// syncRecord 使 DNS 上的记录与配置和服务当前端点一致
+// 配置被删除或停用时删除已发布的记录；服务离线时保留记录，等待下次上线更新端口
+// force 为 false 时跳过已是最新的记录；失败后按 retryInterval 重试
+func syncRecord(deviceID, serviceID uint, force bool) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+
+	key := serviceKey(deviceID, serviceID)
+	configMu.Lock()
+	var want *Record
+	if _, r := findRecord(deviceID, serviceID); r != nil && r.Enabled {
+		copied := *r
+		want = &copied
+	}
+	var pub *Published
+	if _, p := findPublished(deviceID, serviceID); p != nil {
+		copied := *p
+		pub = &copied
+	}
+	configMu.Unlock()
+
+	stateMu.Lock()
+	st, ok := states[key]
+	if !ok {
+		st = &recordState{}
+		states[key] = st
+	}
+	if st.retry != nil {
+		st.retry.Stop()
+		st.retry = nil
+	}
+	ep, online := endpoints[key]
+	status := st.status
+	stateMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := func() error {
+		// 记录名或服务商变化后先删除旧记录
+		if pub != nil && (want == nil || pub.ProviderID != want.ProviderID || pub.Zone != want.Zone || pub.Name != want.fqdn()) {
+			if err := unpublish(ctx, pub); err != nil {
+				return err
+			}
+			pub = nil
+			status = RecordStatus{}
+		}
+		if want == nil || !online {
+			return nil
+		}
+
+		target := want.target()
+		url := ""
+		if want.TXT {
+			url = publishURL(ep, target)
+		}
+		if !force && pub != nil && status.Port == ep.port && status.Target == target && status.URL == url {
+			return nil
+		}
+
+		provider, err := ddns.ProviderByID(want.ProviderID)
+		if err != nil {
+			return err
+		}
+		rec := ddns.Record{
+			Zone: want.Zone, Name: want.fqdn(), Type: "SRV", Value: target, TTL: want.TTL,
+			Priority: want.Priority, Weight: want.Weight, Port: ep.port,
+		}
+		if err := provider.Upsert(ctx, rec); err != nil {
+			return err
+		}
+		hadTXT := pub != nil && pub.TXT
+		pub = &Published{DeviceID: deviceID, ServiceID: serviceID, ProviderID: want.ProviderID, Zone: want.Zone, Name: want.fqdn(), TXT: hadTXT}
+		status = RecordStatus{Published: true, Name: rec.Name, Target: target, Port: ep.port}
+
+		txt := ddns.Record{Zone: want.Zone, Name: want.fqdn(), Type: "TXT", Value: url, TTL: want.TTL}
+		if want.TXT {
+			if err := provider.Upsert(ctx, txt); err != nil {
+				return err
+			}
+		} else if hadTXT {
+			if err := provider.Delete(ctx, txt); err != nil {
+				return err
+			}
+		}
+		pub.TXT = want.TXT
+		status.URL = url
+		status.LastSyncAt = time.Now()
+		logrus.Infof("[SRV %s] 已发布 %s:%d", rec.Name, target, ep.port)
+		return nil
+	}()
+
+	// 已发布的记录写入配置文件，重启后仍能清理
+	configMu.Lock()
+	if saveErr := setPublished(deviceID, serviceID, pub); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	configMu.Unlock()
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if want == nil && pub == nil && err == nil {
+		delete(states, key)
+		return
+	}
+	st.status = status
+	if err != nil {
+		st.status.LastError = err.Error()
+		st.retry = time.AfterFunc(retryInterval, func() { syncRecord(deviceID, serviceID, false) })
+		logrus.Warnf("[SRV %s] 同步失败: %v", key, err)
+	}
+}
+
+// 删除已发布的 SRV 与 TXT 记录
+func unpublish(ctx context.Context, pub *Published) error {
+	provider, err := ddns.ProviderByID(pub.ProviderID)
+	if err != nil {
+		return err
+	}
+	rec := ddns.Record{Zone: pub.Zone, Name: pub.Name, Type: "SRV"}
+	if err := provider.Delete(ctx, rec); err != nil {
+		return err
+	}
+	if pub.TXT {
+		rec.Type = "TXT"
+		if err := provider.Delete(ctx, rec); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("[SRV %s] 已删除", pub.Name)
+	return nil
+}
+
+// SyncRecord 立即重新发布服务的 SRV 记录，返回发布后的状态
+func SyncRecord(deviceID, serviceID uint) (RecordInfo, error) {
+	if _, err := GetRecord(deviceID, serviceID); err != nil {
+		return RecordInfo{}, err
+	}
+	syncRecord(deviceID, serviceID, true)
+	return GetRecord(deviceID, serviceID)
+}
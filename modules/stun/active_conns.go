@@ -0,0 +1,265 @@
+package stun
+
+import (
+	"crypto/tls"
+	"errors"
+	"linkstar/modules/accesslog"
+	"linkstar/modules/stun/model"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrConnNotFound = errors.New("连接不存在或已关闭")
+
+// ActiveConn 一个正在转发的外部连接
+type ActiveConn struct {
+	ID          uint64    `json:"id"`          // 连接ID，进程内唯一
+	DeviceID    uint      `json:"deviceId"`    // 设备ID
+	DeviceName  string    `json:"deviceName"`  // 设备名称
+	ServiceID   uint      `json:"serviceId"`   // 服务ID
+	ServiceName string    `json:"serviceName"` // 服务名称
+	RemoteAddr  string    `json:"remoteAddr"`  // 客户端地址（经 PROXY protocol 还原后的真实地址）
+	Target      string    `json:"target"`      // 内网目标，http-proxy 为最近一次请求的目标，尚未选出时为空
+	StartedAt   time.Time `json:"startedAt"`   // 建立时间
+	BytesIn     uint64    `json:"bytesIn"`     // 从客户端收到的字节
+	BytesOut    uint64    `json:"bytesOut"`    // 发往客户端的字节
+}
+
+// activeConn 登记在册的连接，关闭时自动注销并写入访问日志；Close 可由其他 goroutine 调用以强制断开
+type activeConn struct {
+	net.Conn
+	info              ActiveConn // 不变的部分
+	target            atomic.Value
+	bytesIn, bytesOut atomic.Uint64
+	once              sync.Once
+
+	closing      atomic.Bool            // 已由本端关闭，之后的读错误不算客户端断开
+	clientClosed atomic.Bool            // 读到客户端断开
+	reason       atomic.Pointer[string] // 明确的关闭原因，先设置的生效
+	closedAt     time.Time              // 在 once 内写入
+	holdRecord   bool                   // 为 true 时由 handle 在转发结束后调用 finish 写访问日志
+	recordOnce   sync.Once
+}
+
+var (
+	activeMu    sync.Mutex
+	activeConns = map[uint64]*activeConn{}
+	nextConnID  atomic.Uint64
+)
+
+// 登记一个外部连接，holdRecord 需在登记前确定，登记后连接随时可能被断开
+func trackConn(conn net.Conn, device *model.Device, service *model.Service, holdRecord bool) *activeConn {
+	c := &activeConn{Conn: conn, holdRecord: holdRecord, info: ActiveConn{
+		ID:          nextConnID.Add(1),
+		DeviceID:    device.DeviceID,
+		DeviceName:  device.Name,
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		RemoteAddr:  conn.RemoteAddr().String(),
+		StartedAt:   time.Now(),
+	}}
+	activeMu.Lock()
+	activeConns[c.info.ID] = c
+	activeMu.Unlock()
+	return c
+}
+
+func (c *activeConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.bytesIn.Add(uint64(n))
+	if err != nil && !c.closing.Load() {
+		// 超时是本端设置的读期限（如协议识别），不算客户端断开
+		var ne net.Error
+		if !errors.As(err, &ne) || !ne.Timeout() {
+			c.clientClosed.Store(true)
+		}
+	}
+	return n, err
+}
+
+func (c *activeConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.bytesOut.Add(uint64(n))
+	return n, err
+}
+
+func (c *activeConn) Close() error {
+	c.closing.Store(true)
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		c.closedAt = time.Now()
+		activeMu.Lock()
+		delete(activeConns, c.info.ID)
+		activeMu.Unlock()
+		if !c.holdRecord {
+			c.writeRecord()
+		}
+	})
+	return err
+}
+
+// 设置关闭原因，已有原因时忽略
+func (c *activeConn) setCloseReason(reason string) {
+	c.reason.CompareAndSwap(nil, &reason)
+}
+
+func (c *activeConn) closeReason() string {
+	switch {
+	case c.reason.Load() != nil:
+		return *c.reason.Load()
+	case c.clientClosed.Load():
+		return accesslog.ReasonClientClosed
+	case c.target.Load() == nil:
+		return accesslog.ReasonRejected
+	default:
+		return accesslog.ReasonServerClosed
+	}
+}
+
+// 关闭连接并写入访问日志，只写一次
+func (c *activeConn) finish() {
+	c.Close()
+	c.writeRecord()
+}
+
+// 写入访问日志，只写一次，需在 Close 之后调用（依赖 closedAt）
+func (c *activeConn) writeRecord() {
+	c.recordOnce.Do(func() {
+		info := c.snapshot()
+		accesslog.Write(accesslog.Record{
+			Time:        c.closedAt,
+			StartedAt:   info.StartedAt,
+			DeviceID:    info.DeviceID,
+			Device:      info.DeviceName,
+			ServiceID:   info.ServiceID,
+			Service:     info.ServiceName,
+			RemoteAddr:  info.RemoteAddr,
+			Target:      info.Target,
+			DurationMs:  c.closedAt.Sub(info.StartedAt).Milliseconds(),
+			BytesIn:     info.BytesIn,
+			BytesOut:    info.BytesOut,
+			CloseReason: c.closeReason(),
+		})
+	})
+}
+
+// 记录选出的内网目标
+func (c *activeConn) setTarget(target string) {
+	if target != "" {
+		c.target.Store(target)
+	}
+}
+
+func (c *activeConn) snapshot() ActiveConn {
+	info := c.info
+	info.Target, _ = c.target.Load().(string)
+	info.BytesIn = c.bytesIn.Load()
+	info.BytesOut = c.bytesOut.Load()
+	return info
+}
+
+// 从 http.Server 交给处理器的连接中找到登记的连接，TLS 终止时先取出底层连接
+func activeConnOf(conn net.Conn) *activeConn {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if pc, ok := conn.(*peekedConn); ok {
+		conn = pc.Conn
+	}
+	c, _ := conn.(*activeConn)
+	return c
+}
+
+// 客户端地址的 IP 部分
+func connIP(c *activeConn) string {
+	host, _, err := net.SplitHostPort(c.info.RemoteAddr)
+	if err != nil {
+		return c.info.RemoteAddr
+	}
+	return host
+}
+
+// 按条件选出连接，按建立时间排序
+func selectConns(match func(c *activeConn) bool) []*activeConn {
+	activeMu.Lock()
+	list := make([]*activeConn, 0, len(activeConns))
+	for _, c := range activeConns {
+		if match(c) {
+			list = append(list, c)
+		}
+	}
+	activeMu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].info.ID < list[j].info.ID })
+	return list
+}
+
+func snapshots(list []*activeConn) []ActiveConn {
+	result := make([]ActiveConn, 0, len(list))
+	for _, c := range list {
+		result = append(result, c.snapshot())
+	}
+	return result
+}
+
+func closeAll(list []*activeConn) int {
+	for _, c := range list {
+		c.setCloseReason(accesslog.ReasonKilled)
+		c.Close()
+	}
+	return len(list)
+}
+
+// ListAllConns 所有服务正在转发的连接
+func ListAllConns() []ActiveConn {
+	return snapshots(selectConns(func(*activeConn) bool { return true }))
+}
+
+// ListConns 服务正在转发的连接
+func ListConns(deviceID, serviceID uint) ([]ActiveConn, error) {
+	if err := serviceExists(deviceID, serviceID); err != nil {
+		return nil, err
+	}
+	return snapshots(selectConns(func(c *activeConn) bool {
+		return c.info.DeviceID == deviceID && c.info.ServiceID == serviceID
+	})), nil
+}
+
+// KillConn 断开服务的一个连接
+func KillConn(deviceID, serviceID uint, connID uint64) error {
+	if err := serviceExists(deviceID, serviceID); err != nil {
+		return err
+	}
+	list := selectConns(func(c *activeConn) bool {
+		return c.info.ID == connID && c.info.DeviceID == deviceID && c.info.ServiceID == serviceID
+	})
+	if len(list) == 0 {
+		return ErrConnNotFound
+	}
+	closeAll(list)
+	return nil
+}
+
+// KillServiceConns 断开服务的全部连接，返回断开的数量
+func KillServiceConns(deviceID, serviceID uint) (int, error) {
+	if err := serviceExists(deviceID, serviceID); err != nil {
+		return 0, err
+	}
+	return closeAll(selectConns(func(c *activeConn) bool {
+		return c.info.DeviceID == deviceID && c.info.ServiceID == serviceID
+	})), nil
+}
+
+// KillConnsFrom 断开来自某个地址的全部连接（所有服务），返回断开的数量
+func KillConnsFrom(ip string) int {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+	return closeAll(selectConns(func(c *activeConn) bool {
+		parsed := net.ParseIP(connIP(c))
+		return parsed != nil && parsed.String() == ip
+	}))
+}
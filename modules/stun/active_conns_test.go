@@ -0,0 +1,84 @@
+package stun
+
+import (
+	"linkstar/modules/accesslog"
+	"linkstar/modules/stun/model"
+	"net"
+	"testing"
+	"time"
+)
+
+// 带客户端地址的内存连接
+type addrConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c *addrConn) RemoteAddr() net.Addr { return c.remote }
+
+func pipeFrom(t *testing.T, ip string) net.Conn {
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	return &addrConn{Conn: server, remote: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}}
+}
+
+// 在限定时间内完成，否则视为死锁
+func within(t *testing.T, name string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s did not return", name)
+	}
+}
+
+func TestActiveConnClose(t *testing.T) {
+	t.Chdir(t.TempDir())
+	device := &model.Device{DeviceID: 1, Name: "NAS"}
+	service := &model.Service{ID: 2, ServiceSpec: model.ServiceSpec{Name: "web"}}
+
+	// http-proxy 的连接不等 handle，Close 时直接写访问日志
+	proxied := trackConn(pipeFrom(t, "203.0.113.1"), device, service, false)
+	proxied.setTarget("192.168.1.10:80")
+	within(t, "Close", func() { proxied.Close() })
+	within(t, "second Close", func() { proxied.Close() })
+
+	// 直接转发的连接 Close 后由 finish 写访问日志
+	held := trackConn(pipeFrom(t, "203.0.113.2"), device, service, true)
+	held.Close()
+	if got, _ := accesslog.Query(accesslog.Filter{IP: "203.0.113.2"}); len(got) != 0 {
+		t.Fatalf("held connection recorded before finish: %+v", got)
+	}
+	within(t, "finish", held.finish)
+
+	// 通过接口断开
+	killed := trackConn(pipeFrom(t, "203.0.113.3"), device, service, false)
+	within(t, "KillConnsFrom", func() {
+		if n := KillConnsFrom("203.0.113.3"); n != 1 {
+			t.Errorf("KillConnsFrom = %d, want 1", n)
+		}
+	})
+	if n := len(selectConns(func(c *activeConn) bool { return c == killed })); n != 0 {
+		t.Fatal("closed connection still listed")
+	}
+
+	want := map[string]string{
+		"203.0.113.1": accesslog.ReasonServerClosed,
+		"203.0.113.2": accesslog.ReasonRejected,
+		"203.0.113.3": accesslog.ReasonKilled,
+	}
+	for ip, reason := range want {
+		got, err := accesslog.Query(accesslog.Filter{IP: ip})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].CloseReason != reason || got[0].Service != "web" {
+			t.Fatalf("%s: records = %+v, want one with reason %s", ip, got, reason)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/global"
+	"linkstar/modules/certs"
+	"linkstar/modules/firewall"
+	"linkstar/modules/limiter"
+	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDeviceNotFound    = errors.New("设备不存在")
+	ErrServiceNotFound   = errors.New("服务不存在")
+	ErrServiceNameExists = errors.New("该设备下已存在同名服务")
+	ErrTargetConflict    = errors.New("内网目标已被其他服务使用")
+	ErrDeviceIPConflict  = errors.New("设备改用该 IP 后服务的内网目标与其他设备冲突")
+	ErrSaveConfig        = errors.New("保存配置失败")
+	ErrTLSRequiresTCP    = errors.New("只有 TCP 服务支持 TLS 终止")
+	ErrRedirectNeedsTLS  = errors.New("HTTP 跳转需要开启 TLS 终止")
+	ErrPortRequired      = errors.New("端口转发服务的内网端口不能为空")
+	ErrProxyRequiresTCP  = errors.New("http-proxy / sni-proxy / mux 服务只支持 TCP")
+	ErrPassthroughTLS    = errors.New("sni-proxy / mux 服务透传 TLS，不能同时开启 TLS 终止")
+	ErrMuxNoTarget       = errors.New("mux 服务至少需要配置一个协议端口或默认目标")
+	ErrProxyProtocolTCP  = errors.New("只有 TCP 服务支持发送 PROXY protocol")
+	ErrAcceptProxyTCP    = errors.New("只有 TCP 服务支持接收 PROXY protocol")
+	ErrProxyProtocolHTTP = errors.New("http-proxy 服务通过 X-Forwarded-For 传递客户端地址，不发送 PROXY protocol")
+)
+
+// ConflictField 冲突类错误对应的请求字段，非冲突错误返回空字符串
+func ConflictField(err error) string {
+	switch {
+	case errors.Is(err, ErrServiceNameExists):
+		return "name"
+	case errors.Is(err, ErrDeviceIPConflict):
+		return "ip"
+	case errors.Is(err, ErrTargetConflict):
+		return "internalPort"
+	case errors.Is(err, ErrRouteExists):
+		return "pathPrefix"
+	}
+	return ""
+}
+
+// InvalidField 服务配置不合法时对应的请求字段，其他错误返回空字符串
+func InvalidField(err error) string {
+	switch {
+	case errors.Is(err, ErrTLSRequiresTCP):
+		return "tlsTerminate"
+	case errors.Is(err, ErrRedirectNeedsTLS):
+		return "httpRedirect"
+	case errors.Is(err, ErrPortRequired):
+		return "internalPort"
+	case errors.Is(err, ErrProxyRequiresTCP):
+		return "type"
+	case errors.Is(err, ErrPassthroughTLS):
+		return "tlsTerminate"
+	case errors.Is(err, ErrMuxNoTarget):
+		return "mux"
+	case errors.Is(err, ErrProxyProtocolTCP), errors.Is(err, ErrProxyProtocolHTTP):
+		return "proxyProtocol"
+	case errors.Is(err, ErrAcceptProxyTCP):
+		return "acceptProxyProtocol"
+	case errors.Is(err, ErrRouteTarget), errors.Is(err, ErrSNIRouteTarget):
+		return "target"
+	case errors.Is(err, ErrSNIRoutePath):
+		return "pathPrefix"
+	case errors.Is(err, certs.ErrCertNotFound):
+		return "certId"
+	}
+	return ""
+}
+
+// configMu 保护设备、服务列表的增删改
+var configMu sync.Mutex
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveStunConfig() error {
+	if err := UpdateStunConfig(global.StunConfig); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+func findDevice(deviceID uint) (int, *model.Device) {
+	for i, device := range global.StunConfig.Devices {
+		if device.DeviceID == deviceID {
+			return i, device
+		}
+	}
+	return -1, nil
+}
+
+func findService(device *model.Device, serviceID uint) (int, *model.Service) {
+	for i, svc := range device.Services {
+		if svc.ID == serviceID {
+			return i, svc
+		}
+	}
+	return -1, nil
+}
+
+// 确认服务存在，不能在持有 configMu 时调用
+func serviceExists(deviceID, serviceID uint) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return ErrDeviceNotFound
+	}
+	if _, svc := findService(device, serviceID); svc == nil {
+		return ErrServiceNotFound
+	}
+	return nil
+}
+
+// 同一设备下服务名不能重复，excludeID 为更新时自身的ID
+func serviceNameTaken(device *model.Device, name string, excludeID uint) bool {
+	for _, svc := range device.Services {
+		if svc.ID != excludeID && svc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 服务的传输协议（大写），旧配置中为空的视为 TCP
+func protocolOf(spec model.ServiceSpec) string {
+	if spec.Protocol == "" {
+		return "TCP"
+	}
+	return strings.ToUpper(spec.Protocol)
+}
+
+// 两个服务转发到同一个内网目标（IP + 端口 + 协议）视为冲突，excludeKey 为更新时自身的 key
+// 未设置默认目标的 http-proxy / sni-proxy / mux 服务不占用内网端口
+func findTargetConflict(ip string, spec model.ServiceSpec, excludeKey string) error {
+	if spec.InternalPort == 0 {
+		return nil
+	}
+	for _, d := range global.StunConfig.Devices {
+		if d.IP != ip {
+			continue
+		}
+		for _, svc := range d.Services {
+			if serviceKey(d.DeviceID, svc.ID) == excludeKey {
+				continue
+			}
+			if svc.InternalPort == spec.InternalPort && protocolOf(svc.ServiceSpec) == protocolOf(spec) {
+				return fmt.Errorf("%w: %s:%d/%s 已被 [%s - %s] 使用",
+					ErrTargetConflict, ip, spec.InternalPort, protocolOf(spec), d.Name, svc.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// 规范化服务配置：协议统一大写，默认 TCP，类型默认 forward
+func normalizeSpec(spec *model.ServiceSpec) {
+	spec.Protocol = strings.ToUpper(spec.Protocol)
+	if spec.Protocol == "" {
+		spec.Protocol = "TCP"
+	}
+	if spec.Type == "" {
+		spec.Type = model.ServiceTypeForward
+	}
+	if spec.TLSTerminate || spec.Type == model.ServiceTypeSNIProxy {
+		spec.TLS = true
+	}
+}
+
+// 复制设备及其服务、路由，返回给调用方的副本不与运行中的服务共享
+func copyDevice(d *model.Device) *model.Device {
+	device := *d
+	device.Services = make([]*model.Service, 0, len(d.Services))
+	for _, svc := range d.Services {
+		s := *svc
+		s.Allow = slices.Clone(svc.Allow)
+		s.Deny = slices.Clone(svc.Deny)
+		s.Routes = make([]*model.ProxyRoute, 0, len(svc.Routes))
+		for _, route := range svc.Routes {
+			r := *route
+			s.Routes = append(s.Routes, &r)
+		}
+		device.Services = append(device.Services, &s)
+	}
+	return &device
+}
+
+// 在配置锁内解析服务的访问控制列表
+func serviceRules(svc *model.Service) *firewall.Rules {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return firewall.NewRules(svc.Allow, svc.Deny)
+}
+
+// ListDevices 所有设备的副本，在 configMu 下复制，可在锁外遍历
+func ListDevices() []*model.Device {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	devices := make([]*model.Device, 0, len(global.StunConfig.Devices))
+	for _, d := range global.StunConfig.Devices {
+		devices = append(devices, copyDevice(d))
+	}
+	return devices
+}
+
+// GetDevice 按ID查找设备，返回副本
+func GetDevice(deviceID uint) (*model.Device, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return nil, ErrDeviceNotFound
+	}
+	return copyDevice(device), nil
+}
+
+// GetService 按ID查找服务，返回设备与服务的副本
+func GetService(deviceID, serviceID uint) (*model.Device, *model.Service, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return nil, nil, ErrDeviceNotFound
+	}
+	index, svc := findService(device, serviceID)
+	device = copyDevice(device)
+	if svc == nil {
+		return device, nil, ErrServiceNotFound
+	}
+	return device, device.Services[index], nil
+}
+
+// 按ID查找服务，返回配置中的指针，供启动服务和修改启用状态使用
+func lookupService(deviceID, serviceID uint) (*model.Device, *model.Service, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return nil, nil, ErrDeviceNotFound
+	}
+	_, svc := findService(device, serviceID)
+	if svc == nil {
+		return nil, nil, ErrServiceNotFound
+	}
+	return device, svc, nil
+}
+
+// AddDevice 新增设备并持久化
+func AddDevice(name, ip string) (*model.Device, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	// 生成新设备ID（取当前最大ID+1）
+	var maxID uint = 0
+	for _, d := range global.StunConfig.Devices {
+		if d.DeviceID > maxID {
+			maxID = d.DeviceID
+		}
+	}
+
+	device := &model.Device{
+		DeviceID:  maxID + 1,
+		Name:      name,
+		IP:        ip,
+		Services:  []*model.Service{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	global.StunConfig.Devices = append(global.StunConfig.Devices, device)
+
+	if err := saveStunConfig(); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UpdateDevice 修改设备，IP 变化时重启该设备下所有服务
+func UpdateDevice(deviceID uint, name, ip string) (*model.Device, error) {
+	configMu.Lock()
+	_, device := findDevice(deviceID)
+	if device == nil {
+		configMu.Unlock()
+		return nil, ErrDeviceNotFound
+	}
+
+	oldIP := device.IP
+	// 设备下的服务随设备一起换到新 IP，逐个检查是否与新 IP 上已有的服务冲突
+	if oldIP != ip {
+		for _, svc := range device.Services {
+			if err := findTargetConflict(ip, svc.ServiceSpec, serviceKey(deviceID, svc.ID)); err != nil {
+				configMu.Unlock()
+				return nil, fmt.Errorf("%w: %w", ErrDeviceIPConflict, err)
+			}
+		}
+	}
+	device.Name = name
+	device.IP = ip
+	device.UpdatedAt = time.Now()
+	err := saveStunConfig()
+	configMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// 若 IP 发生变化，重启该设备下所有已启用服务
+	if oldIP != ip {
+		for _, svc := range device.Services {
+			StartService(device, svc)
+		}
+	}
+	return device, nil
+}
+
+// DeleteDevice 停止设备下所有服务并删除设备
+func DeleteDevice(deviceID uint) error {
+	configMu.Lock()
+	index, device := findDevice(deviceID)
+	if device == nil {
+		configMu.Unlock()
+		return ErrDeviceNotFound
+	}
+	devices := global.StunConfig.Devices
+	global.StunConfig.Devices = append(devices[:index:index], devices[index+1:]...)
+	err := saveStunConfig()
+	configMu.Unlock()
+
+	// 停止该设备下所有服务的 STUN 穿透
+	for _, svc := range device.Services {
+		StopService(deviceID, svc.ID)
+	}
+	return err
+}
+
+// 检查 TLS 终止、服务类型与 PROXY protocol 配置
+func checkTLS(spec model.ServiceSpec) error {
+	switch spec.Type {
+	case model.ServiceTypeForward:
+		if spec.InternalPort == 0 {
+			return ErrPortRequired
+		}
+	case model.ServiceTypeHTTPProxy, model.ServiceTypeSNIProxy, model.ServiceTypeMux:
+		if spec.Protocol != "TCP" {
+			return ErrProxyRequiresTCP
+		}
+	}
+	if (spec.Type == model.ServiceTypeSNIProxy || spec.Type == model.ServiceTypeMux) && spec.TLSTerminate {
+		return ErrPassthroughTLS
+	}
+	if spec.ProxyProtocol != "" && spec.Protocol != "TCP" {
+		return ErrProxyProtocolTCP
+	}
+	if spec.ProxyProtocol != "" && spec.Type == model.ServiceTypeHTTPProxy {
+		return ErrProxyProtocolHTTP
+	}
+	if spec.AcceptProxyProtocol && spec.Protocol != "TCP" {
+		return ErrAcceptProxyTCP
+	}
+	if spec.Type == model.ServiceTypeMux {
+		m := spec.Mux
+		if spec.InternalPort == 0 && m.SSH == 0 && m.HTTP == 0 && m.TLS == 0 && m.RDP == 0 && m.OpenVPN == 0 {
+			return ErrMuxNoTarget
+		}
+	}
+	if spec.HTTPRedirect && !spec.TLSTerminate {
+		return ErrRedirectNeedsTLS
+	}
+	if !spec.TLSTerminate {
+		return nil
+	}
+	if spec.Protocol != "TCP" {
+		return ErrTLSRequiresTCP
+	}
+	if spec.CertID != 0 && !certs.Exists(spec.CertID) {
+		return fmt.Errorf("%w: %d", certs.ErrCertNotFound, spec.CertID)
+	}
+	return nil
+}
+
+// 服务是否使用了证书，注册到 certs.InUse
+func certInUse(id uint) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, d := range global.StunConfig.Devices {
+		for _, svc := range d.Services {
+			if svc.TLSTerminate && svc.CertID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddService 在设备下新增服务，持久化后启动
+func AddService(deviceID uint, spec model.ServiceSpec) (*model.Service, error) {
+	normalizeSpec(&spec)
+	if err := checkTLS(spec); err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	_, device := findDevice(deviceID)
+	if device == nil {
+		configMu.Unlock()
+		return nil, ErrDeviceNotFound
+	}
+	if serviceNameTaken(device, spec.Name, 0) {
+		configMu.Unlock()
+		return nil, ErrServiceNameExists
+	}
+	if err := findTargetConflict(device.IP, spec, ""); err != nil {
+		configMu.Unlock()
+		return nil, err
+	}
+
+	// 生成新服务ID（取当前最大ID+1）
+	var maxID uint = 0
+	for _, svc := range device.Services {
+		if svc.ID > maxID {
+			maxID = svc.ID
+		}
+	}
+
+	svc := &model.Service{
+		ID:          maxID + 1,
+		ServiceSpec: spec,
+		UpdatedAt:   time.Now(),
+	}
+	device.Services = append(device.Services, svc)
+	err := saveStunConfig()
+	configMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// 启动该服务的 STUN 穿透
+	StartService(device, svc)
+	return svc, nil
+}
+
+// UpdateService 修改服务配置，持久化后重启（停旧起新）
+func UpdateService(deviceID, serviceID uint, spec model.ServiceSpec) (*model.Service, error) {
+	normalizeSpec(&spec)
+	if err := checkTLS(spec); err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	_, device := findDevice(deviceID)
+	if device == nil {
+		configMu.Unlock()
+		return nil, ErrDeviceNotFound
+	}
+	_, svc := findService(device, serviceID)
+	if svc == nil {
+		configMu.Unlock()
+		return nil, ErrServiceNotFound
+	}
+	if serviceNameTaken(device, spec.Name, serviceID) {
+		configMu.Unlock()
+		return nil, ErrServiceNameExists
+	}
+	if err := findTargetConflict(device.IP, spec, serviceKey(deviceID, serviceID)); err != nil {
+		configMu.Unlock()
+		return nil, err
+	}
+
+	// 路由目标的格式随类型不同，类型变化时清空路由
+	oldType := svc.Type
+	if oldType == "" {
+		oldType = model.ServiceTypeForward
+	}
+	if oldType != spec.Type {
+		svc.Routes = nil
+	}
+	svc.ServiceSpec = spec
+	svc.UpdatedAt = time.Now()
+	err := saveStunConfig()
+	configMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	StartService(device, svc)
+	return svc, nil
+}
+
+// DeleteService 删除服务并停止其 STUN 穿透
+func DeleteService(deviceID, serviceID uint) error {
+	configMu.Lock()
+	_, device := findDevice(deviceID)
+	if device == nil {
+		configMu.Unlock()
+		return ErrDeviceNotFound
+	}
+	index, svc := findService(device, serviceID)
+	if svc == nil {
+		configMu.Unlock()
+		return ErrServiceNotFound
+	}
+	services := device.Services
+	device.Services = append(services[:index:index], services[index+1:]...)
+	err := saveStunConfig()
+	configMu.Unlock()
+
+	StopService(deviceID, serviceID)
+	limiter.Remove(serviceKey(deviceID, serviceID))
+	traffic.Remove(serviceKey(deviceID, serviceID))
+	return err
+}
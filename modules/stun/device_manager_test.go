@@ -0,0 +1,68 @@
+package stun
+
+import (
+	"errors"
+	"linkstar/global"
+	"linkstar/modules/stun/model"
+	"testing"
+)
+
+func useDevices(t *testing.T, devices ...*model.Device) {
+	configMu.Lock()
+	old := global.StunConfig.Devices
+	global.StunConfig.Devices = devices
+	configMu.Unlock()
+	t.Cleanup(func() {
+		configMu.Lock()
+		global.StunConfig.Devices = old
+		configMu.Unlock()
+	})
+}
+
+func TestGettersReturnCopies(t *testing.T) {
+	svc := &model.Service{ID: 2, ServiceSpec: model.ServiceSpec{Name: "ssh", Allow: []string{"10.0.0.0/8"}}}
+	useDevices(t, &model.Device{DeviceID: 1, Name: "NAS", Services: []*model.Service{svc}})
+
+	device, err := GetDevice(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	device.Name = "changed"
+	device.Services[0].Allow[0] = "0.0.0.0/0"
+
+	d, s, err := GetService(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "NAS" || s.Allow[0] != "10.0.0.0/8" || s == svc || d.Services[0] != s {
+		t.Fatalf("GetService = %+v, %+v; want an unshared copy of the stored config", d, s)
+	}
+	s.Name = "changed"
+	if svc.Name != "ssh" {
+		t.Fatal("GetService returned the stored service")
+	}
+
+	if _, _, err := GetService(1, 3); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("err = %v, want ErrServiceNotFound", err)
+	}
+}
+
+func TestTargetConflictWithLegacyProtocol(t *testing.T) {
+	// 旧配置中的服务没有保存协议，按 TCP 处理
+	legacy := &model.Service{ID: 2, ServiceSpec: model.ServiceSpec{Name: "ssh", InternalPort: 22}}
+	useDevices(t, &model.Device{DeviceID: 1, Name: "NAS", IP: "192.168.1.10", Services: []*model.Service{legacy}})
+
+	spec := model.ServiceSpec{Name: "ssh2", InternalPort: 22}
+	normalizeSpec(&spec)
+	if err := findTargetConflict("192.168.1.10", spec, ""); !errors.Is(err, ErrTargetConflict) {
+		t.Fatalf("TCP: err = %v, want ErrTargetConflict", err)
+	}
+	spec.Protocol = "UDP"
+	if err := findTargetConflict("192.168.1.10", spec, ""); err != nil {
+		t.Fatalf("UDP: err = %v, want no conflict", err)
+	}
+	// 更新自身不算冲突
+	if err := findTargetConflict("192.168.1.10", legacy.ServiceSpec, serviceKey(1, 2)); err != nil {
+		t.Fatalf("self: err = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package stun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 服务 goroutine 的 pprof 标签名，StartService 中打上，子 goroutine 自动继承
+const serviceLabel = "service"
+
+// RunningService 正在运行的服务
+type RunningService struct {
+	Key       string    `json:"key"`       // "deviceID-serviceID"
+	StartedAt time.Time `json:"startedAt"` // 本次启动时间
+}
+
+// GoroutineSummary goroutine 统计
+type GoroutineSummary struct {
+	Total     int            `json:"total"`     // 进程 goroutine 总数
+	Unlabeled int            `json:"unlabeled"` // 不属于任何服务的 goroutine
+	Services  map[string]int `json:"services"`  // 每个服务 key 下的 goroutine 数
+}
+
+// GetRunningServices 获取 runningServices 中的服务
+func GetRunningServices() []RunningService {
+	servicesMu.Lock()
+	list := make([]RunningService, 0, len(runningServices))
+	for key, entry := range runningServices {
+		list = append(list, RunningService{Key: key, StartedAt: entry.startedAt})
+	}
+	servicesMu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// GetGoroutineSummary 按服务 key 统计 goroutine 数量
+func GetGoroutineSummary() GoroutineSummary {
+	summary := GoroutineSummary{
+		Total:    runtime.NumGoroutine(),
+		Services: map[string]int{},
+	}
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return summary
+	}
+
+	// debug=1 格式：
+	// 3 @ 0x... 0x...
+	// # labels: {"service":"1-2"}
+	// #	0x...	func+0x..	file:line
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	count := 0
+	labeled := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " @ "); i > 0 {
+			if n, err := strconv.Atoi(line[:i]); err == nil {
+				count = n
+				continue
+			}
+		}
+		if count > 0 && strings.HasPrefix(line, "# labels: ") {
+			labels := map[string]string{}
+			if json.Unmarshal([]byte(strings.TrimPrefix(line, "# labels: ")), &labels) == nil {
+				if key, ok := labels[serviceLabel]; ok {
+					summary.Services[key] += count
+					labeled += count
+				}
+			}
+			count = 0
+		}
+	}
+	summary.Unlabeled = summary.Total - labeled
+	return summary
+}
@@ -0,0 +1,147 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/global"
+	"linkstar/modules/event"
+	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
+	"sync"
+)
+
+// StatusSnapshot 全量状态快照
+type StatusSnapshot struct {
+	Config  model.StunConfig `json:"config"`  // 当前配置（含服务状态）
+	Running []RunningService `json:"running"` // 运行中的服务
+	Traffic []traffic.Stats  `json:"traffic"` // 各服务的流量统计
+}
+
+// Snapshot 获取当前全量状态
+func Snapshot() StatusSnapshot {
+	return StatusSnapshot{
+		Config:  global.StunConfig,
+		Running: GetRunningServices(),
+		Traffic: traffic.List(),
+	}
+}
+
+// errPortDrift 健康检查发现公网端口漂移
+type errPortDrift struct {
+	oldPort, newPort int
+}
+
+func (e *errPortDrift) Error() string {
+	return fmt.Sprintf("公网端口漂移 %d -> %d，需要重新打洞", e.oldPort, e.newPort)
+}
+
+var (
+	endpointsMu   sync.Mutex
+	lastEndpoints = make(map[string]string) // 每个服务最近一次的公网地址，用于判断是否变化
+)
+
+func serviceRef(device *model.Device, service *model.Service) event.ServiceRef {
+	return event.ServiceRef{
+		DeviceID:    device.DeviceID,
+		DeviceName:  device.Name,
+		DeviceIP:    device.IP,
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		Protocol:    service.Protocol,
+		TLS:         service.TLS,
+	}
+}
+
+// 服务上线，附带上次的公网地址
+func publishServiceOnline(device *model.Device, service *model.Service, publicAddr, publicURL string) {
+	key := serviceKey(device.DeviceID, service.ID)
+	endpointsMu.Lock()
+	oldAddr := lastEndpoints[key]
+	lastEndpoints[key] = publicAddr
+	endpointsMu.Unlock()
+
+	event.Publish(event.ServiceOnline{
+		ServiceRef: serviceRef(device, service),
+		PublicAddr: publicAddr,
+		OldAddr:    oldAddr,
+		PublicURL:  publicURL,
+	})
+}
+
+// 健康检查因端口漂移退出时发布 PortDrift
+func publishPortDrift(ref event.ServiceRef, publicIP string, err error) {
+	var drift *errPortDrift
+	if errors.As(err, &drift) {
+		portDrifts.Inc(ref.DeviceName, ref.ServiceName)
+		event.Publish(event.PortDrift{
+			ServiceRef: ref, PublicIP: publicIP, OldPort: drift.oldPort, NewPort: drift.newPort,
+		})
+	}
+}
+
+// 当前默认网关的描述，如 "IGDv2 192.168.1.1"
+func gatewayName(gw *model.UpnpGateway) string {
+	if gw == nil || gw.DefaultGateway == "" {
+		return ""
+	}
+	var host string
+	switch gw.DefaultGateway {
+	case "IGDv2":
+		host = gw.DefaultV2.Location.Hostname()
+	case "IGDv1":
+		host = gw.DefaultV1.Location.Hostname()
+	case "IGDv2ppp":
+		host = gw.DefaultV2ppp.Location.Hostname()
+	case "IGDv1ppp":
+		host = gw.DefaultV1ppp.Location.Hostname()
+	}
+	return gw.DefaultGateway + " " + host
+}
+
+// 访问地址，如 https://1.2.3.4:40001
+func buildPublicURL(tls bool, publicAddr string) string {
+	if tls {
+		return "https://" + publicAddr
+	}
+	return "http://" + publicAddr
+}
+
+// OnlineService 打洞成功且运行中的服务
+type OnlineService struct {
+	DeviceID    uint   `json:"deviceId"`    // 设备ID
+	DeviceName  string `json:"deviceName"`  // 设备名称
+	ServiceID   uint   `json:"serviceId"`   // 服务ID
+	ServiceName string `json:"serviceName"` // 服务名称
+	Protocol    string `json:"protocol"`    // 传输协议
+	PublicAddr  string `json:"publicAddr"`  // 公网地址 IP:端口
+	PublicURL   string `json:"publicURL"`   // 访问地址
+}
+
+// OnlineServices 当前在线的服务及其公网地址
+func OnlineServices() []OnlineService {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+
+	var list []OnlineService
+	for _, device := range global.StunConfig.Devices {
+		for _, service := range device.Services {
+			if !service.PunchSuccess || !IsServiceRunning(device.DeviceID, service.ID) {
+				continue
+			}
+			addr := lastEndpoints[serviceKey(device.DeviceID, service.ID)]
+			if addr == "" {
+				continue
+			}
+			list = append(list, OnlineService{
+				DeviceID:    device.DeviceID,
+				DeviceName:  device.Name,
+				ServiceID:   service.ID,
+				ServiceName: service.Name,
+				Protocol:    service.Protocol,
+				PublicAddr:  addr,
+				PublicURL:   buildPublicURL(service.TLS, addr),
+			})
+		}
+	}
+	return list
+}
@@ -9,16 +9,25 @@ import (
 )
 
 // 双向复制
-func Forward(src net.Conn, targetAddr string, protocol string) {
+// proxyProtocol 为 v1 / v2 时，连接内网目标后先发送 PROXY protocol 头，携带 src 的真实客户端地址
+// 只有连接内网目标失败时返回错误，供流量统计计数
+func Forward(src net.Conn, targetAddr string, protocol string, proxyProtocol string) error {
 	defer src.Close()
 
 	dst, err := net.DialTimeout(protocol, targetAddr, 3*time.Second)
 	if err != nil {
 		logrus.Errorf("连接内网目标失败 [%s]: %v", targetAddr, err)
-		return
+		return err
 	}
 	defer dst.Close()
 
+	if proxyProtocol != "" {
+		if err := writeProxyHeader(dst, proxyProtocol, src.RemoteAddr(), src.LocalAddr()); err != nil {
+			logrus.Errorf("发送 PROXY protocol 头失败 [%s]: %v", targetAddr, err)
+			return err
+		}
+	}
+
 	go func() {
 		_, _ = io.Copy(dst, src)
 		dst.Close() // src断了，关掉dst，让下面的io.Copy立刻返回
@@ -26,4 +35,5 @@ func Forward(src net.Conn, targetAddr string, protocol string) {
 
 	_, _ = io.Copy(src, dst)
 	src.Close() // dst断了，关掉src，让上面的io.Copy立刻返回
+	return nil
 }
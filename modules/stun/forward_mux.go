@@ -0,0 +1,183 @@
+package stun
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"linkstar/modules/stun/model"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// mux 识别出的协议
+const (
+	muxSSH     = "ssh"
+	muxHTTP    = "http"
+	muxTLS     = "tls"
+	muxRDP     = "rdp"
+	muxOpenVPN = "openvpn"
+	muxUnknown = "unknown" // 无法识别，或超时未收到客户端首包
+)
+
+const (
+	defaultMuxTimeout = 2 * time.Second
+	maxSniffBytes     = 16 // 最多预读的字节数，足够区分以上协议
+)
+
+var httpMethods = [][]byte{
+	[]byte("GET "), []byte("POST "), []byte("PUT "), []byte("HEAD "), []byte("DELETE "),
+	[]byte("OPTIONS "), []byte("PATCH "), []byte("CONNECT "), []byte("TRACE "), []byte("PRI * HTTP/2"),
+}
+
+// 前缀匹配结果
+const (
+	sniffNo    = iota // 不可能是该协议
+	sniffMaybe        // 数据不够，还需要继续读
+	sniffYes          // 确定是该协议
+)
+
+// 按固定前缀判断
+func sniffPrefix(b, prefix []byte) int {
+	if len(b) < len(prefix) {
+		if bytes.HasPrefix(prefix, b) {
+			return sniffMaybe
+		}
+		return sniffNo
+	}
+	if bytes.HasPrefix(b, prefix) {
+		return sniffYes
+	}
+	return sniffNo
+}
+
+// 按已读到的首包判断协议，数据不够时返回 decided=false
+func sniffProtocol(b []byte) (proto string, decided bool) {
+	maybe := false
+	check := func(state int) bool {
+		if state == sniffMaybe {
+			maybe = true
+		}
+		return state == sniffYes
+	}
+
+	// TLS 记录：handshake(0x16) + 版本主号 0x03
+	if check(sniffPrefix(b, []byte{tlsHandshakeByte, 0x03})) {
+		return muxTLS, true
+	}
+	if check(sniffPrefix(b, []byte("SSH-"))) {
+		return muxSSH, true
+	}
+	for _, m := range httpMethods {
+		if check(sniffPrefix(b, m)) {
+			return muxHTTP, true
+		}
+	}
+	// RDP：TPKT 头（03 00 + 长度）后是 X.224 Connection Request（0xE0）
+	if check(sniffRDP(b)) {
+		return muxRDP, true
+	}
+	// OpenVPN TCP：2 字节包长度 + 操作码 P_CONTROL_HARD_RESET_CLIENT_V2(7) / V3(10)
+	if check(sniffOpenVPN(b)) {
+		return muxOpenVPN, true
+	}
+	if maybe && len(b) < maxSniffBytes {
+		return "", false
+	}
+	return muxUnknown, true
+}
+
+func sniffRDP(b []byte) int {
+	if state := sniffPrefix(b, []byte{0x03, 0x00}); state != sniffYes {
+		return state
+	}
+	if len(b) < 6 {
+		return sniffMaybe
+	}
+	if b[5]&0xF0 == 0xE0 {
+		return sniffYes
+	}
+	return sniffNo
+}
+
+func sniffOpenVPN(b []byte) int {
+	if len(b) < 3 {
+		return sniffMaybe
+	}
+	length := int(b[0])<<8 | int(b[1])
+	opcode := b[2] >> 3
+	if length > 0 && (opcode == 7 || opcode == 10) {
+		return sniffYes
+	}
+	return sniffNo
+}
+
+// 预读首包识别协议，timeout 内没有收到数据视为服务端先发言的协议
+func detectProtocol(conn net.Conn, br *bufio.Reader, timeout time.Duration) string {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for n := 1; ; n = br.Buffered() + 1 {
+		b, err := br.Peek(n)
+		if err != nil {
+			// 超时或连接关闭：按已读到的数据做最终判断
+			if b = b[:br.Buffered()]; len(b) == 0 {
+				return muxUnknown
+			}
+			if proto, decided := sniffProtocol(b); decided {
+				return proto
+			}
+			return muxUnknown
+		}
+		if proto, decided := sniffProtocol(b); decided {
+			return proto
+		}
+	}
+}
+
+// ForwardMux 识别连接的协议并转发到对应的内网目标，首包数据原样交给目标
+// resolve 返回空字符串表示该协议没有目标；只有连接内网目标失败时返回错误
+func ForwardMux(conn net.Conn, timeout time.Duration, resolve func(proto string) string, proxyProtocol string) error {
+	br := bufio.NewReaderSize(conn, maxSniffBytes)
+	proto := detectProtocol(conn, br, timeout)
+
+	targetAddr := resolve(proto)
+	if targetAddr == "" {
+		logrus.Debugf("协议 %s 没有配置转发目标 [%s]", proto, conn.RemoteAddr())
+		conn.Close()
+		return nil
+	}
+	logrus.Debugf("识别为 %s，转发到 %s [%s]", proto, targetAddr, conn.RemoteAddr())
+	return Forward(&peekedConn{Conn: conn, r: br}, targetAddr, "tcp", proxyProtocol)
+}
+
+// mux 服务的等待首包超时
+func muxTimeout(service *model.Service) time.Duration {
+	if service.Mux.Timeout <= 0 {
+		return defaultMuxTimeout
+	}
+	return time.Duration(service.Mux.Timeout) * time.Millisecond
+}
+
+// mux 服务按协议选出目标地址，协议未配置端口时使用服务的内网端口作为默认目标
+func muxTarget(device *model.Device, service *model.Service, proto string) string {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	ports := map[string]uint16{
+		muxSSH:     service.Mux.SSH,
+		muxHTTP:    service.Mux.HTTP,
+		muxTLS:     service.Mux.TLS,
+		muxRDP:     service.Mux.RDP,
+		muxOpenVPN: service.Mux.OpenVPN,
+	}
+	port := ports[proto]
+	if port == 0 {
+		port = service.InternalPort
+	}
+	if port == 0 {
+		return ""
+	}
+	return net.JoinHostPort(device.IP, fmt.Sprint(port))
+}
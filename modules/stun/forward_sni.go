@@ -0,0 +1,82 @@
+package stun
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"linkstar/modules/stun/model"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 读到 ClientHello 后中止握手
+var errClientHelloRead = errors.New("client hello read")
+
+// readOnlyConn 只读连接，握手过程中试图写入（发送 alert）时直接失败，不会发给客户端
+type readOnlyConn struct {
+	r io.Reader
+}
+
+func (c readOnlyConn) Read(p []byte) (int, error)         { return c.r.Read(p) }
+func (c readOnlyConn) Write(p []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c readOnlyConn) Close() error                       { return nil }
+func (c readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c readOnlyConn) SetDeadline(t time.Time) error      { return nil }
+func (c readOnlyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c readOnlyConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// 读取 TLS ClientHello 中的 SNI，返回的连接会先重放已读取的字节
+func peekServerName(conn net.Conn) (string, net.Conn, error) {
+	var buf bytes.Buffer
+	var serverName string
+	err := tls.Server(readOnlyConn{r: io.TeeReader(conn, &buf)}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errClientHelloRead
+		},
+	}).Handshake()
+	if !errors.Is(err, errClientHelloRead) {
+		return "", nil, fmt.Errorf("不是 TLS 握手: %w", err)
+	}
+	return serverName, &peekedConn{Conn: conn, r: io.MultiReader(&buf, conn)}, nil
+}
+
+// ForwardSNI 按 ClientHello 中的 SNI 选择内网目标并透传原始 TLS 流，不解密
+// resolve 返回空字符串表示没有匹配的目标；只有连接内网目标失败时返回错误
+func ForwardSNI(conn net.Conn, resolve func(serverName string) string, proxyProtocol string) error {
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	serverName, pc, err := peekServerName(conn)
+	if err != nil {
+		logrus.Debugf("读取 SNI 失败 [%s]: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return nil
+	}
+	conn.SetDeadline(time.Time{})
+
+	targetAddr := resolve(serverName)
+	if targetAddr == "" {
+		logrus.Debugf("SNI %q 没有匹配的路由 [%s]", serverName, conn.RemoteAddr())
+		conn.Close()
+		return nil
+	}
+	return Forward(pc, targetAddr, "tcp", proxyProtocol)
+}
+
+// sni-proxy 服务按 SNI 选出目标地址，没有匹配的路由时使用服务的内网端口作为默认目标
+func sniTarget(device *model.Device, service *model.Service, serverName string) string {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if route := matchRoute(service.Routes, serverName, ""); route != nil {
+		return route.Target
+	}
+	if service.InternalPort == 0 {
+		return ""
+	}
+	return net.JoinHostPort(device.IP, fmt.Sprint(service.InternalPort))
+}
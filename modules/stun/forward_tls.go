@@ -0,0 +1,76 @@
+package stun
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TLS 记录类型 handshake
+const tlsHandshakeByte = 0x16
+
+// peekedConn 已预读部分数据的连接，Read 先返回预读的数据
+type peekedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// ForwardTLS 在打洞端口上终止 TLS，再以明文转发给内网目标
+// redirect 为 true 时，首字节不是 TLS 握手的连接按 HTTP 处理，301 跳转到 https
+// 只有连接内网目标失败时返回错误
+func ForwardTLS(conn net.Conn, targetAddr string, config *tls.Config, redirect bool, proxyProtocol string) error {
+	if tlsConn := acceptTLS(conn, config, redirect); tlsConn != nil {
+		return Forward(tlsConn, targetAddr, "tcp", proxyProtocol)
+	}
+	return nil
+}
+
+// 完成 TLS 握手，失败或已按 redirect 回复跳转时关闭连接并返回 nil
+func acceptTLS(conn net.Conn, config *tls.Config, redirect bool) *tls.Conn {
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	br := bufio.NewReader(conn)
+	first, err := br.Peek(1)
+	if err != nil {
+		conn.Close()
+		return nil
+	}
+	pc := &peekedConn{Conn: conn, r: br}
+
+	if first[0] != tlsHandshakeByte {
+		if redirect {
+			redirectToHTTPS(pc, br)
+		}
+		conn.Close()
+		return nil
+	}
+
+	tlsConn := tls.Server(pc, config)
+	if err := tlsConn.Handshake(); err != nil {
+		logrus.Debugf("TLS 握手失败 [%s]: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return nil
+	}
+	conn.SetDeadline(time.Time{})
+	return tlsConn
+}
+
+// 读取一个明文 HTTP 请求，回复 301 到同一 Host 的 https 地址
+func redirectToHTTPS(conn net.Conn, br *bufio.Reader) {
+	req, err := http.ReadRequest(br)
+	if err != nil || req.Host == "" {
+		return
+	}
+	location := "https://" + req.Host + req.URL.RequestURI()
+	fmt.Fprintf(conn, "HTTP/1.1 301 Moved Permanently\r\nLocation: %s\r\nContent-Length: 0\r\nConnection: close\r\n\r\n", location)
+}
@@ -3,6 +3,7 @@ package stun
 import (
 	"fmt"
 	"linkstar/global"
+	"linkstar/modules/event"
 	"net"
 	"strings"
 	"time"
@@ -128,8 +129,9 @@ func UpdatedPublicIP() {
 		}
 
 		// 获取新的公网ip成功
-		if global.StunConfig.PublicIP != publicIp {
+		if oldIP := global.StunConfig.PublicIP; oldIP != publicIp {
 			global.StunConfig.PublicIP = publicIp
+			event.Publish(event.PublicIPChanged{OldIP: oldIP, NewIP: publicIp})
 		}
 		time.Sleep(5 * time.Second)
 	}
@@ -0,0 +1,215 @@
+package stun
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 转发到内网 HTTP 目标共用的 Transport
+// 内网 https 目标多为自签名证书，不校验证书
+var proxyTransport = &http.Transport{
+	DialContext:           (&net.Dialer{Timeout: 3 * time.Second}).DialContext,
+	MaxIdleConnsPerHost:   16,
+	IdleConnTimeout:       90 * time.Second,
+	ResponseHeaderTimeout: 60 * time.Second,
+	TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+}
+
+// connListener 由打洞端口的 Accept 循环投递连接的 net.Listener，供 http.Server 使用
+type connListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newConnListener(addr net.Addr) *connListener {
+	return &connListener{addr: addr, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr { return l.addr }
+
+// 投递一个连接，监听器已关闭时直接关闭连接
+func (l *connListener) push(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+// httpProxy http-proxy 服务：在一个打洞端口上按 Host / 路径前缀分发到多个内网 HTTP 目标
+// 路由在每个请求时读取，修改路由不需要重新打洞
+type httpProxy struct {
+	device   *model.Device
+	service  *model.Service
+	listener *connListener
+	server   *http.Server
+	counter  *traffic.Counter // 记录转发失败次数
+}
+
+// 请求上下文中保存所属连接的 key
+type activeConnKey struct{}
+
+func newHTTPProxy(device *model.Device, service *model.Service, addr net.Addr) *httpProxy {
+	p := &httpProxy{
+		device:   device,
+		service:  service,
+		listener: newConnListener(addr),
+		counter:  traffic.Service(serviceKey(device.DeviceID, service.ID)),
+	}
+	p.server = &http.Server{
+		Handler: p,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, activeConnKey{}, activeConnOf(c))
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	go p.server.Serve(p.listener)
+	return p
+}
+
+// Close 关闭监听器与空闲连接，已升级的 WebSocket 连接随对端断开
+func (p *httpProxy) Close() {
+	p.server.Close()
+}
+
+// 交给 http.Server 处理，*tls.Conn 会被识别为 https 请求
+func (p *httpProxy) serveConn(conn net.Conn) {
+	p.listener.push(conn)
+}
+
+// 选出请求对应的目标，没有匹配的路由时使用服务的内网端口作为默认目标
+func (p *httpProxy) resolve(host, path string) (*model.ProxyRouteSpec, *url.URL) {
+	configMu.Lock()
+	route := matchRoute(p.service.Routes, host, path)
+	var spec model.ProxyRouteSpec
+	if route != nil {
+		spec = route.ProxyRouteSpec
+	}
+	ip, port := p.device.IP, p.service.InternalPort
+	configMu.Unlock()
+
+	if route == nil {
+		if port == 0 {
+			return nil, nil
+		}
+		return nil, &url.URL{Scheme: "http", Host: net.JoinHostPort(ip, fmt.Sprint(port))}
+	}
+	target, err := url.Parse(spec.Target)
+	if err != nil {
+		return nil, nil
+	}
+	return &spec, target
+}
+
+func (p *httpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, target := p.resolve(r.Host, r.URL.Path)
+	if target == nil {
+		http.Error(w, "no route for "+r.Host+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	if c, _ := r.Context().Value(activeConnKey{}).(*activeConn); c != nil {
+		c.setTarget(target.Host)
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Transport: proxyTransport,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if route != nil && route.StripPrefix && route.PathPrefix != "/" {
+				pr.Out.URL.Path = stripPrefix(pr.Out.URL.Path, route.PathPrefix)
+				if pr.Out.URL.RawPath != "" {
+					pr.Out.URL.RawPath = stripPrefix(pr.Out.URL.RawPath, route.PathPrefix)
+				}
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			if route != nil && route.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logrus.Warnf("[%s] 转发到 %s 失败: %v", p.service.Name, target.Host, err)
+			p.counter.Error()
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// 按优先级选出路由：精确 Host > 通配 Host > 不限 Host，同级按路径前缀最长匹配
+func matchRoute(routes []*model.ProxyRoute, host, path string) *model.ProxyRoute {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	var best *model.ProxyRoute
+	bestHost, bestLen := -1, -1
+	for _, r := range routes {
+		hostScore := hostMatch(r.Host, host)
+		if hostScore < 0 || !pathMatch(r.PathPrefix, path) {
+			continue
+		}
+		if hostScore > bestHost || (hostScore == bestHost && len(r.PathPrefix) > bestLen) {
+			best, bestHost, bestLen = r, hostScore, len(r.PathPrefix)
+		}
+	}
+	return best
+}
+
+// Host 匹配程度：2 精确，1 通配，0 不限，-1 不匹配
+func hostMatch(pattern, host string) int {
+	switch {
+	case pattern == "":
+		return 0
+	case pattern == host:
+		return 2
+	case strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]):
+		return 1
+	}
+	return -1
+}
+
+// 路径前缀按段匹配，/app 匹配 /app 与 /app/x，不匹配 /apple
+func pathMatch(prefix, path string) bool {
+	if prefix == "" || prefix == "/" || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, prefix+"/")
+}
+
+func stripPrefix(path, prefix string) string {
+	path = strings.TrimPrefix(path, prefix)
+	if path == "" {
+		return "/"
+	}
+	return path
+}
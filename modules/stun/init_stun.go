@@ -3,6 +3,9 @@ package stun
 import (
 	"fmt"
 	"linkstar/global"
+	"linkstar/modules/certs"
+	"linkstar/modules/event"
+	"linkstar/modules/metrics"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -18,6 +21,20 @@ func InitSTUN() error {
 		logrus.Fatal("读取配置文件失败", err)
 	}
 
+	// 删除证书前检查是否被服务使用
+	certs.InUse = append(certs.InUse, certInUse)
+
+	// /metrics 抓取时输出服务状态与流量
+	metrics.RegisterCollector(writeMetrics)
+
+	// 地址被自动封禁后断开其已建立的连接
+	event.Handle(16, func(env event.Envelope) {
+		e := env.Data.(event.IPBanned)
+		if n := KillConnsFrom(e.IP); n > 0 {
+			logrus.Infof("已断开封禁地址 %s 的 %d 个连接", e.IP, n)
+		}
+	}, event.KindIPBanned)
+
 	// 监听退出保持配置文件
 	go SetupShutdownHook(func() {
 		err := UpdateStunConfig(global.StunConfig)
@@ -63,7 +80,11 @@ func InitSTUN() error {
 		// 智能选择网关
 		SelectDefaultGateway(wg)
 
+		oldGateway := gatewayName(global.UpnpGateway)
 		global.UpnpGateway = wg
+		if newGateway := gatewayName(wg); newGateway != oldGateway {
+			event.Publish(event.GatewayChanged{OldGateway: oldGateway, NewGateway: newGateway})
+		}
 
 		return nil
 	})
@@ -0,0 +1,128 @@
+package stun
+
+import (
+	"linkstar/global"
+	"linkstar/modules/metrics"
+	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
+	"time"
+)
+
+var (
+	punchAttempts = metrics.NewCounterVec("linkstar_service_punch_attempts_total",
+		"打洞尝试次数", "device", "service")
+	repunches = metrics.NewCounterVec("linkstar_service_repunches_total",
+		"重新打洞次数，reason 为 manual（手动）或 failure（隧道异常退出后重试）", "device", "service", "reason")
+	portDrifts = metrics.NewCounterVec("linkstar_service_port_drifts_total",
+		"健康检查发现公网端口漂移的次数", "device", "service")
+	keepaliveFailures = metrics.NewCounterVec("linkstar_service_keepalive_failures_total",
+		"保活检查失败次数", "device", "service")
+	stunHandshakeSeconds = metrics.NewHistogramVec("linkstar_stun_handshake_duration_seconds",
+		"STUN 握手耗时", metrics.DurationBuckets, "server", "protocol")
+	stunHandshakeErrors = metrics.NewCounterVec("linkstar_stun_handshake_errors_total",
+		"STUN 握手失败次数", "server", "protocol")
+	upnpSeconds = metrics.NewHistogramVec("linkstar_upnp_operation_duration_seconds",
+		"UPnP 操作耗时（不含排队）", metrics.DurationBuckets, "operation")
+	upnpErrors = metrics.NewCounterVec("linkstar_upnp_operation_errors_total",
+		"UPnP 操作失败次数", "operation")
+)
+
+// 记录一次 STUN 握手
+func observeStunHandshake(server, protocol string, start time.Time, err error) {
+	if err != nil {
+		stunHandshakeErrors.Inc(server, protocol)
+		return
+	}
+	stunHandshakeSeconds.Observe(time.Since(start).Seconds(), server, protocol)
+}
+
+// 执行并记录一次 UPnP 操作
+func observeUpnp(operation string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	upnpSeconds.Observe(time.Since(start).Seconds(), operation)
+	if err != nil {
+		upnpErrors.Inc(operation)
+	}
+	return err
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// 抓取时计算的服务状态、流量与 UPnP 队列指标
+func writeMetrics(w *metrics.Writer) {
+	type serviceInfo struct {
+		key, device, service, protocol, typ string
+		deviceID, serviceID                 uint
+		enabled, punched                    bool
+	}
+	configMu.Lock()
+	var services []serviceInfo
+	for _, d := range global.StunConfig.Devices {
+		for _, s := range d.Services {
+			typ := s.Type
+			if typ == "" {
+				typ = model.ServiceTypeForward
+			}
+			services = append(services, serviceInfo{
+				key: serviceKey(d.DeviceID, s.ID), device: d.Name, service: s.Name, protocol: s.Protocol, typ: typ,
+				deviceID: d.DeviceID, serviceID: s.ID, enabled: s.Enabled, punched: s.PunchSuccess,
+			})
+		}
+	}
+	configMu.Unlock()
+
+	stats := map[string]traffic.Stats{}
+	for _, s := range traffic.List() {
+		stats[s.Key] = s
+	}
+
+	w.Header("linkstar_service_enabled", "gauge", "服务是否启用")
+	for _, s := range services {
+		w.Sample("linkstar_service_enabled", []string{
+			"device", s.device, "service", s.service, "protocol", s.protocol, "type", s.typ,
+		}, boolValue(s.enabled))
+	}
+	w.Header("linkstar_service_running", "gauge", "服务的打洞 goroutine 是否在运行")
+	for _, s := range services {
+		w.Sample("linkstar_service_running", []string{"device", s.device, "service", s.service},
+			boolValue(IsServiceRunning(s.deviceID, s.serviceID)))
+	}
+	w.Header("linkstar_service_online", "gauge", "服务是否打洞成功并在线")
+	for _, s := range services {
+		w.Sample("linkstar_service_online", []string{"device", s.device, "service", s.service},
+			boolValue(s.punched && IsServiceRunning(s.deviceID, s.serviceID)))
+	}
+
+	trafficMetrics := []struct {
+		name, typ, help string
+		value           func(traffic.Stats) float64
+	}{
+		{"linkstar_service_received_bytes_total", "counter", "从外部客户端收到的字节（重置统计后归零）",
+			func(s traffic.Stats) float64 { return float64(s.BytesIn) }},
+		{"linkstar_service_sent_bytes_total", "counter", "发往外部客户端的字节（重置统计后归零）",
+			func(s traffic.Stats) float64 { return float64(s.BytesOut) }},
+		{"linkstar_service_connections_total", "counter", "外部连接数（重置统计后归零）",
+			func(s traffic.Stats) float64 { return float64(s.Connections) }},
+		{"linkstar_service_target_errors_total", "counter", "连接内网目标失败次数（重置统计后归零）",
+			func(s traffic.Stats) float64 { return float64(s.Errors) }},
+		{"linkstar_service_active_connections", "gauge", "当前连接数",
+			func(s traffic.Stats) float64 { return float64(s.Active) }},
+	}
+	for _, m := range trafficMetrics {
+		w.Header(m.name, m.typ, m.help)
+		for _, s := range services {
+			w.Sample(m.name, []string{"device", s.device, "service", s.service}, m.value(stats[s.key]))
+		}
+	}
+
+	q := GetUpnpQueueState()
+	w.Gauge("linkstar_upnp_queue_depth", "UPnP 队列中排队的任务数", float64(q.Pending))
+	w.Gauge("linkstar_upnp_queue_capacity", "UPnP 队列容量", float64(q.Capacity))
+	w.Gauge("linkstar_upnp_queue_running", "UPnP 队列是否有任务正在执行", boolValue(q.Running))
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"linkstar/modules/limiter"
+	"time"
+)
 
 type StunConfig struct {
 	// 基础网络信息
@@ -12,29 +15,59 @@ type StunConfig struct {
 	CreatedAt     time.Time       `json:"createdAt"`     // 配置创建时间
 	UpdatedAt     time.Time       `json:"updatedAt"`     // 最后更新时间
 
-	Devices        []Device `json:"devices"`        // stun设备列表
-	StunServerList []string `json:"stunServerList"` // stun服务器列表
+	Devices        []*Device `json:"devices"`        // stun设备列表
+	StunServerList []string  `json:"stunServerList"` // stun服务器列表
 }
 
 type Device struct {
-	DeviceID uint      `josn:"id"`       // 设备ID
-	Name     string    `json:"name"`     // "本机" / "群晖NAS" / "树莓派"
-	IP       string    `json:"ip"`       // 设备ip
-	Services []Service `json:"services"` // 该设备上的服务
+	DeviceID uint       `josn:"id"`       // 设备ID
+	Name     string     `json:"name"`     // "本机" / "群晖NAS" / "树莓派"
+	IP       string     `json:"ip"`       // 设备ip
+	Services []*Service `json:"services"` // 该设备上的服务
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // Service 单个服务配置
+// 设备、服务均以指针保存，运行中的服务 goroutine 持有的指针不会因切片扩容而失效
 type Service struct {
-	ID             uint   `json:"id"` // 服务唯一标识符
-	StartupSuccess bool   `json:"-"`
-	Name           string `json:"name"`         // 服务名称,如 "SSH" / "Web管理" / "照片库"
-	InternalPort   uint16 `json:"internalPort"` // 内网端口,如 22
-	ExternalPort   uint16 `json:"externalPort"` // 外网映射端口,如 2222 (默认与 upnp映射端口一样
-	Protocol       string `json:"protocol"`     // 传输协议 "TCP"/"UDP" (默认 TCP)
-	TLS            bool   `json:"tls"`          // 证书
+	ID             uint `json:"id"` // 服务唯一标识符
+	StartupSuccess bool `json:"-"`
+	ServiceSpec
+
+	Routes []*ProxyRoute `json:"routes,omitempty"` // http-proxy 服务的路由，通过路由接口单独管理
+
+	ExternalPort uint16    `json:"externalPort"` // 外网映射端口,如 2222 (默认与 upnp映射端口一样
+	PunchSuccess bool      `json:"punchSuccess"` // STUN穿透是否成功
+	LastError    string    `json:"lastError"`    // 最后一次操作的错误信息
+	UpdatedAt    time.Time `json:"updatedAt"`    // 最后更新时间
+}
+
+// ServiceSpec 服务中由用户配置的部分
+type ServiceSpec struct {
+	Name         string `json:"name" binding:"required"`                                         // 服务名称,如 "SSH" / "Web管理" / "照片库"
+	Type         string `json:"type" binding:"omitempty,oneof=forward http-proxy sni-proxy mux"` // 服务类型 forward 端口转发（默认）/ http-proxy 按 Host 或路径分发 / sni-proxy 按 SNI 透传 TLS / mux 按协议分流
+	InternalPort uint16 `json:"internalPort"`                                                    // 内网端口,如 22；http-proxy / sni-proxy / mux 服务可选，作为未匹配时的默认目标
+	Protocol     string `json:"protocol" binding:"omitempty,protocol"`                           // 传输协议 "TCP"/"UDP" (默认 TCP)
+	TLS          bool   `json:"tls"`                                                             // 证书
+
+	// TLS 终止（仅 TCP）
+	TLSTerminate bool `json:"tlsTerminate"` // 在打洞端口上终止 TLS，以明文转发给内网目标（开启后 tls 自动为 true）
+	CertID       uint `json:"certId"`       // 使用的证书（证书管理中的ID），0 为进程内生成的自签名证书
+	HTTPRedirect bool `json:"httpRedirect"` // 同一端口收到明文 HTTP 请求时 301 跳转到 https
+
+	Mux MuxConfig `json:"mux"` // mux 服务的协议分流配置（仅 mux）
+
+	// PROXY protocol（仅 TCP）
+	ProxyProtocol       string `json:"proxyProtocol" binding:"omitempty,oneof=v1 v2"` // 连接内网目标时发送的 PROXY protocol 版本，为空不发送；http-proxy 服务改用 X-Forwarded-For
+	AcceptProxyProtocol bool   `json:"acceptProxyProtocol"`                           // 入站连接以 PROXY protocol 头开头（位于其他中继之后），从中取出真实客户端地址
+
+	// 访问控制，与全局列表叠加
+	Allow []string `json:"allow" binding:"omitempty,dive,cidr"` // 允许的客户端 CIDR，非空时只接受其中的地址并取代全局允许列表
+	Deny  []string `json:"deny" binding:"omitempty,dive,cidr"`  // 禁止的客户端 CIDR
+
+	Limits limiter.Limits `json:"limits"` // 并发连接、新建速率与带宽限制，与全局限制同时生效
 
 	// UPnP 相关配置
 	UseUPnP        bool   `json:"useUpnp"`        // 是否启用 UPnP 自动端口映射 (默认 true)
@@ -42,10 +75,41 @@ type Service struct {
 
 	Enabled     bool   `json:"enabled"`     // 服务是否启用 (默认 true)
 	Description string `json:"description"` // 服务描述信息 (可选)
+}
 
-	PunchSuccess bool      `json:"punchSuccess"` // STUN穿透是否成功
-	LastError    string    `json:"lastError"`    // 最后一次操作的错误信息
-	UpdatedAt    time.Time `json:"updatedAt"`    // 最后更新时间
+// 服务类型
+const (
+	ServiceTypeForward   = "forward"    // 端口转发
+	ServiceTypeHTTPProxy = "http-proxy" // HTTP 反向代理，一个打洞端口承载多个 Web 应用
+	ServiceTypeSNIProxy  = "sni-proxy"  // 按 TLS SNI 透传，后端保留自己的证书
+	ServiceTypeMux       = "mux"        // 按首包识别协议（SSH / HTTP / TLS / RDP / OpenVPN）分流到设备的不同端口
+)
+
+// MuxConfig mux 服务各协议转发到设备上的端口，为 0 时该协议交给默认目标（internalPort）
+type MuxConfig struct {
+	SSH     uint16 `json:"ssh"`                               // SSH，如 22
+	HTTP    uint16 `json:"http"`                              // 明文 HTTP，如 80
+	TLS     uint16 `json:"tls"`                               // TLS（HTTPS 等），如 443
+	RDP     uint16 `json:"rdp"`                               // 远程桌面，如 3389
+	OpenVPN uint16 `json:"openvpn"`                           // OpenVPN（TCP 模式），如 1194
+	Timeout int    `json:"timeout" binding:"min=0,max=10000"` // 等待客户端首包的毫秒数，0 为 2000；超时视为服务端先发言的协议，转发到默认目标
+}
+
+// ProxyRoute http-proxy / sni-proxy 服务的一条路由
+type ProxyRoute struct {
+	ID uint `json:"id"` // 路由ID
+	ProxyRouteSpec
+}
+
+// ProxyRouteSpec 路由中由用户配置的部分
+// 匹配优先级：精确 Host > 通配 Host > 不限 Host，同级按路径前缀最长匹配
+type ProxyRouteSpec struct {
+	Host         string `json:"host"`                                        // 匹配的 Host（sni-proxy 为 SNI），支持 *.example.com，为空匹配任意
+	PathPrefix   string `json:"pathPrefix" binding:"omitempty,startswith=/"` // 匹配的路径前缀，如 /photos，为空匹配任意路径（仅 http-proxy）
+	Target       string `json:"target" binding:"required"`                   // 内网目标，http-proxy 如 http://192.168.1.10:8080，sni-proxy 如 192.168.1.10:5001
+	StripPrefix  bool   `json:"stripPrefix"`                                 // 转发前去掉路径前缀（仅 http-proxy）
+	PreserveHost bool   `json:"preserveHost"`                                // 保留原始 Host 头，默认改写为目标地址（仅 http-proxy）
+	Description  string `json:"description"`                                 // 描述（可选）
 }
 
 // 每个Nat路由信息
@@ -0,0 +1,45 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ProbeTarget 探测内网目标是否在监听，返回空字符串表示正常，否则为警告信息
+// TCP 直接建连；UDP 发一个空包，收到 ICMP 端口不可达说明没有监听，超时无回应视为无法判断
+// 端口为 0（未设置默认目标的 http-proxy 服务）时不探测
+func ProbeTarget(ip string, port uint16, protocol string) string {
+	if port == 0 {
+		return ""
+	}
+	addr := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+
+	if strings.ToLower(protocol) == "udp" {
+		conn, err := net.DialTimeout("udp", addr, 2*time.Second)
+		if err != nil {
+			return fmt.Sprintf("无法探测 %s: %v", addr, err)
+		}
+		defer conn.Close()
+
+		conn.SetDeadline(time.Now().Add(1500 * time.Millisecond))
+		if _, err = conn.Write([]byte{}); err == nil {
+			_, err = conn.Read(make([]byte, 1500))
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Sprintf("%s (UDP) 端口不可达，目标可能没有在监听", addr)
+		}
+		return ""
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		return fmt.Sprintf("%s (TCP) 连接失败，目标可能没有在监听: %v", addr, err)
+	}
+	conn.Close()
+	return ""
+}
@@ -0,0 +1,175 @@
+package stun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PROXY protocol 版本
+const (
+	ProxyProtocolV1 = "v1"
+	ProxyProtocolV2 = "v2"
+)
+
+// v2 头的固定签名
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+var ErrProxyHeader = errors.New("PROXY protocol 头格式错误")
+
+// proxiedConn 地址取自 PROXY protocol 头的连接，RemoteAddr 为真实客户端地址
+type proxiedConn struct {
+	net.Conn
+	r      io.Reader
+	remote net.Addr
+	local  net.Addr
+}
+
+func (c *proxiedConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+func (c *proxiedConn) RemoteAddr() net.Addr       { return c.remote }
+func (c *proxiedConn) LocalAddr() net.Addr        { return c.local }
+
+// 写入 PROXY protocol 头，地址不是同族的 TCP 地址时写入 UNKNOWN / LOCAL
+func writeProxyHeader(w io.Writer, version string, src, dst net.Addr) error {
+	s, _ := src.(*net.TCPAddr)
+	d, _ := dst.(*net.TCPAddr)
+	known := s != nil && d != nil && (s.IP.To4() == nil) == (d.IP.To4() == nil)
+
+	if version == ProxyProtocolV1 {
+		if !known {
+			_, err := io.WriteString(w, "PROXY UNKNOWN\r\n")
+			return err
+		}
+		family := "TCP6"
+		if s.IP.To4() != nil {
+			family = "TCP4"
+		}
+		_, err := fmt.Fprintf(w, "PROXY %s %s %s %d %d\r\n", family, s.IP, d.IP, s.Port, d.Port)
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(proxyV2Signature)
+	if !known {
+		buf.Write([]byte{0x20, 0x00, 0x00, 0x00}) // LOCAL，不携带地址
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	srcIP, dstIP, family := s.IP.To4(), d.IP.To4(), byte(0x11) // TCP over IPv4
+	if srcIP == nil {
+		srcIP, dstIP, family = s.IP.To16(), d.IP.To16(), 0x21 // TCP over IPv6
+	}
+	buf.Write([]byte{0x21, family}) // 版本 2，命令 PROXY
+	binary.Write(&buf, binary.BigEndian, uint16(2*len(srcIP)+4))
+	buf.Write(srcIP)
+	buf.Write(dstIP)
+	binary.Write(&buf, binary.BigEndian, uint16(s.Port))
+	binary.Write(&buf, binary.BigEndian, uint16(d.Port))
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// 读取入站连接开头的 PROXY protocol 头（自动识别 v1 / v2），返回使用真实地址的连接
+// 开启后没有 PROXY 头的连接直接拒绝
+func readProxyHeader(conn net.Conn) (net.Conn, error) {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	br := bufio.NewReader(conn)
+	pc := &proxiedConn{Conn: conn, r: br, remote: conn.RemoteAddr(), local: conn.LocalAddr()}
+
+	sig, err := br.Peek(len(proxyV2Signature))
+	if err == nil && bytes.Equal(sig, proxyV2Signature) {
+		return pc, readProxyV2(br, pc)
+	}
+	if first, err := br.Peek(6); err != nil || string(first) != "PROXY " {
+		return nil, ErrProxyHeader
+	}
+	return pc, readProxyV1(br, pc)
+}
+
+// v1：PROXY TCP4 源IP 目标IP 源端口 目标端口\r\n，最长 107 字节
+func readProxyV1(br *bufio.Reader, pc *proxiedConn) error {
+	var line []byte
+	for len(line) < 107 {
+		b, err := br.ReadByte()
+		if err != nil {
+			return ErrProxyHeader
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return ErrProxyHeader
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return ErrProxyHeader
+	}
+	src, err := parseTCPAddr(fields[2], fields[4])
+	if err != nil {
+		return err
+	}
+	dst, err := parseTCPAddr(fields[3], fields[5])
+	if err != nil {
+		return err
+	}
+	pc.remote, pc.local = src, dst
+	return nil
+}
+
+func parseTCPAddr(ip, port string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	p, err := strconv.ParseUint(port, 10, 16)
+	if addr == nil || err != nil {
+		return nil, ErrProxyHeader
+	}
+	return &net.TCPAddr{IP: addr, Port: int(p)}, nil
+}
+
+// v2：签名 + 版本/命令 + 地址族 + 长度 + 地址，LOCAL 命令与不认识的地址族保留原地址
+func readProxyV2(br *bufio.Reader, pc *proxiedConn) error {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return ErrProxyHeader
+	}
+	if header[12]>>4 != 2 {
+		return ErrProxyHeader
+	}
+	body := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(br, body); err != nil {
+		return ErrProxyHeader
+	}
+	if header[12]&0x0F != 0x01 { // LOCAL（健康检查等）
+		return nil
+	}
+
+	var ipLen int
+	switch header[13] {
+	case 0x11, 0x12: // TCP / UDP over IPv4
+		ipLen = net.IPv4len
+	case 0x21, 0x22: // TCP / UDP over IPv6
+		ipLen = net.IPv6len
+	default:
+		return nil
+	}
+	if len(body) < 2*ipLen+4 {
+		return ErrProxyHeader
+	}
+	ports := body[2*ipLen:]
+	pc.remote = &net.TCPAddr{IP: net.IP(body[:ipLen]), Port: int(binary.BigEndian.Uint16(ports[0:2]))}
+	pc.local = &net.TCPAddr{IP: net.IP(body[ipLen : 2*ipLen]), Port: int(binary.BigEndian.Uint16(ports[2:4]))}
+	return nil
+}
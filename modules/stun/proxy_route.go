@@ -0,0 +1,171 @@
+package stun
+
+import (
+	"errors"
+	"linkstar/modules/stun/model"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotProxyService = errors.New("服务不是 http-proxy / sni-proxy 类型")
+	ErrRouteNotFound   = errors.New("路由不存在")
+	ErrRouteExists     = errors.New("已存在相同 Host 和路径前缀的路由")
+	ErrRouteTarget     = errors.New("路由目标必须是 http:// 或 https:// 地址")
+	ErrSNIRouteTarget  = errors.New("sni-proxy 路由目标必须是 host:port 地址")
+	ErrSNIRoutePath    = errors.New("sni-proxy 路由不支持路径前缀")
+)
+
+// 规范化并检查路由：Host 小写，路径前缀去掉末尾的 /
+func normalizeRoute(serviceType string, spec *model.ProxyRouteSpec) error {
+	spec.Host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(spec.Host), "."))
+	if spec.PathPrefix != "/" {
+		spec.PathPrefix = strings.TrimSuffix(spec.PathPrefix, "/")
+	}
+
+	if serviceType == model.ServiceTypeSNIProxy {
+		if spec.PathPrefix != "" {
+			return ErrSNIRoutePath
+		}
+		host, port, err := net.SplitHostPort(spec.Target)
+		if err != nil || host == "" || strings.Contains(spec.Target, "/") {
+			return ErrSNIRouteTarget
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return ErrSNIRouteTarget
+		}
+		return nil
+	}
+	u, err := url.Parse(spec.Target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrRouteTarget
+	}
+	return nil
+}
+
+// 查找 http-proxy / sni-proxy 服务，调用方需持有 configMu
+func findProxyService(deviceID, serviceID uint) (*model.Service, error) {
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return nil, ErrDeviceNotFound
+	}
+	_, svc := findService(device, serviceID)
+	if svc == nil {
+		return nil, ErrServiceNotFound
+	}
+	if svc.Type != model.ServiceTypeHTTPProxy && svc.Type != model.ServiceTypeSNIProxy {
+		return nil, ErrNotProxyService
+	}
+	return svc, nil
+}
+
+func findRoute(svc *model.Service, routeID uint) (int, *model.ProxyRoute) {
+	for i, r := range svc.Routes {
+		if r.ID == routeID {
+			return i, r
+		}
+	}
+	return -1, nil
+}
+
+// 同一服务下 Host + 路径前缀不能重复，excludeID 为更新时自身的ID
+func routeTaken(svc *model.Service, spec model.ProxyRouteSpec, excludeID uint) bool {
+	for _, r := range svc.Routes {
+		if r.ID != excludeID && r.Host == spec.Host && r.PathPrefix == spec.PathPrefix {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRoutes http-proxy / sni-proxy 服务的全部路由
+func ListRoutes(deviceID, serviceID uint) ([]model.ProxyRoute, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	svc, err := findProxyService(deviceID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]model.ProxyRoute, 0, len(svc.Routes))
+	for _, r := range svc.Routes {
+		list = append(list, *r)
+	}
+	return list, nil
+}
+
+// AddRoute 新增路由，立即对新请求生效，不需要重新打洞
+func AddRoute(deviceID, serviceID uint, spec model.ProxyRouteSpec) (model.ProxyRoute, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	svc, err := findProxyService(deviceID, serviceID)
+	if err != nil {
+		return model.ProxyRoute{}, err
+	}
+	if err := normalizeRoute(svc.Type, &spec); err != nil {
+		return model.ProxyRoute{}, err
+	}
+	if routeTaken(svc, spec, 0) {
+		return model.ProxyRoute{}, ErrRouteExists
+	}
+
+	var maxID uint = 0
+	for _, r := range svc.Routes {
+		if r.ID > maxID {
+			maxID = r.ID
+		}
+	}
+	route := &model.ProxyRoute{ID: maxID + 1, ProxyRouteSpec: spec}
+	svc.Routes = append(svc.Routes, route)
+	if err := saveStunConfig(); err != nil {
+		return model.ProxyRoute{}, err
+	}
+	return *route, nil
+}
+
+// UpdateRoute 修改路由，立即对新请求生效
+func UpdateRoute(deviceID, serviceID, routeID uint, spec model.ProxyRouteSpec) (model.ProxyRoute, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	svc, err := findProxyService(deviceID, serviceID)
+	if err != nil {
+		return model.ProxyRoute{}, err
+	}
+	if err := normalizeRoute(svc.Type, &spec); err != nil {
+		return model.ProxyRoute{}, err
+	}
+	_, route := findRoute(svc, routeID)
+	if route == nil {
+		return model.ProxyRoute{}, ErrRouteNotFound
+	}
+	if routeTaken(svc, spec, routeID) {
+		return model.ProxyRoute{}, ErrRouteExists
+	}
+	route.ProxyRouteSpec = spec
+	if err := saveStunConfig(); err != nil {
+		return model.ProxyRoute{}, err
+	}
+	return *route, nil
+}
+
+// DeleteRoute 删除路由
+func DeleteRoute(deviceID, serviceID, routeID uint) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	svc, err := findProxyService(deviceID, serviceID)
+	if err != nil {
+		return err
+	}
+	index, route := findRoute(svc, routeID)
+	if route == nil {
+		return ErrRouteNotFound
+	}
+	routes := svc.Routes
+	svc.Routes = append(routes[:index:index], routes[index+1:]...)
+	return saveStunConfig()
+}
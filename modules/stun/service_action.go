@@ -0,0 +1,120 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/modules/stun/model"
+	"sync"
+	"time"
+)
+
+// 服务生命周期操作
+const (
+	ActionStart   = "start"   // 启用并启动（可恢复因重试次数耗尽被自动关闭的服务）
+	ActionStop    = "stop"    // 停止并禁用
+	ActionRestart = "restart" // 停旧起新
+	ActionRepunch = "repunch" // 保持运行，放弃当前映射重新打洞
+)
+
+var (
+	ErrUnknownAction     = errors.New("未知的操作")
+	ErrServiceDisabled   = errors.New("服务未启用")
+	ErrServiceNotRunning = errors.New("服务未运行")
+)
+
+// ServiceTarget 批量操作的目标服务
+type ServiceTarget struct {
+	DeviceID  uint `json:"deviceId" binding:"required"`  // 设备ID
+	ServiceID uint `json:"serviceId" binding:"required"` // 服务ID
+}
+
+// ActionResult 单个服务的操作结果
+type ActionResult struct {
+	DeviceID  uint   `json:"deviceId"`  // 设备ID
+	ServiceID uint   `json:"serviceId"` // 服务ID
+	Success   bool   `json:"success"`   // 是否成功
+	Error     string `json:"error"`     // 失败原因
+}
+
+// 修改服务启用状态并持久化
+func setServiceEnabled(service *model.Service, enabled bool) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if service.Enabled == enabled {
+		return nil
+	}
+	service.Enabled = enabled
+	service.UpdatedAt = time.Now()
+	return saveStunConfig()
+}
+
+// ServiceAction 对单个服务执行 start / stop / restart / repunch
+func ServiceAction(deviceID, serviceID uint, action string) (*model.Service, error) {
+	device, service, err := lookupService(deviceID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case ActionStart:
+		if err := setServiceEnabled(service, true); err != nil {
+			return nil, err
+		}
+		StartService(device, service)
+	case ActionStop:
+		StopService(deviceID, serviceID)
+		if err := setServiceEnabled(service, false); err != nil {
+			return nil, err
+		}
+	case ActionRestart:
+		if !service.Enabled {
+			return nil, ErrServiceDisabled
+		}
+		StartService(device, service)
+	case ActionRepunch:
+		if err := RepunchService(deviceID, serviceID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+	_, service, err = GetService(deviceID, serviceID)
+	return service, err
+}
+
+// DeviceAction 对设备下所有服务执行同一操作
+func DeviceAction(deviceID uint, action string) ([]ActionResult, error) {
+	device, err := GetDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]ServiceTarget, 0, len(device.Services))
+	for _, svc := range device.Services {
+		targets = append(targets, ServiceTarget{DeviceID: deviceID, ServiceID: svc.ID})
+	}
+	return BulkServiceAction(targets, action), nil
+}
+
+// BulkServiceAction 并发对多个服务执行同一操作，结果顺序与 targets 一致
+func BulkServiceAction(targets []ServiceTarget, action string) []ActionResult {
+	results := make([]ActionResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t ServiceTarget) {
+			defer wg.Done()
+			result := ActionResult{DeviceID: t.DeviceID, ServiceID: t.ServiceID, Success: true}
+			if _, err := ServiceAction(t.DeviceID, t.ServiceID, action); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, t)
+	}
+	wg.Wait()
+
+	return results
+}
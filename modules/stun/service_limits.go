@@ -0,0 +1,48 @@
+package stun
+
+import (
+	"linkstar/modules/limiter"
+)
+
+// GetServiceLimits 服务的限制及当前并发连接数
+func GetServiceLimits(deviceID, serviceID uint) (limiter.Status, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return limiter.Status{}, ErrDeviceNotFound
+	}
+	_, svc := findService(device, serviceID)
+	if svc == nil {
+		return limiter.Status{}, ErrServiceNotFound
+	}
+	// 服务未运行时 Limiter 可能还没有加载配置，以配置为准
+	status := limiter.Service(serviceKey(deviceID, serviceID)).Status()
+	status.Limits = svc.Limits
+	return status, nil
+}
+
+// UpdateServiceLimits 修改服务的限制，持久化后立即生效，不重新打洞
+func UpdateServiceLimits(deviceID, serviceID uint, limits limiter.Limits) (limiter.Status, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, device := findDevice(deviceID)
+	if device == nil {
+		return limiter.Status{}, ErrDeviceNotFound
+	}
+	_, svc := findService(device, serviceID)
+	if svc == nil {
+		return limiter.Status{}, ErrServiceNotFound
+	}
+	old := svc.Limits
+	svc.Limits = limits
+	if err := saveStunConfig(); err != nil {
+		svc.Limits = old
+		return limiter.Status{}, err
+	}
+	lim := limiter.Service(serviceKey(deviceID, serviceID))
+	lim.Update(limits)
+	return lim.Status(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"linkstar/global"
 	"linkstar/modules/stun/model"
+	"runtime/pprof"
 	"sync"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 
 // serviceEntry 记录一个正在运行的服务
 type serviceEntry struct {
-	cancel context.CancelFunc
-	done   chan struct{} // goroutine 退出时关闭，用于等待旧实例真正结束
+	cancel    context.CancelFunc
+	done      chan struct{} // goroutine 退出时关闭，用于等待旧实例真正结束
+	repunch   chan struct{} // 通知当前隧道放弃映射、重新打洞
+	startedAt time.Time
 }
 
 var (
@@ -58,10 +61,12 @@ func StartService(device *model.Device, service *model.Service) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{}) // 每次启动新建一个 done channel
-	runningServices[key] = &serviceEntry{cancel: cancel, done: done}
+	repunch := make(chan struct{}, 1)
+	runningServices[key] = &serviceEntry{cancel: cancel, done: done, repunch: repunch, startedAt: time.Now()}
 	servicesMu.Unlock()
 
-	go func() {
+	// 打上 pprof 标签，诊断接口按服务统计 goroutine（子 goroutine 自动继承）
+	go pprof.Do(ctx, pprof.Labels(serviceLabel, key), func(ctx context.Context) {
 		defer close(done) // 无论以何种方式退出，都关闭 done 通知等待方
 
 		maxRetries := 5
@@ -75,9 +80,26 @@ func StartService(device *model.Device, service *model.Service) {
 			}
 
 			attempt++
+			punchAttempts.Inc(device.Name, service.Name)
 			logrus.Infof("[%s - %s] 启动服务 (第 %d 次)", device.Name, service.Name, attempt)
 
-			err := RunStunTunnelWithContext(ctx, device.IP, service)
+			// 每次打洞单独一个 ctx，收到重新打洞信号时只取消本次隧道
+			tunnelCtx, tunnelCancel := context.WithCancel(ctx)
+			repunched := false
+			watchDone := make(chan struct{})
+			go func() {
+				defer close(watchDone)
+				select {
+				case <-repunch:
+					repunched = true
+					tunnelCancel()
+				case <-tunnelCtx.Done():
+				}
+			}()
+
+			err := RunStunTunnelWithContext(tunnelCtx, device, service)
+			tunnelCancel()
+			<-watchDone
 
 			// ctx 被取消，正常退出
 			if ctx.Err() != nil {
@@ -86,6 +108,14 @@ func StartService(device *model.Device, service *model.Service) {
 				return
 			}
 
+			// 手动触发的重新打洞，不计入失败次数
+			if repunched {
+				attempt = 0
+				repunches.Inc(device.Name, service.Name, "manual")
+				logrus.Infof("[%s - %s] 重新打洞", device.Name, service.Name)
+				continue
+			}
+
 			if err != nil {
 				logrus.Errorf("❌ [%s - %s] STUN 穿透失败 (第 %d/%d 次): %v",
 					device.Name, service.Name, attempt, maxRetries, err)
@@ -105,11 +135,12 @@ func StartService(device *model.Device, service *model.Service) {
 					return
 				}
 
+				repunches.Inc(device.Name, service.Name, "failure")
 				time.Sleep(time.Second)
 				continue
 			}
 		}
-	}()
+	})
 }
 
 // StopService 停止指定服务的 goroutine
@@ -137,12 +168,35 @@ func StopService(deviceID, serviceID uint) {
 	}
 }
 
+// RepunchService 让运行中的服务放弃当前映射重新打洞，服务未运行时返回 ErrServiceNotRunning
+func RepunchService(deviceID, serviceID uint) error {
+	key := serviceKey(deviceID, serviceID)
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+
+	entry, ok := runningServices[key]
+	if !ok {
+		return ErrServiceNotRunning
+	}
+	select {
+	case entry.repunch <- struct{}{}:
+	default: // 已有待处理的重新打洞信号
+	}
+	return nil
+}
+
+// IsServiceRunning 服务是否在运行
+func IsServiceRunning(deviceID, serviceID uint) bool {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	_, ok := runningServices[serviceKey(deviceID, serviceID)]
+	return ok
+}
+
 // StartAllServices 启动全部已启用的服务（程序初始化时调用）
 func StartAllServices() {
-	for i := range global.StunConfig.Devices {
-		device := &global.StunConfig.Devices[i]
-		for j := range device.Services {
-			service := &device.Services[j]
+	for _, device := range global.StunConfig.Devices {
+		for _, service := range device.Services {
 			if service.Enabled {
 				StartService(device, service)
 			}
@@ -0,0 +1,21 @@
+package stun
+
+import (
+	"linkstar/modules/traffic"
+)
+
+// GetServiceTraffic 服务的累计及每日流量
+func GetServiceTraffic(deviceID, serviceID uint) (traffic.Stats, error) {
+	if err := serviceExists(deviceID, serviceID); err != nil {
+		return traffic.Stats{}, err
+	}
+	return traffic.Get(serviceKey(deviceID, serviceID)), nil
+}
+
+// ResetServiceTraffic 清空服务的流量统计
+func ResetServiceTraffic(deviceID, serviceID uint) error {
+	if err := serviceExists(deviceID, serviceID); err != nil {
+		return err
+	}
+	return traffic.Reset(serviceKey(deviceID, serviceID))
+}
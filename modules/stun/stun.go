@@ -2,10 +2,18 @@ package stun
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"linkstar/global"
+	"linkstar/modules/accesslog"
+	"linkstar/modules/certs"
+	"linkstar/modules/event"
+	"linkstar/modules/firewall"
+	"linkstar/modules/limiter"
 	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,8 +23,11 @@ import (
 )
 
 // RunStunTunnelWithContext 实现内网穿透逻辑  支持 context 取消的穿透逻辑
-func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *model.Service) error {
+func RunStunTunnelWithContext(ctx context.Context, device *model.Device, service *model.Service) (retErr error) {
 	protocol := strings.ToLower(service.Protocol) // 转为小写
+	targetIP := device.IP
+	key := serviceKey(device.DeviceID, service.ID)
+	ref := serviceRef(device, service)
 
 	localAddr := fmt.Sprintf("%s:0", global.StunConfig.LocalIP) //端口为0任意端口
 
@@ -48,11 +59,21 @@ func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *mod
 		return fmt.Errorf("与STUN服务器握手失败:%w", err)
 	}
 
+	setStunBinding(StunBinding{
+		Key:        key,
+		Protocol:   protocol,
+		LocalAddr:  stunConn.LocalAddr().String(),
+		PublicAddr: net.JoinHostPort(publicIP, strconv.Itoa(publicPort)),
+		StunServer: global.StunConfig.BestSTUN,
+		BoundAt:    time.Now(),
+	})
+
 	// 端口复用监听
 	listenAddr := fmt.Sprintf("%s:%d", global.StunConfig.LocalIP, localPort)
 	listener, err := reuseport.Listen(protocol, listenAddr) // 使用reuseport SO_REUSEPORT 可以复用端口
 	if err != nil {
 		stunConn.Close()
+		removeStunBinding(key)
 		return fmt.Errorf("端口监听失败：%w", err)
 	}
 
@@ -61,11 +82,16 @@ func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *mod
 	defer upnpCancel()
 	description := fmt.Sprintf("LinkStar-%s", service.Name)
 	err = AddPortMappingQueue(upnpCtx, localPort, localPort, "TCP", description)
+	mapped := err == nil
 	if err != nil {
 		logrus.Warnf("[%s] UPnP 映射失败 (非致命): %v", service.Name, err)
 		// todo 处理失败
+		event.Publish(event.MappingFailed{ServiceRef: ref, ExternalPort: localPort, Error: err.Error()})
 	} else {
 		logrus.Infof("[%s] UPnP 映射成功: 路由器 WAN:%d -> 本机:%d", service.Name, localPort, localPort)
+		event.Publish(event.MappingAdded{
+			ServiceRef: ref, ExternalPort: localPort, InternalPort: localPort, Gateway: gatewayName(global.UpnpGateway),
+		})
 	}
 
 	// ctx 取消时关闭连接，让所有阻塞调用立即返回
@@ -83,31 +109,44 @@ func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *mod
 		logrus.Infof("[%s] 正在清理资源...", service.Name)
 		stunConn.Close()
 		listener.Close()
-		go DeletePortMapping(localPort, protocol)
+		removeStunBinding(key)
+		go func() {
+			if err := DeletePortMapping(localPort, protocol); err == nil && mapped {
+				event.Publish(event.MappingRemoved{
+					ServiceRef: ref, ExternalPort: localPort, Gateway: gatewayName(global.UpnpGateway),
+				})
+			}
+		}()
 		service.PunchSuccess = false
 		service.ExternalPort = 0
+
+		reason := "服务已停止"
+		if retErr != nil && ctx.Err() == nil {
+			reason = retErr.Error()
+		}
+		event.Publish(event.ServiceOffline{
+			ServiceRef: ref, PublicAddr: net.JoinHostPort(publicIP, strconv.Itoa(publicPort)), Reason: reason,
+		})
 	}()
 
 	errCh := make(chan error, 3)
 	logrus.Infof("%v %v %v", localPort, publicIP, publicPort)
 
 	// 存储数据
-	var publicURL string
-	if service.TLS {
-		publicURL = fmt.Sprintf("https://%s:%d", publicIP, publicPort)
-	} else {
-		publicURL = fmt.Sprintf("http://%s:%d", publicIP, publicPort)
-	}
+	publicAddr := net.JoinHostPort(publicIP, strconv.Itoa(publicPort))
+	publicURL := buildPublicURL(service.TLS, publicAddr)
 
 	service.ExternalPort = uint16(publicPort)
 	service.PunchSuccess = true
+	publishServiceOnline(device, service, publicAddr, publicURL)
 
 	// 开启保活
 	if protocol == "tcp" {
 		go func() {
-			err = tcpStunHealthCheck(innerCtx, stunConn, publicIP, publicPort, localPort, service)
+			err = tcpStunHealthCheck(innerCtx, stunConn, publicIP, publicPort, localPort, service, ref)
 			if err != nil {
 				service.PunchSuccess = false
+				publishPortDrift(ref, publicIP, err)
 				errCh <- fmt.Errorf("TCP健康检查失败: %w", err)
 			}
 		}()
@@ -115,9 +154,10 @@ func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *mod
 		go func() {
 			udpConn := stunConn.(*net.UDPConn)
 			stunServerAddr, _ := net.ResolveUDPAddr("udp", global.StunConfig.BestSTUN)
-			err = udpStunHealthCheck(udpConn, stunServerAddr, publicPort, localPort)
+			err = udpStunHealthCheck(innerCtx, udpConn, stunServerAddr, publicPort, localPort, ref)
 			if err != nil {
 				service.PunchSuccess = false
+				publishPortDrift(ref, publicIP, err)
 				errCh <- fmt.Errorf("UDP健康检查失败: %w", err)
 			}
 		}()
@@ -125,24 +165,117 @@ func RunStunTunnelWithContext(ctx context.Context, targetIP string, service *mod
 
 	go func() {
 		targetAddr := fmt.Sprintf("%s:%d", targetIP, service.InternalPort)
+		var tlsConfig *tls.Config
+		if service.TLSTerminate && protocol == "tcp" {
+			tlsConfig = certs.ServerConfig(service.CertID)
+		}
+		var proxy *httpProxy
+		if service.Type == model.ServiceTypeHTTPProxy && protocol == "tcp" {
+			proxy = newHTTPProxy(device, service, listener.Addr())
+			defer proxy.Close()
+		}
+		// 限制在运行中修改时直接更新 Limiter，不重启服务
+		lim := limiter.Service(key)
+		lim.Update(service.Limits)
+		counter := traffic.Service(key)
+		// 修改服务会重启隧道，访问控制列表在启动时解析一次即可
+		rules := serviceRules(service)
+		// 按服务类型处理一个入站连接
+		handle := func(clientConn *activeConn) {
+			logrus.Infof("[%s] 收到外部连接: %s", service.Name, clientConn.RemoteAddr())
+			event.Publish(event.ConnectionAccepted{ServiceRef: ref, RemoteAddr: clientConn.RemoteAddr().String()})
+			var err error
+			switch {
+			case proxy != nil && tlsConfig != nil:
+				if tlsConn := acceptTLS(clientConn, tlsConfig, service.HTTPRedirect); tlsConn != nil {
+					proxy.serveConn(tlsConn)
+				}
+			case proxy != nil:
+				proxy.serveConn(clientConn)
+			case service.Type == model.ServiceTypeSNIProxy:
+				err = ForwardSNI(clientConn, func(serverName string) string {
+					target := sniTarget(device, service, serverName)
+					clientConn.setTarget(target)
+					return target
+				}, service.ProxyProtocol)
+			case service.Type == model.ServiceTypeMux:
+				err = ForwardMux(clientConn, muxTimeout(service), func(proto string) string {
+					target := muxTarget(device, service, proto)
+					clientConn.setTarget(target)
+					return target
+				}, service.ProxyProtocol)
+			case tlsConfig != nil:
+				clientConn.setTarget(targetAddr)
+				err = ForwardTLS(clientConn, targetAddr, tlsConfig, service.HTTPRedirect, service.ProxyProtocol)
+			default:
+				clientConn.setTarget(targetAddr)
+				err = Forward(clientConn, targetAddr, protocol, service.ProxyProtocol)
+			}
+			if err != nil {
+				counter.Error()
+				clientConn.setCloseReason(accesslog.ReasonTargetUnreachable)
+			}
+			if proxy == nil {
+				clientConn.finish()
+			}
+		}
 		for {
 			clientConn, err := listener.Accept()
 			if err != nil {
 				errCh <- fmt.Errorf("监听器退出: %w", err)
 				return
 			}
-			logrus.Infof("[%s] 收到外部连接: %s", service.Name, clientConn.RemoteAddr())
-			go Forward(clientConn, targetAddr, protocol)
+			go func() {
+				// 位于其他中继之后时，先从 PROXY protocol 头取出真实客户端地址
+				if service.AcceptProxyProtocol {
+					conn, err := readProxyHeader(clientConn)
+					if err != nil {
+						logrus.Warnf("[%s] 拒绝连接 %s: %v", service.Name, clientConn.RemoteAddr(), err)
+						clientConn.Close()
+						return
+					}
+					clientConn = conn
+				}
+				if err := firewall.Check(clientConn.RemoteAddr(), rules); err != nil {
+					logrus.Debugf("[%s] 拒绝连接 %s: %v", service.Name, clientConn.RemoteAddr(), err)
+					clientConn.Close()
+					return
+				}
+				conn, err := limiter.Accept(clientConn, lim)
+				if err != nil {
+					logrus.Debugf("[%s] 拒绝连接 %s: %v", service.Name, clientConn.RemoteAddr(), err)
+					clientConn.Close()
+					return
+				}
+				counted := counter.Track(conn)
+				tracked, err := firewall.Track(counted, ref)
+				if err != nil {
+					// 本连接触发了自动封禁
+					logrus.Debugf("[%s] 拒绝连接 %s: %v", service.Name, clientConn.RemoteAddr(), err)
+					counted.Close()
+					return
+				}
+				// 直接转发时等 handle 得知是否连上目标后再写访问日志；http-proxy 的连接由 http.Server 关闭时写
+				handle(trackConn(tracked, device, service, proxy == nil))
+			}()
 		}
 	}()
 
 	// 输出访问数据
 	logrus.Infof("   访问地址: %s", publicURL)
-	return <-errCh
+
+	// ctx 取消（停止、重启、重新打洞）时立即返回，由 defer 关闭连接和监听器
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // 与STUN服务器握手TCP
-func doTcpStunHandshake(conn net.Conn) (string, int, error) {
+func doTcpStunHandshake(conn net.Conn) (ip string, port int, err error) {
+	defer func(start time.Time) { observeStunHandshake(conn.RemoteAddr().String(), "tcp", start, err) }(time.Now())
 
 	// 发送STUN请求
 	msg := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
@@ -177,7 +310,8 @@ func doTcpStunHandshake(conn net.Conn) (string, int, error) {
 }
 
 // 与STUN服务器握手UDP
-func doUDPStunHandshake(conn *net.UDPConn, stunServerAddr *net.UDPAddr) (string, int, error) {
+func doUDPStunHandshake(conn *net.UDPConn, stunServerAddr *net.UDPAddr) (ip string, port int, err error) {
+	defer func(start time.Time) { observeStunHandshake(stunServerAddr.String(), "udp", start, err) }(time.Now())
 
 	// 发送STUN请求
 	msg := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
@@ -226,12 +360,13 @@ func firstTcpHealthKeep(publicIP string, expectedPublicPort int) bool {
 }
 
 // TCP STUN 健康检测
-func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string, expectedPublicPort int, localPort uint16, service *model.Service) error {
+func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string, expectedPublicPort int, localPort uint16, service *model.Service, ref event.ServiceRef) error {
 	healthTicker := time.NewTicker(28 * time.Second) // 每28s 检测一次
 	defer healthTicker.Stop()
 
 	// 首次保活与检测
 	if !firstTcpHealthKeep(publicIP, expectedPublicPort) {
+		keepaliveFailures.Inc(ref.DeviceName, ref.ServiceName)
 		return fmt.Errorf("[%s] 首次保活失败，重启", service.Name)
 	}
 	service.PunchSuccess = true
@@ -264,6 +399,7 @@ func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string,
 			}
 
 			logrus.Warnf("[%s] 端到端检查失败 (%d/%d)", service.Name, failureCount, maxFailures)
+			keepaliveFailures.Inc(ref.DeviceName, ref.ServiceName)
 
 			// 策略2: STUN 检测NAT映射
 			_, port, err := doTcpStunHandshake(currentStunConn)
@@ -289,7 +425,7 @@ func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string,
 				// 检查端口是否变化
 				if newPort != expectedPublicPort {
 					newConn.Close()
-					return fmt.Errorf("公网端口漂移 %d -> %d", expectedPublicPort, newPort)
+					return &errPortDrift{oldPort: expectedPublicPort, newPort: newPort}
 				}
 
 				logrus.Infof("✅ STUN重连成功，端口保持 %d", newPort)
@@ -303,7 +439,7 @@ func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string,
 
 			// STUN正常但端口变化，触发重启
 			if port != expectedPublicPort {
-				return fmt.Errorf("❌ 公网端口漂移 %d -> %d，需要重新打洞", expectedPublicPort, port)
+				return &errPortDrift{oldPort: expectedPublicPort, newPort: port}
 			}
 
 			// STUN正常但服务持续失败，可能是上游服务问题
@@ -314,7 +450,7 @@ func tcpStunHealthCheck(ctx context.Context, stunConn net.Conn, publicIP string,
 }
 
 // UDP健康检测
-func udpStunHealthCheck(udpConn *net.UDPConn, stunServer *net.UDPAddr, expectedPublicPort int, localPort uint16) error {
+func udpStunHealthCheck(ctx context.Context, udpConn *net.UDPConn, stunServer *net.UDPAddr, expectedPublicPort int, localPort uint16, ref event.ServiceRef) error {
 	healthTicker := time.NewTicker(280 * time.Second) // 每28s 健康检测一次
 	defer healthTicker.Stop()
 
@@ -324,12 +460,19 @@ func udpStunHealthCheck(udpConn *net.UDPConn, stunServer *net.UDPAddr, expectedP
 
 	logrus.Info("启动UDP健康检查 间隔28s")
 
-	for range healthTicker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-healthTicker.C:
+		}
+
 		// STUN 检测NAT映射
 		_, port, err := doUDPStunHandshake(currentConn, stunServer)
 		if err != nil {
 			consecutiveFailures++
 			logrus.Warnf("UDP STUN检查失败 (%d/%d): %v", consecutiveFailures, maxFailures, err)
+			keepaliveFailures.Inc(ref.DeviceName, ref.ServiceName)
 
 			// 达到失败阈值，尝试重建
 			if consecutiveFailures >= maxFailures {
@@ -355,7 +498,7 @@ func udpStunHealthCheck(udpConn *net.UDPConn, stunServer *net.UDPAddr, expectedP
 				// 判断端口变没，如果变了必须退出，重新打洞
 				if newPort != expectedPublicPort {
 					newConn.Close()
-					return fmt.Errorf("公网端口漂移 %d -> %d", expectedPublicPort, newPort)
+					return &errPortDrift{oldPort: expectedPublicPort, newPort: newPort}
 				}
 
 				logrus.Infof("✅ UDP重建成功，端口保持 %d", newPort)
@@ -367,19 +510,17 @@ func udpStunHealthCheck(udpConn *net.UDPConn, stunServer *net.UDPAddr, expectedP
 
 		// STUN 正常 但是端口变了，得重启
 		if port != expectedPublicPort {
-			return fmt.Errorf("❌ 公网端口变化 %d -> %d", expectedPublicPort, port)
+			return &errPortDrift{oldPort: expectedPublicPort, newPort: port}
 		}
 
 		// STUN正常
 		consecutiveFailures = 0
 	}
-	return nil
-
 }
 
 // tcpConnectCheck 通用 TCP 连通性检查
 func tcpConnectCheck(host string, port int, timeout time.Duration) bool {
-	addr := fmt.Sprintf("%s:%d", host, port)
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
 	conn, err := net.DialTimeout("tcp", addr, timeout)
 	if err != nil {
 		logrus.Debugf("TCP连接检查失败 %s: %v", addr, err)
@@ -0,0 +1,47 @@
+package stun
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StunBinding 一个服务当前占用的 STUN 套接字绑定
+type StunBinding struct {
+	Key        string    `json:"key"`        // "deviceID-serviceID"
+	Protocol   string    `json:"protocol"`   // tcp / udp
+	LocalAddr  string    `json:"localAddr"`  // 本机复用端口地址
+	PublicAddr string    `json:"publicAddr"` // STUN 映射出的公网地址
+	StunServer string    `json:"stunServer"` // 使用的 STUN 服务器
+	BoundAt    time.Time `json:"boundAt"`    // 绑定时间
+}
+
+var (
+	bindingsMu   sync.Mutex
+	stunBindings = make(map[string]StunBinding) // key: "deviceID-serviceID"
+)
+
+func setStunBinding(b StunBinding) {
+	bindingsMu.Lock()
+	defer bindingsMu.Unlock()
+	stunBindings[b.Key] = b
+}
+
+func removeStunBinding(key string) {
+	bindingsMu.Lock()
+	defer bindingsMu.Unlock()
+	delete(stunBindings, key)
+}
+
+// GetStunBindings 获取当前全部 STUN 绑定（按 key 排序）
+func GetStunBindings() []StunBinding {
+	bindingsMu.Lock()
+	list := make([]StunBinding, 0, len(stunBindings))
+	for _, b := range stunBindings {
+		list = append(list, b)
+	}
+	bindingsMu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
@@ -7,6 +7,8 @@ import (
 	"linkstar/modules/stun/model"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/huin/goupnp/dcps/internetgateway1"
 	"github.com/huin/goupnp/dcps/internetgateway2"
@@ -27,6 +29,25 @@ type UpnpQueue struct {
 	taskCh chan upnpTask
 	once   sync.Once
 	cancel context.CancelFunc
+
+	// 运行状态，供诊断接口读取
+	running   atomic.Bool
+	processed atomic.Uint64
+	failed    atomic.Uint64
+	mu        sync.Mutex
+	lastError string
+	lastRunAt time.Time
+}
+
+// UpnpQueueState upnp队列状态快照
+type UpnpQueueState struct {
+	Pending   int       `json:"pending"`   // 排队中的任务数
+	Capacity  int       `json:"capacity"`  // 队列容量
+	Running   bool      `json:"running"`   // 是否有任务正在执行
+	Processed uint64    `json:"processed"` // 已执行任务数
+	Failed    uint64    `json:"failed"`    // 执行失败的任务数
+	LastError string    `json:"lastError"` // 最后一次失败原因
+	LastRunAt time.Time `json:"lastRunAt"` // 最后一次执行时间
 }
 
 // // upnp网关
@@ -176,7 +197,11 @@ func (q *UpnpQueue) worker(ctx context.Context) {
 		case <-ctx.Done(): //收到信号退出
 			return
 		case task := <-q.taskCh:
-			task.resultCh <- task.fn() //结果写到返回通道
+			q.running.Store(true)
+			err := task.fn()
+			q.running.Store(false)
+			q.record(err)
+			task.resultCh <- err //结果写到返回通道
 		}
 
 	}
@@ -202,6 +227,38 @@ func (q *UpnpQueue) submit(ctx context.Context, fn func() error) error {
 
 }
 
+// 记录任务执行结果
+func (q *UpnpQueue) record(err error) {
+	q.processed.Add(1)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.lastRunAt = time.Now()
+	if err != nil {
+		q.failed.Add(1)
+		q.lastError = err.Error()
+	}
+}
+
+// State 获取队列状态
+func (q *UpnpQueue) State() UpnpQueueState {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return UpnpQueueState{
+		Pending:   len(q.taskCh),
+		Capacity:  cap(q.taskCh),
+		Running:   q.running.Load(),
+		Processed: q.processed.Load(),
+		Failed:    q.failed.Load(),
+		LastError: q.lastError,
+		LastRunAt: q.lastRunAt,
+	}
+}
+
+// GetUpnpQueueState 获取全局upnp队列状态
+func GetUpnpQueueState() UpnpQueueState {
+	return upnpQueue.State()
+}
+
 // stop 优雅停止
 func (q *UpnpQueue) stop() {
 	q.once.Do(q.cancel)
@@ -210,14 +267,18 @@ func (q *UpnpQueue) stop() {
 // 添加Upnp端口映射队列
 func AddPortMappingQueue(ctx context.Context, externalPort, internalPort uint16, protocol, description string) error {
 	return upnpQueue.submit(ctx, func() error {
-		return AddPortMapping(externalPort, internalPort, protocol, description)
+		return observeUpnp("add_port_mapping", func() error {
+			return AddPortMapping(externalPort, internalPort, protocol, description)
+		})
 	})
 }
 
 // 删除Upnp端口映射
 func DeletePortMappingSave(ctx context.Context, externalPort uint16, protocol string) error {
 	return upnpQueue.submit(ctx, func() error {
-		return DeletePortMapping(externalPort, protocol)
+		return observeUpnp("delete_port_mapping", func() error {
+			return DeletePortMapping(externalPort, protocol)
+		})
 	})
 }
 
@@ -0,0 +1,251 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	trafficConfigPath = "config/traffic.json"
+	keepDays          = 90 // 每日统计保留天数
+	dateLayout        = time.DateOnly
+)
+
+var (
+	ErrSaveConfig = errors.New("保存流量统计失败")
+)
+
+// Totals 一段时间内的流量
+type Totals struct {
+	BytesIn     uint64 `json:"bytesIn"`     // 从外部客户端收到的字节
+	BytesOut    uint64 `json:"bytesOut"`    // 发往外部客户端的字节
+	Connections uint64 `json:"connections"` // 连接数
+	Errors      uint64 `json:"errors"`      // 连接内网目标失败次数
+}
+
+func (t *Totals) add(o Totals) {
+	t.BytesIn += o.BytesIn
+	t.BytesOut += o.BytesOut
+	t.Connections += o.Connections
+	t.Errors += o.Errors
+}
+
+// Day 一天的流量
+type Day struct {
+	Date string `json:"date"` // 日期，如 "2024-05-01"
+	Totals
+}
+
+// Stats 服务的流量统计
+type Stats struct {
+	Key string `json:"key"` // "deviceID-serviceID"
+	Totals
+	Active int64     `json:"active"`         // 当前连接数
+	Since  time.Time `json:"since"`          // 开始统计（上次重置）的时间
+	Days   []Day     `json:"days,omitempty"` // 每日流量，按日期升序，仅单个服务的查询返回
+}
+
+// record 持久化的统计
+type record struct {
+	Since time.Time          `json:"since"` // 开始统计的时间
+	Total Totals             `json:"total"` // 累计
+	Days  map[string]*Totals `json:"days"`  // 每日流量，key 为日期
+}
+
+// Config 流量统计文件（config/traffic.json），每分钟写入一次
+type Config struct {
+	Services map[string]*record `json:"services"` // key 为 "deviceID-serviceID"
+}
+
+var (
+	configMu sync.Mutex
+	config   = Config{Services: map[string]*record{}}
+)
+
+// 读取统计文件，不存在时视为空
+func readConfig() (Config, error) {
+	c := Config{}
+	if fileInfo, err := os.Stat(trafficConfigPath); err == nil && fileInfo.Size() > 0 {
+		var err error
+		if c, err = utilsFile.ReadJsonFile[Config](trafficConfigPath); err != nil {
+			return c, err
+		}
+	}
+	if c.Services == nil {
+		c.Services = map[string]*record{}
+	}
+	return c, nil
+}
+
+// 持久化统计，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(trafficConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(trafficConfigPath, config); err != nil {
+		logrus.Error("流量统计写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+// 取服务的持久化记录，不存在时创建，调用方需持有 configMu
+func recordOf(key string) *record {
+	r, ok := config.Services[key]
+	if !ok {
+		r = &record{Since: time.Now(), Days: map[string]*Totals{}}
+		config.Services[key] = r
+	}
+	if r.Days == nil {
+		r.Days = map[string]*Totals{}
+	}
+	return r
+}
+
+// 把计数器的增量并入当天和累计统计，清理过期的每日记录，调用方需持有 configMu
+func merge(now time.Time) {
+	today := now.Format(dateLayout)
+	oldest := now.AddDate(0, 0, -keepDays+1).Format(dateLayout)
+
+	countersMu.Lock()
+	defer countersMu.Unlock()
+
+	for key, c := range counters {
+		delta := c.drain()
+		if delta == (Totals{}) {
+			continue
+		}
+		r := recordOf(key)
+		r.Total.add(delta)
+		day := r.Days[today]
+		if day == nil {
+			day = &Totals{}
+			r.Days[today] = day
+		}
+		day.add(delta)
+	}
+	for _, r := range config.Services {
+		for date := range r.Days {
+			if date < oldest {
+				delete(r.Days, date)
+			}
+		}
+	}
+}
+
+// Flush 把实时计数并入统计并写入文件
+func Flush() error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	merge(time.Now())
+	return saveConfig()
+}
+
+// Init 读取历史统计并每分钟写入一次
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取流量统计失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			Flush()
+		}
+	}()
+}
+
+// 组装统计，调用方需持有 configMu 且已调用 merge
+func statsOf(key string, r *record, withDays bool) Stats {
+	s := Stats{Key: key, Totals: r.Total, Since: r.Since}
+	countersMu.Lock()
+	if c, ok := counters[key]; ok {
+		s.Active = c.active.Load()
+	}
+	countersMu.Unlock()
+
+	if withDays {
+		s.Days = make([]Day, 0, len(r.Days))
+		for date, t := range r.Days {
+			s.Days = append(s.Days, Day{Date: date, Totals: *t})
+		}
+		sort.Slice(s.Days, func(i, j int) bool { return s.Days[i].Date < s.Days[j].Date })
+	}
+	return s
+}
+
+// List 全部服务的累计统计，按 key 排序
+func List() []Stats {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	merge(time.Now())
+	list := make([]Stats, 0, len(config.Services))
+	for key, r := range config.Services {
+		list = append(list, statsOf(key, r, false))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// Get 单个服务的累计及每日统计，还没有任何流量时返回空统计
+func Get(key string) Stats {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	merge(time.Now())
+	if r, ok := config.Services[key]; ok {
+		return statsOf(key, r, true)
+	}
+	return Stats{Key: key, Days: []Day{}}
+}
+
+// Reset 清空服务的统计（含每日记录）并从现在开始重新统计，当前连接数不受影响
+func Reset(key string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	now := time.Now()
+	merge(now)
+	config.Services[key] = &record{Since: now, Days: map[string]*Totals{}}
+	return saveConfig()
+}
+
+// ResetAll 清空全部服务的统计
+func ResetAll() error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	now := time.Now()
+	merge(now)
+	for key := range config.Services {
+		config.Services[key] = &record{Since: now, Days: map[string]*Totals{}}
+	}
+	return saveConfig()
+}
+
+// Remove 服务删除后移除其统计
+func Remove(key string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	countersMu.Lock()
+	delete(counters, key)
+	countersMu.Unlock()
+	delete(config.Services, key)
+	if err := saveConfig(); err != nil {
+		logrus.Warnf("移除流量统计失败: %v", err)
+	}
+}
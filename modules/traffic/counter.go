@@ -0,0 +1,77 @@
+package traffic
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter 单个服务的实时计数，定期并入持久化的累计和每日统计
+type Counter struct {
+	bytesIn, bytesOut, conns, errs atomic.Uint64 // 尚未并入统计的增量
+	active                         atomic.Int64  // 当前连接数
+}
+
+// 取出并清零尚未并入统计的增量
+func (c *Counter) drain() Totals {
+	return Totals{
+		BytesIn:     c.bytesIn.Swap(0),
+		BytesOut:    c.bytesOut.Swap(0),
+		Connections: c.conns.Swap(0),
+		Errors:      c.errs.Swap(0),
+	}
+}
+
+// Error 记录一次连接内网目标失败
+func (c *Counter) Error() {
+	c.errs.Add(1)
+}
+
+// Track 记录一个新连接，返回的连接统计收发字节，关闭时减少当前连接数
+func (c *Counter) Track(conn net.Conn) net.Conn {
+	c.conns.Add(1)
+	c.active.Add(1)
+	return &countedConn{Conn: conn, counter: c}
+}
+
+// countedConn 统计外部客户端连接的收发字节
+type countedConn struct {
+	net.Conn
+	counter *Counter
+	once    sync.Once
+}
+
+func (c *countedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.counter.bytesIn.Add(uint64(n))
+	return n, err
+}
+
+func (c *countedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.counter.bytesOut.Add(uint64(n))
+	return n, err
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() { c.counter.active.Add(-1) })
+	return c.Conn.Close()
+}
+
+var (
+	countersMu sync.Mutex
+	counters   = map[string]*Counter{}
+)
+
+// Service 取服务的计数器，key 为 "deviceID-serviceID"
+func Service(key string) *Counter {
+	countersMu.Lock()
+	defer countersMu.Unlock()
+
+	c, ok := counters[key]
+	if !ok {
+		c = &Counter{}
+		counters[key] = c
+	}
+	return c
+}
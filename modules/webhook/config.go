@@ -0,0 +1,207 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"linkstar/modules/event"
+	"linkstar/utils/utilsFile"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const webhookConfigPath = "config/webhooks.json"
+
+var (
+	ErrWebhookNotFound = errors.New("webhook 不存在")
+	ErrInvalidTemplate = errors.New("请求体模板语法错误")
+	ErrSaveConfig      = errors.New("保存 webhook 配置失败")
+)
+
+// Config webhook 配置文件（config/webhooks.json）
+type Config struct {
+	Webhooks []*Webhook `json:"webhooks"` // webhook 列表
+}
+
+// Webhook 一个推送目标
+type Webhook struct {
+	ID uint `json:"id"` // webhook ID
+	Spec
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Spec webhook 中由用户配置的部分
+type Spec struct {
+	Name string `json:"name" binding:"required"`    // 名称
+	URL  string `json:"url" binding:"required,url"` // 接收地址
+
+	Events []event.Kind `json:"events" binding:"dive,oneof=service.online service.offline service.portDrift publicIP.changed mapping.added mapping.removed mapping.failed gateway.changed connection.accepted ip.banned"` // 订阅的事件，为空表示除 connection.accepted 外的全部
+
+	DeviceID     uint              `json:"deviceId"`                          // 只推送该设备的事件，0 表示全部
+	ServiceID    uint              `json:"serviceId"`                         // 只推送该服务的事件，0 表示全部
+	Headers      map[string]string `json:"headers"`                           // 自定义请求头
+	BodyTemplate string            `json:"bodyTemplate"`                      // 请求体模板（Go text/template，数据为 Payload），为空时发送 Payload 的 JSON
+	Secret       string            `json:"secret,omitempty"`                  // HMAC-SHA256 签名密钥，为空不签名；接口返回时隐藏
+	MaxRetries   int               `json:"maxRetries" binding:"min=0,max=10"` // 失败后的重试次数
+	Timeout      int               `json:"timeout" binding:"min=0,max=60"`    // 单次请求超时（秒），0 为默认 10 秒
+	Enabled      bool              `json:"enabled"`                           // 是否启用
+}
+
+// Info 接口返回的 webhook，不含签名密钥
+type Info struct {
+	Webhook
+	HasSecret bool `json:"hasSecret"` // 是否设置了签名密钥
+}
+
+func (wh Webhook) info() Info {
+	hasSecret := wh.Secret != ""
+	wh.Secret = ""
+	return Info{Webhook: wh, HasSecret: hasSecret}
+}
+
+var (
+	configMu sync.Mutex
+	config   Config
+)
+
+// 读取配置文件，不存在时视为空配置
+func readConfig() (Config, error) {
+	if fileInfo, err := os.Stat(webhookConfigPath); os.IsNotExist(err) || fileInfo.Size() == 0 {
+		return Config{Webhooks: []*Webhook{}}, nil
+	}
+	c, err := utilsFile.ReadJsonFile[Config](webhookConfigPath)
+	if err != nil {
+		return c, err
+	}
+	if c.Webhooks == nil {
+		c.Webhooks = []*Webhook{}
+	}
+	return c, nil
+}
+
+// 持久化配置，失败时包装为 ErrSaveConfig
+func saveConfig() error {
+	if err := os.MkdirAll(filepath.Dir(webhookConfigPath), 0755); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	if err := utilsFile.WriteJsonFile(webhookConfigPath, config); err != nil {
+		logrus.Error("webhook 配置写入失败：", err)
+		return fmt.Errorf("%w: %v", ErrSaveConfig, err)
+	}
+	return nil
+}
+
+func findWebhook(id uint) (int, *Webhook) {
+	for i, wh := range config.Webhooks {
+		if wh.ID == id {
+			return i, wh
+		}
+	}
+	return -1, nil
+}
+
+// ListWebhooks 全部 webhook
+func ListWebhooks() []Info {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	list := make([]Info, 0, len(config.Webhooks))
+	for _, wh := range config.Webhooks {
+		list = append(list, wh.info())
+	}
+	return list
+}
+
+// 按ID取出 webhook 的副本（含密钥），供投递使用
+func getWebhook(id uint) (Webhook, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, wh := findWebhook(id)
+	if wh == nil {
+		return Webhook{}, ErrWebhookNotFound
+	}
+	return *wh, nil
+}
+
+// GetWebhook 按ID查找 webhook
+func GetWebhook(id uint) (Info, error) {
+	wh, err := getWebhook(id)
+	if err != nil {
+		return Info{}, err
+	}
+	return wh.info(), nil
+}
+
+// AddWebhook 新增 webhook 并持久化
+func AddWebhook(spec Spec) (Info, error) {
+	if err := checkTemplate(spec.BodyTemplate); err != nil {
+		return Info{}, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var maxID uint = 0
+	for _, wh := range config.Webhooks {
+		if wh.ID > maxID {
+			maxID = wh.ID
+		}
+	}
+
+	wh := &Webhook{
+		ID:        maxID + 1,
+		Spec:      spec,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	config.Webhooks = append(config.Webhooks, wh)
+	if err := saveConfig(); err != nil {
+		return Info{}, err
+	}
+	return wh.info(), nil
+}
+
+// UpdateWebhook 修改 webhook 并持久化
+// 接口不返回密钥，spec.Secret 为空时保留原密钥，removeSecret 为 true 时清除
+func UpdateWebhook(id uint, spec Spec, removeSecret bool) (Info, error) {
+	if err := checkTemplate(spec.BodyTemplate); err != nil {
+		return Info{}, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	_, wh := findWebhook(id)
+	if wh == nil {
+		return Info{}, ErrWebhookNotFound
+	}
+	if spec.Secret == "" && !removeSecret {
+		spec.Secret = wh.Secret
+	}
+	wh.Spec = spec
+	wh.UpdatedAt = time.Now()
+	if err := saveConfig(); err != nil {
+		return Info{}, err
+	}
+	return wh.info(), nil
+}
+
+// DeleteWebhook 删除 webhook，投递记录保留
+func DeleteWebhook(id uint) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index, wh := findWebhook(id)
+	if wh == nil {
+		return ErrWebhookNotFound
+	}
+	webhooks := config.Webhooks
+	config.Webhooks = append(webhooks[:index:index], webhooks[index+1:]...)
+	return saveConfig()
+}
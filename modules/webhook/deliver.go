@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"linkstar/modules/event"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 第 n 次重试前等待 retryBaseDelay * 2^(n-1)
+var retryBaseDelay = time.Second
+
+const (
+	defaultTimeout   = 10 * time.Second
+	retryMaxDelay    = 60 * time.Second // 单次等待上限
+	deliveryLogSize  = 500              // 保留的投递记录数
+	responseBodySize = 1024             // 记录的响应体长度上限
+)
+
+// 投递状态
+const (
+	StatusPending = "pending" // 投递中（含等待重试）
+	StatusSuccess = "success" // 收到 2xx
+	StatusFailed  = "failed"  // 重试耗尽或不可重试的错误
+)
+
+// Delivery 一次投递记录
+type Delivery struct {
+	ID         uint64     `json:"id"`         // 投递ID，同时通过 X-LinkStar-Delivery 请求头发送
+	WebhookID  uint       `json:"webhookId"`  // webhook ID
+	EventID    uint64     `json:"eventId"`    // 事件ID
+	Event      event.Kind `json:"event"`      // 事件类型
+	URL        string     `json:"url"`        // 接收地址
+	Status     string     `json:"status"`     // pending / success / failed
+	Attempts   int        `json:"attempts"`   // 已请求次数
+	StatusCode int        `json:"statusCode"` // 最后一次响应状态码，请求未完成为 0
+	Error      string     `json:"error"`      // 最后一次失败原因
+	Request    string     `json:"request"`    // 请求体
+	Response   string     `json:"response"`   // 最后一次响应体（截断）
+	CreatedAt  time.Time  `json:"createdAt"`  // 创建时间
+	FinishedAt time.Time  `json:"finishedAt"` // 完成时间
+}
+
+var (
+	deliveriesMu sync.Mutex
+	deliveryID   uint64
+	deliveries   []*Delivery // 最近的投递记录，按时间先后
+)
+
+var client = &http.Client{}
+
+func newDelivery(wh Webhook, env event.Envelope) *Delivery {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	deliveryID++
+	d := &Delivery{
+		ID:        deliveryID,
+		WebhookID: wh.ID,
+		EventID:   env.ID,
+		Event:     env.Type,
+		URL:       wh.URL,
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+	if len(deliveries) >= deliveryLogSize {
+		deliveries = append(deliveries[:0], deliveries[1:]...)
+	}
+	deliveries = append(deliveries, d)
+	return d
+}
+
+// 修改投递记录
+func (d *Delivery) update(fn func(d *Delivery)) {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+	fn(d)
+}
+
+// ListDeliveries 最近的投递记录，新的在前；webhookID 为 0 时返回全部
+func ListDeliveries(webhookID uint) []Delivery {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	list := []Delivery{}
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if webhookID == 0 || deliveries[i].WebhookID == webhookID {
+			list = append(list, *deliveries[i])
+		}
+	}
+	return list
+}
+
+// 第 attempt 次重试前的等待时间
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
+
+// 网络错误、5xx、429 可重试
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// 对请求体签名，接收方用同一密钥计算 HMAC-SHA256 后比对
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver 投递一个事件，失败时按指数退避重试，阻塞直到完成
+func deliver(wh Webhook, env event.Envelope) Delivery {
+	d := newDelivery(wh, env)
+
+	body, err := renderBody(wh.BodyTemplate, buildPayload(env))
+	if err != nil {
+		d.update(func(d *Delivery) {
+			d.Status = StatusFailed
+			d.Error = err.Error()
+			d.FinishedAt = time.Now()
+		})
+		logrus.Warnf("[webhook %s] 生成请求体失败: %v", wh.Name, err)
+		return *d
+	}
+	d.update(func(d *Delivery) { d.Request = string(body) })
+
+	for attempt := 0; attempt <= wh.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff(attempt))
+		}
+
+		statusCode, response, err := send(wh, d.ID, env, body)
+		d.update(func(d *Delivery) {
+			d.Attempts = attempt + 1
+			d.StatusCode = statusCode
+			d.Response = response
+			d.Error = ""
+			if err != nil {
+				d.Error = err.Error()
+			}
+		})
+
+		if err == nil {
+			d.update(func(d *Delivery) {
+				d.Status = StatusSuccess
+				d.FinishedAt = time.Now()
+			})
+			return *d
+		}
+		logrus.Warnf("[webhook %s] 投递失败 (第 %d/%d 次): %v", wh.Name, attempt+1, wh.MaxRetries+1, err)
+		if !retryable(statusCode) {
+			break
+		}
+	}
+
+	d.update(func(d *Delivery) {
+		d.Status = StatusFailed
+		d.FinishedAt = time.Now()
+	})
+	return *d
+}
+
+// 发送一次请求，非 2xx 视为失败
+func send(wh Webhook, id uint64, env event.Envelope, body []byte) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LinkStar-Webhook")
+	for k, v := range wh.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("X-LinkStar-Event", string(env.Type))
+	req.Header.Set("X-LinkStar-Delivery", strconv.FormatUint(id, 10))
+	if wh.Secret != "" {
+		req.Header.Set("X-LinkStar-Signature", sign(wh.Secret, body))
+	}
+
+	timeout := defaultTimeout
+	if wh.Timeout > 0 {
+		timeout = time.Duration(wh.Timeout) * time.Second
+	}
+	c := *client
+	c.Timeout = timeout
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodySize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(data), fmt.Errorf("接收方返回 %s", resp.Status)
+	}
+	return resp.StatusCode, string(data), nil
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"linkstar/modules/event"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 本地接收方，按顺序返回 codes 中的状态码，之后一律 200
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+	times    []time.Time
+}
+
+func newReceiver(t *testing.T, codes ...int) *receiver {
+	r := &receiver{codes: codes}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		r.times = append(r.times, time.Now())
+		code := http.StatusOK
+		if len(r.codes) > 0 {
+			code, r.codes = r.codes[0], r.codes[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(code)
+		io.WriteString(w, http.StatusText(code))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func fastRetry(t *testing.T) {
+	old := retryBaseDelay
+	retryBaseDelay = 20 * time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = old })
+}
+
+func onlineEnvelope(id uint64) event.Envelope {
+	return event.Envelope{
+		ID:   id,
+		Type: event.KindServiceOnline,
+		Time: time.Now(),
+		Data: event.ServiceOnline{
+			ServiceRef: event.ServiceRef{DeviceID: 1, ServiceID: 2, ServiceName: "ssh"},
+			PublicAddr: "1.2.3.4:5678",
+		},
+	}
+}
+
+func TestDeliverSignsBody(t *testing.T) {
+	r := newReceiver(t)
+	wh := Webhook{ID: 101, Spec: Spec{Name: "sign", URL: r.URL, Secret: "s3cret", Headers: map[string]string{"X-Custom": "1"}}}
+
+	d := deliver(wh, onlineEnvelope(7))
+	if d.Status != StatusSuccess || d.Attempts != 1 || d.StatusCode != http.StatusOK {
+		t.Fatalf("delivery = %+v", d)
+	}
+
+	req, body := r.requests[0], r.bodies[0]
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); req.Header.Get("X-LinkStar-Signature") != want {
+		t.Fatalf("signature = %q, want %q", req.Header.Get("X-LinkStar-Signature"), want)
+	}
+	if got := req.Header.Get("X-LinkStar-Event"); got != string(event.KindServiceOnline) {
+		t.Fatalf("X-LinkStar-Event = %q", got)
+	}
+	if got := req.Header.Get("X-LinkStar-Delivery"); got != strconv.FormatUint(d.ID, 10) {
+		t.Fatalf("X-LinkStar-Delivery = %q, want %d", got, d.ID)
+	}
+	if req.Header.Get("X-Custom") != "1" {
+		t.Fatal("custom header not sent")
+	}
+
+	var p struct {
+		Event   event.Kind `json:"event"`
+		EventID uint64     `json:"eventId"`
+		NewAddr string     `json:"newAddr"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if p.Event != event.KindServiceOnline || p.EventID != 7 || p.NewAddr != "1.2.3.4:5678" {
+		t.Fatalf("payload = %+v", p)
+	}
+	if string(body) != d.Request {
+		t.Fatal("delivery log should keep the request body")
+	}
+}
+
+func TestDeliverWithoutSecret(t *testing.T) {
+	r := newReceiver(t)
+	deliver(Webhook{ID: 102, Spec: Spec{URL: r.URL}}, onlineEnvelope(8))
+	if sig := r.requests[0].Header.Get("X-LinkStar-Signature"); sig != "" {
+		t.Fatalf("unsigned webhook sent signature %q", sig)
+	}
+}
+
+func TestDeliverRetries5xx(t *testing.T) {
+	fastRetry(t)
+	r := newReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	wh := Webhook{ID: 103, Spec: Spec{URL: r.URL, MaxRetries: 3}}
+
+	d := deliver(wh, onlineEnvelope(9))
+	if d.Status != StatusSuccess || d.Attempts != 3 || r.count() != 3 {
+		t.Fatalf("delivery = %+v, requests = %d", d, r.count())
+	}
+	// 指数退避：第 1 次重试前等 base，第 2 次等 2*base
+	if gap := r.times[1].Sub(r.times[0]); gap < retryBaseDelay {
+		t.Fatalf("first retry after %v, want >= %v", gap, retryBaseDelay)
+	}
+	if gap := r.times[2].Sub(r.times[1]); gap < 2*retryBaseDelay {
+		t.Fatalf("second retry after %v, want >= %v", gap, 2*retryBaseDelay)
+	}
+}
+
+func TestDeliverGivesUp(t *testing.T) {
+	fastRetry(t)
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		r := newReceiver(t, 502, 502, 502)
+		d := deliver(Webhook{ID: 104, Spec: Spec{URL: r.URL, MaxRetries: 2}}, onlineEnvelope(10))
+		if d.Status != StatusFailed || d.Attempts != 3 || d.StatusCode != 502 || d.Error == "" {
+			t.Fatalf("delivery = %+v", d)
+		}
+	})
+
+	t.Run("4xx not retried", func(t *testing.T) {
+		r := newReceiver(t, http.StatusBadRequest)
+		d := deliver(Webhook{ID: 105, Spec: Spec{URL: r.URL, MaxRetries: 3}}, onlineEnvelope(11))
+		if d.Status != StatusFailed || d.Attempts != 1 || r.count() != 1 {
+			t.Fatalf("delivery = %+v, requests = %d", d, r.count())
+		}
+	})
+
+	t.Run("429 retried", func(t *testing.T) {
+		r := newReceiver(t, http.StatusTooManyRequests)
+		d := deliver(Webhook{ID: 106, Spec: Spec{URL: r.URL, MaxRetries: 1}}, onlineEnvelope(12))
+		if d.Status != StatusSuccess || d.Attempts != 2 {
+			t.Fatalf("delivery = %+v", d)
+		}
+	})
+}
+
+func TestDeliveryLog(t *testing.T) {
+	fastRetry(t)
+	r := newReceiver(t, http.StatusInternalServerError)
+	wh := Webhook{ID: 107, Spec: Spec{URL: r.URL, MaxRetries: 1}}
+
+	first := deliver(wh, onlineEnvelope(20))
+	second := deliver(wh, onlineEnvelope(21))
+
+	list := ListDeliveries(wh.ID)
+	if len(list) != 2 {
+		t.Fatalf("got %d deliveries, want 2", len(list))
+	}
+	// 新的在前
+	if list[0].ID != second.ID || list[1].ID != first.ID {
+		t.Fatalf("order = %d, %d; want %d, %d", list[0].ID, list[1].ID, second.ID, first.ID)
+	}
+	got := list[1]
+	if got.EventID != 20 || got.Event != event.KindServiceOnline || got.URL != r.URL ||
+		got.Status != StatusSuccess || got.Attempts != 2 || got.StatusCode != http.StatusOK ||
+		got.Response != "OK" || got.FinishedAt.IsZero() {
+		t.Fatalf("delivery log entry = %+v", got)
+	}
+
+	if all := ListDeliveries(0); len(all) < 2 || all[0].ID != second.ID {
+		t.Fatalf("ListDeliveries(0) should include every webhook, newest first")
+	}
+}
+
+func TestSecretHiddenFromAPI(t *testing.T) {
+	t.Chdir(t.TempDir())
+	configMu.Lock()
+	config = Config{Webhooks: []*Webhook{}}
+	configMu.Unlock()
+
+	created, err := AddWebhook(Spec{Name: "a", URL: "http://127.0.0.1/", Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Secret != "" || !created.HasSecret {
+		t.Fatalf("created = %+v", created)
+	}
+	data, _ := json.Marshal(ListWebhooks())
+	if containsSecret(data) {
+		t.Fatalf("list leaks secret: %s", data)
+	}
+
+	// 修改时不传密钥则保留
+	updated, err := UpdateWebhook(created.ID, Spec{Name: "b", URL: "http://127.0.0.1/"}, false)
+	if err != nil || !updated.HasSecret {
+		t.Fatalf("updated = %+v, %v", updated, err)
+	}
+	if wh, _ := getWebhook(created.ID); wh.Secret != "s3cret" {
+		t.Fatalf("stored secret = %q", wh.Secret)
+	}
+
+	removed, err := UpdateWebhook(created.ID, Spec{Name: "b", URL: "http://127.0.0.1/"}, true)
+	if err != nil || removed.HasSecret {
+		t.Fatalf("removed = %+v, %v", removed, err)
+	}
+}
+
+func containsSecret(data []byte) bool {
+	var list []map[string]any
+	json.Unmarshal(data, &list)
+	for _, m := range list {
+		if _, ok := m["secret"]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"linkstar/modules/event"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Init 读取 webhook 配置并订阅事件总线
+func Init() {
+	c, err := readConfig()
+	if err != nil {
+		logrus.Errorf("读取 webhook 配置失败: %v", err)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+
+	event.Handle(256, dispatch)
+}
+
+// webhook 是否需要推送该事件
+func (wh *Webhook) match(env event.Envelope) bool {
+	if !wh.Enabled {
+		return false
+	}
+	if len(wh.Events) > 0 {
+		if !slices.Contains(wh.Events, env.Type) {
+			return false
+		}
+	} else if env.Type == event.KindConnectionAccepted {
+		// 连接事件过于频繁，需要显式订阅
+		return false
+	}
+
+	if wh.DeviceID == 0 && wh.ServiceID == 0 {
+		return true
+	}
+	ref := serviceRefOf(env.Data)
+	if ref == nil {
+		return false
+	}
+	return (wh.DeviceID == 0 || wh.DeviceID == ref.DeviceID) &&
+		(wh.ServiceID == 0 || wh.ServiceID == ref.ServiceID)
+}
+
+// 将事件分发给匹配的 webhook，每个投递单独一个 goroutine，重试不阻塞总线
+func dispatch(env event.Envelope) {
+	configMu.Lock()
+	var targets []Webhook
+	for _, wh := range config.Webhooks {
+		if wh.match(env) {
+			targets = append(targets, *wh)
+		}
+	}
+	configMu.Unlock()
+
+	for _, wh := range targets {
+		go deliver(wh, env)
+	}
+}
+
+// TestWebhook 立即发送一个测试事件并等待结果（不论是否启用，不重试）
+func TestWebhook(id uint) (Delivery, error) {
+	wh, err := getWebhook(id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	wh.MaxRetries = 0
+	env := event.Envelope{
+		Type: KindTest,
+		Time: time.Now(),
+		Data: TestEvent{Message: "LinkStar webhook 测试"},
+	}
+	return deliver(wh, env), nil
+}
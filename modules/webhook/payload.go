@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"linkstar/modules/event"
+	"net"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// Payload 默认请求体，也是请求体模板的数据
+type Payload struct {
+	Event     event.Kind      `json:"event"`             // 事件类型
+	EventID   uint64          `json:"eventId"`           // 事件ID
+	Time      time.Time       `json:"time"`              // 事件时间
+	Device    *PayloadDevice  `json:"device,omitempty"`  // 事件所属设备
+	Service   *PayloadService `json:"service,omitempty"` // 事件所属服务
+	State     string          `json:"state,omitempty"`   // 服务状态 online / offline
+	OldAddr   string          `json:"oldAddr"`           // 旧公网地址 IP:端口（公网IP变化时为IP）
+	NewAddr   string          `json:"newAddr"`           // 新公网地址 IP:端口（公网IP变化时为IP）
+	PublicURL string          `json:"publicURL"`         // 访问地址
+	Reason    string          `json:"reason"`            // 下线、漂移、映射失败、封禁的原因
+	Data      event.Event     `json:"data"`              // 原始事件
+}
+
+type PayloadDevice struct {
+	ID   uint   `json:"id"`   // 设备ID
+	Name string `json:"name"` // 设备名称
+	IP   string `json:"ip"`   // 设备内网IP
+}
+
+type PayloadService struct {
+	ID       uint   `json:"id"`       // 服务ID
+	Name     string `json:"name"`     // 服务名称
+	Protocol string `json:"protocol"` // 协议
+}
+
+// TestEvent 手动触发的测试事件
+type TestEvent struct {
+	Message string `json:"message"` // 说明
+}
+
+// KindTest 测试事件类型
+const KindTest event.Kind = "webhook.test"
+
+func (TestEvent) Kind() event.Kind { return KindTest }
+
+// 事件所属服务，非服务事件返回 nil
+func serviceRefOf(e event.Event) *event.ServiceRef {
+	switch e := e.(type) {
+	case event.ServiceOnline:
+		return &e.ServiceRef
+	case event.ServiceOffline:
+		return &e.ServiceRef
+	case event.PortDrift:
+		return &e.ServiceRef
+	case event.MappingAdded:
+		return &e.ServiceRef
+	case event.MappingRemoved:
+		return &e.ServiceRef
+	case event.MappingFailed:
+		return &e.ServiceRef
+	case event.ConnectionAccepted:
+		return &e.ServiceRef
+	case event.IPBanned:
+		return &e.ServiceRef
+	}
+	return nil
+}
+
+// 由事件生成请求体数据
+func buildPayload(env event.Envelope) Payload {
+	p := Payload{Event: env.Type, EventID: env.ID, Time: env.Time, Data: env.Data}
+
+	if ref := serviceRefOf(env.Data); ref != nil {
+		p.Device = &PayloadDevice{ID: ref.DeviceID, Name: ref.DeviceName, IP: ref.DeviceIP}
+		p.Service = &PayloadService{ID: ref.ServiceID, Name: ref.ServiceName, Protocol: ref.Protocol}
+	}
+
+	switch e := env.Data.(type) {
+	case event.ServiceOnline:
+		p.State = "online"
+		p.OldAddr, p.NewAddr, p.PublicURL = e.OldAddr, e.PublicAddr, e.PublicURL
+	case event.ServiceOffline:
+		p.State = "offline"
+		p.OldAddr, p.Reason = e.PublicAddr, e.Reason
+	case event.PortDrift:
+		p.OldAddr = net.JoinHostPort(e.PublicIP, strconv.Itoa(e.OldPort))
+		p.NewAddr = net.JoinHostPort(e.PublicIP, strconv.Itoa(e.NewPort))
+		p.Reason = "公网端口漂移"
+	case event.PublicIPChanged:
+		p.OldAddr, p.NewAddr = e.OldIP, e.NewIP
+	case event.MappingAdded:
+		p.NewAddr = strconv.Itoa(int(e.ExternalPort))
+	case event.MappingRemoved:
+		p.OldAddr = strconv.Itoa(int(e.ExternalPort))
+	case event.MappingFailed:
+		p.Reason = e.Error
+	case event.GatewayChanged:
+		p.OldAddr, p.NewAddr = e.OldGateway, e.NewGateway
+	case event.IPBanned:
+		p.NewAddr, p.Reason = e.IP, e.Reason
+	case TestEvent:
+		p.Reason = e.Message
+	}
+	return p
+}
+
+// 模板可用的函数，如 {{json .Data}}
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func checkTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, err := template.New("body").Funcs(templateFuncs).Parse(text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// 生成请求体：有模板时渲染模板，否则为 Payload 的 JSON
+func renderBody(text string, p Payload) ([]byte, error) {
+	if text == "" {
+		return json.Marshal(p)
+	}
+	tmpl, err := template.New("body").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("渲染请求体模板失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
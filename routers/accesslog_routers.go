@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/accesslog_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogRouters 访问日志查询接口，挂载在 /api/v2 下
+func AccessLogRouters(g *gin.RouterGroup) {
+	var app = api.App.AccessLogApi
+
+	g.GET(
+		"access-log",
+		middleware.BindV2QueryMiddleware[accesslog_api.AccessLogQueryRequest],
+		app.AccessLogQueryView,
+	)
+}
@@ -0,0 +1,47 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/acme_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AcmeRouters ACME 证书申请接口，挂载在 /api/v2 下
+func AcmeRouters(g *gin.RouterGroup) {
+	var app = api.App.AcmeApi
+
+	g.GET("acme/settings", app.SettingsGetView)
+	g.PUT(
+		"acme/settings",
+		middleware.BindV2Middleware[acme_api.SettingsUpdateRequest],
+		app.SettingsUpdateView,
+	)
+	g.GET("acme/orders", app.OrderListView)
+	g.POST(
+		"acme/orders",
+		middleware.BindV2Middleware[acme_api.OrderCreateRequest],
+		app.OrderCreateView,
+	)
+	g.GET(
+		"acme/orders/:id",
+		middleware.BindV2Middleware[acme_api.OrderUriRequest],
+		app.OrderGetView,
+	)
+	g.PUT(
+		"acme/orders/:id",
+		middleware.BindV2Middleware[acme_api.OrderUpdateRequest],
+		app.OrderUpdateView,
+	)
+	g.DELETE(
+		"acme/orders/:id",
+		middleware.BindV2Middleware[acme_api.OrderUriRequest],
+		app.OrderDeleteView,
+	)
+	g.POST(
+		"acme/orders/:id/issue",
+		middleware.BindV2Middleware[acme_api.OrderUriRequest],
+		app.OrderIssueView,
+	)
+}
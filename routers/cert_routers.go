@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/cert_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertRouters 证书管理接口，挂载在 /api/v2 下
+func CertRouters(g *gin.RouterGroup) {
+	var app = api.App.CertApi
+
+	g.GET("certs", app.CertListView)
+	g.POST(
+		"certs",
+		middleware.BindV2Middleware[cert_api.CertUploadRequest],
+		app.CertUploadView,
+	)
+	g.POST(
+		"certs/generate",
+		middleware.BindV2Middleware[cert_api.CertGenerateRequest],
+		app.CertGenerateView,
+	)
+	g.GET("certs/ca", app.CertCAView)
+	g.GET(
+		"certs/:id",
+		middleware.BindV2Middleware[cert_api.CertUriRequest],
+		app.CertGetView,
+	)
+	g.PUT(
+		"certs/:id",
+		middleware.BindV2Middleware[cert_api.CertReplaceRequest],
+		app.CertReplaceView,
+	)
+	g.DELETE(
+		"certs/:id",
+		middleware.BindV2Middleware[cert_api.CertUriRequest],
+		app.CertDeleteView,
+	)
+}
@@ -0,0 +1,67 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/ddns_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DdnsRouters DDNS 管理接口，挂载在 /api/v2 下
+func DdnsRouters(g *gin.RouterGroup) {
+	var app = api.App.DdnsApi
+
+	// DNS 服务商
+	g.GET("ddns/providers", app.ProviderListView)
+	g.POST(
+		"ddns/providers",
+		middleware.BindV2Middleware[ddns_api.ProviderCreateRequest],
+		app.ProviderCreateView,
+	)
+	g.GET(
+		"ddns/providers/:id",
+		middleware.BindV2Middleware[ddns_api.ProviderUriRequest],
+		app.ProviderGetView,
+	)
+	g.PUT(
+		"ddns/providers/:id",
+		middleware.BindV2Middleware[ddns_api.ProviderUpdateRequest],
+		app.ProviderUpdateView,
+	)
+	g.DELETE(
+		"ddns/providers/:id",
+		middleware.BindV2Middleware[ddns_api.ProviderUriRequest],
+		app.ProviderDeleteView,
+	)
+
+	// 域名
+	g.GET("ddns/domains", app.DomainListView)
+	g.POST(
+		"ddns/domains",
+		middleware.BindV2Middleware[ddns_api.DomainCreateRequest],
+		app.DomainCreateView,
+	)
+	g.GET(
+		"ddns/domains/:id",
+		middleware.BindV2Middleware[ddns_api.DomainUriRequest],
+		app.DomainGetView,
+	)
+	g.PUT(
+		"ddns/domains/:id",
+		middleware.BindV2Middleware[ddns_api.DomainUpdateRequest],
+		app.DomainUpdateView,
+	)
+	g.DELETE(
+		"ddns/domains/:id",
+		middleware.BindV2Middleware[ddns_api.DomainUriRequest],
+		app.DomainDeleteView,
+	)
+
+	// 立即同步
+	g.POST(
+		"ddns/domains/:id/sync",
+		middleware.BindV2Middleware[ddns_api.DomainUriRequest],
+		app.DomainSyncView,
+	)
+}
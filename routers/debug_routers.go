@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"linkstar/api"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DebugRouters(g *gin.RouterGroup) {
+	var app = api.App.DebugApi
+
+	// pprof，路径必须是 /debug/pprof/ 前缀，pprof.Index 按此前缀解析 profile 名
+	g.GET("pprof/", gin.WrapF(pprof.Index))
+	g.GET("pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	g.GET("pprof/profile", gin.WrapF(pprof.Profile))
+	g.GET("pprof/symbol", gin.WrapF(pprof.Symbol))
+	g.POST("pprof/symbol", gin.WrapF(pprof.Symbol))
+	g.GET("pprof/trace", gin.WrapF(pprof.Trace))
+	g.GET("pprof/:name", gin.WrapF(pprof.Index))
+
+	// 每个服务 key 下的 goroutine 数
+	g.GET("goroutines", app.GoroutineSummaryView)
+
+	// runningServices 中的服务
+	g.GET("services", app.RunningServicesView)
+
+	// UPnP 队列状态
+	g.GET("upnp", app.UpnpQueueView)
+
+	// STUN 套接字绑定
+	g.GET("bindings", app.StunBindingsView)
+}
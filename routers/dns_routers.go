@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"linkstar/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DnsRouters 内置权威 DNS 接口，挂载在 /api/v2 下
+func DnsRouters(g *gin.RouterGroup) {
+	var app = api.App.DnsApi
+
+	g.GET("dns/status", app.DnsStatusView)
+	g.GET("dns/records", app.DnsRecordListView)
+}
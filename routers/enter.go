@@ -2,8 +2,10 @@ package routers
 
 import (
 	"io/fs"
+	"linkstar/global"
+	"linkstar/middleware"
+	"linkstar/utils/validate"
 	"net/http"
-	_ "net/http/pprof" // 加下划线，只要副作用（自动注册路由）
 	"strings"
 	"time"
 
@@ -13,20 +15,55 @@ import (
 
 func Run(webFS fs.FS) {
 
-	// 单独起 pprof，只在排查问题时用
-	go func() {
-		logrus.Info("pprof 运行在：0.0.0.0:3334")
-		http.ListenAndServe("0.0.0.0:3334", nil)
-	}()
+	// 注册自定义校验规则
+	validate.Init()
 
 	gin.SetMode("release")
 	r := gin.Default()
 	r.RedirectTrailingSlash = false
 
 	// API 路由
-	g := r.Group("api")
+	g := r.Group("api", middleware.AuthMiddleware)
 	StunRouters(g)
 
+	// v2 接口，鉴权失败返回 401
+	v2 := r.Group("api/v2", middleware.AuthV2Middleware)
+	StunV2Routers(v2)
+	WebhookRouters(v2)
+	DdnsRouters(v2)
+	SrvRouters(v2)
+	DnsRouters(v2)
+	CertRouters(v2)
+	AcmeRouters(v2)
+	FirewallRouters(v2)
+	LimitRouters(v2)
+	AccessLogRouters(v2)
+	LogRouters(v2)
+
+	// 固定地址跳转：/go/<服务名>
+	RedirectRouters(r)
+	if global.Config.Redirect.Listen != "" {
+		go runRedirectServer()
+	}
+
+	// OpenAPI 文档：/api/openapi.json，Swagger UI：/api/docs
+	OpenAPIRouters(r)
+
+	// Prometheus 指标：/metrics，默认关闭
+	if global.Config.Metrics.Enable {
+		MetricsRouters(r)
+		logrus.Info("Prometheus 指标已开启：/metrics")
+	}
+
+	// 调试接口（pprof、运行时诊断），默认关闭，开启后必须携带 token 访问
+	if global.Config.Debug.Enable {
+		if global.Config.System.Token == "" {
+			logrus.Warn("已开启调试接口但未配置 token，调试接口将拒绝所有请求")
+		}
+		DebugRouters(r.Group("debug", middleware.StrictAuthMiddleware))
+		logrus.Info("调试接口已开启：/debug")
+	}
+
 	// 剥掉 web/dist 前缀
 	webFS, _ = fs.Sub(webFS, "web/dist")
 	// 所有非 API 请求：先找静态文件，找不到就返回 index.html（Vue Router 兜底）
@@ -49,10 +86,11 @@ func Run(webFS fs.FS) {
 		c.Data(200, "text/html; charset=utf-8", data)
 	})
 
-	logrus.Info("后端运行在：0.0.0.0:3333")
+	addr := global.Config.System.Addr
+	logrus.Infof("后端运行在：%s", addr)
 
 	srv := &http.Server{
-		Addr:        "0.0.0.0:3333",
+		Addr:        addr,
 		Handler:     r,
 		IdleTimeout: 60 * time.Second,
 	}
@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/firewall_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FirewallRouters 访问控制与自动封禁接口，挂载在 /api/v2 下
+func FirewallRouters(g *gin.RouterGroup) {
+	var app = api.App.FirewallApi
+
+	g.GET("firewall/settings", app.SettingsGetView)
+	g.PUT(
+		"firewall/settings",
+		middleware.BindV2Middleware[firewall_api.SettingsUpdateRequest],
+		app.SettingsUpdateView,
+	)
+	g.GET("firewall/bans", app.BanListView)
+	g.DELETE("firewall/bans", app.BanClearView)
+	g.DELETE(
+		"firewall/bans/:ip",
+		middleware.BindV2Middleware[firewall_api.BanUriRequest],
+		app.BanDeleteView,
+	)
+}
@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/limit_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitRouters 全局限制接口，挂载在 /api/v2 下
+func LimitRouters(g *gin.RouterGroup) {
+	var app = api.App.LimitApi
+
+	g.GET("limits", app.LimitsGetView)
+	g.PUT(
+		"limits",
+		middleware.BindV2Middleware[limit_api.LimitsUpdateRequest],
+		app.LimitsUpdateView,
+	)
+}
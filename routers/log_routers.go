@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/log_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogRouters 运行日志级别接口，挂载在 /api/v2 下
+func LogRouters(g *gin.RouterGroup) {
+	var app = api.App.LogApi
+
+	g.GET("log/level", app.LogLevelGetView)
+	g.PUT(
+		"log/level",
+		middleware.BindV2Middleware[log_api.LogLevelUpdateRequest],
+		app.LogLevelUpdateView,
+	)
+}
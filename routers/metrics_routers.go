@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/global"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsRouters Prometheus 指标，配置了 metrics.auth 时与 v2 接口使用相同的鉴权
+func MetricsRouters(r *gin.Engine) {
+	var app = api.App.MetricsApi
+
+	if global.Config.Metrics.Auth {
+		r.GET("metrics", middleware.AuthV2Middleware, app.MetricsView)
+		return
+	}
+	r.GET("metrics", app.MetricsView)
+}
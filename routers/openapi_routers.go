@@ -0,0 +1,362 @@
+package routers
+
+import (
+	"linkstar/api/accesslog_api"
+	"linkstar/api/acme_api"
+	"linkstar/api/cert_api"
+	"linkstar/api/ddns_api"
+	"linkstar/api/firewall_api"
+	"linkstar/api/limit_api"
+	"linkstar/api/log_api"
+	"linkstar/api/redirect_api"
+	"linkstar/api/srv_api"
+	"linkstar/api/stun_api"
+	"linkstar/api/stun_v2_api"
+	"linkstar/api/webhook_api"
+	"linkstar/core"
+	"linkstar/docs"
+	"linkstar/modules/accesslog"
+	"linkstar/modules/acme"
+	"linkstar/modules/certs"
+	"linkstar/modules/ddns"
+	"linkstar/modules/dnsserver"
+	"linkstar/modules/event"
+	"linkstar/modules/firewall"
+	"linkstar/modules/limiter"
+	"linkstar/modules/redirect"
+	"linkstar/modules/srv"
+	"linkstar/modules/stun"
+	"linkstar/modules/stun/model"
+	"linkstar/modules/traffic"
+	"linkstar/modules/webhook"
+	"linkstar/utils/res"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiDocs 接口文档，key 与路由表一致："METHOD 完整路径"
+var apiDocs = map[string]docs.Operation{
+	"GET /api/openapi.json": {Summary: "OpenAPI 3 文档", Tag: "docs"},
+	"GET /api/docs":         {Summary: "Swagger UI 页面", Tag: "docs"},
+
+	// v1
+	"GET /api/stun/config": {Summary: "获取全部的stun配置", Response: model.StunConfig{}},
+	"GET /api/stun/events": {
+		Summary: "推送服务状态变化（SSE），首个事件为 snapshot 全量快照，重连时按 Last-Event-ID 续传",
+		Request: stun_api.StunEventsViewRequest{}, Response: event.Envelope{}, Stream: true,
+	},
+	"POST /api/stun/service/add": {
+		Summary: "新增服务", Request: stun_api.StunServiceAddViewRequest{}, Response: model.Service{},
+	},
+	"POST /api/stun/device/add": {
+		Summary: "新增设备", Request: stun_api.StunDeviceAddViewRequest{}, Response: model.Device{},
+	},
+	"PUT /api/stun/service/update": {
+		Summary: "修改服务（会重启该服务）", Request: stun_api.StunServiceUpdateViewRequest{}, Response: model.Service{},
+	},
+	"DELETE /api/stun/service/delete": {
+		Summary: "删除服务", Request: stun_api.StunServiceDeleteViewRequest{},
+	},
+	"POST /api/stun/service/action": {
+		Summary: "服务启动/停止/重启/重新打洞", Request: stun_api.StunServiceActionViewRequest{}, Response: model.Service{},
+	},
+	"POST /api/stun/device/action": {
+		Summary: "对设备下所有服务执行同一操作", Request: stun_api.StunDeviceActionViewRequest{},
+		Response: stun.ActionResult{}, List: true,
+	},
+	"POST /api/stun/service/action/bulk": {
+		Summary: "批量操作服务", Request: stun_api.StunServiceBulkActionViewRequest{},
+		Response: stun.ActionResult{}, List: true,
+	},
+	"DELETE /api/stun/device/delete": {
+		Summary: "删除设备及其所有服务", Request: stun_api.StunDeviceDeleteViewRequest{},
+	},
+	"PUT /api/stun/device/update": {
+		Summary: "修改设备（IP 变化时重启其服务）", Request: stun_api.StunDeviceUpdateViewRequest{}, Response: model.Device{},
+	},
+
+	// v2
+	"GET /api/v2/config":      {Summary: "获取全部的stun配置", Response: model.StunConfig{}, V2: true},
+	"GET /api/v2/connections": {Summary: "所有服务正在转发的连接", Response: stun.ActiveConn{}, List: true, V2: true},
+	"DELETE /api/v2/connections/ip/:ip": {
+		Summary: "断开来自该地址的全部连接", Request: stun_v2_api.ConnIPRequest{}, Response: stun_v2_api.ConnKillResponse{}, V2: true,
+	},
+	"GET /api/v2/traffic":    {Summary: "全部服务的累计流量", Response: traffic.Stats{}, List: true, V2: true},
+	"DELETE /api/v2/traffic": {Summary: "清空全部服务的流量统计", Status: http.StatusNoContent, V2: true},
+	"GET /api/v2/devices":    {Summary: "设备列表", Response: model.Device{}, List: true, V2: true},
+	"POST /api/v2/devices": {
+		Summary: "新增设备", Request: stun_v2_api.DeviceCreateRequest{}, Response: model.Device{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/devices/:id": {
+		Summary: "设备详情", Request: stun_v2_api.DeviceUriRequest{}, Response: model.Device{}, V2: true,
+	},
+	"PUT /api/v2/devices/:id": {
+		Summary: "修改设备（IP 变化时重启其服务）", Request: stun_v2_api.DeviceUpdateRequest{}, Response: model.Device{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id": {
+		Summary: "删除设备及其所有服务", Request: stun_v2_api.DeviceUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"GET /api/v2/devices/:id/services": {
+		Summary: "设备下的服务列表", Request: stun_v2_api.DeviceUriRequest{}, Response: model.Service{}, List: true, V2: true,
+	},
+	"POST /api/v2/devices/:id/services": {
+		Summary: "新增服务", Request: stun_v2_api.ServiceCreateRequest{}, Response: stun_v2_api.ServiceResponse{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/devices/:id/services/:sid": {
+		Summary: "服务详情", Request: stun_v2_api.ServiceUriRequest{}, Response: model.Service{}, V2: true,
+	},
+	"PUT /api/v2/devices/:id/services/:sid": {
+		Summary: "修改服务（会重启该服务）", Request: stun_v2_api.ServiceUpdateRequest{}, Response: stun_v2_api.ServiceResponse{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid": {
+		Summary: "删除服务", Request: stun_v2_api.ServiceUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"GET /api/v2/devices/:id/services/:sid/routes": {
+		Summary: "http-proxy / sni-proxy 服务的路由列表", Request: stun_v2_api.ServiceUriRequest{}, Response: model.ProxyRoute{},
+		List: true, V2: true,
+	},
+	"POST /api/v2/devices/:id/services/:sid/routes": {
+		Summary: "新增路由（立即生效，不重新打洞）", Request: stun_v2_api.RouteCreateRequest{}, Response: model.ProxyRoute{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"PUT /api/v2/devices/:id/services/:sid/routes/:rid": {
+		Summary: "修改路由", Request: stun_v2_api.RouteUpdateRequest{}, Response: model.ProxyRoute{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid/routes/:rid": {
+		Summary: "删除路由", Request: stun_v2_api.RouteUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"GET /api/v2/devices/:id/services/:sid/limits": {
+		Summary: "服务的连接数与带宽限制及当前并发连接数", Request: stun_v2_api.ServiceUriRequest{},
+		Response: limiter.Status{}, V2: true,
+	},
+	"PUT /api/v2/devices/:id/services/:sid/limits": {
+		Summary: "修改服务的限制（立即生效，不重新打洞）", Request: stun_v2_api.ServiceLimitsUpdateRequest{},
+		Response: limiter.Status{}, V2: true,
+	},
+	"GET /api/v2/devices/:id/services/:sid/traffic": {
+		Summary: "服务的累计及每日流量（保留 90 天）", Request: stun_v2_api.ServiceUriRequest{},
+		Response: traffic.Stats{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid/traffic": {
+		Summary: "清空服务的流量统计", Request: stun_v2_api.ServiceUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"GET /api/v2/devices/:id/services/:sid/connections": {
+		Summary: "服务正在转发的连接", Request: stun_v2_api.ServiceUriRequest{}, Response: stun.ActiveConn{},
+		List: true, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid/connections": {
+		Summary: "断开服务的全部连接", Request: stun_v2_api.ServiceUriRequest{},
+		Response: stun_v2_api.ConnKillResponse{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid/connections/:cid": {
+		Summary: "断开一个连接", Request: stun_v2_api.ConnUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"POST /api/v2/devices/:id/services/:sid/actions/:action": {
+		Summary: "服务启动/停止/重启/重新打洞", Request: stun_v2_api.ServiceActionRequest{}, Response: model.Service{}, V2: true,
+	},
+	"POST /api/v2/devices/:id/actions/:action": {
+		Summary: "对设备下所有服务执行同一操作", Request: stun_v2_api.DeviceActionRequest{},
+		Response: stun.ActionResult{}, List: true, V2: true,
+	},
+	"POST /api/v2/services/actions/:action": {
+		Summary: "批量操作服务", Request: stun_v2_api.BulkActionRequest{},
+		Response: stun.ActionResult{}, List: true, V2: true,
+	},
+
+	// webhook
+	"GET /api/v2/webhooks": {Summary: "webhook 列表", Response: webhook.Info{}, List: true, V2: true},
+	"POST /api/v2/webhooks": {
+		Summary: "新增 webhook", Request: webhook_api.WebhookCreateRequest{}, Response: webhook.Info{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/webhooks/:id": {
+		Summary: "webhook 详情", Request: webhook_api.WebhookUriRequest{}, Response: webhook.Info{}, V2: true,
+	},
+	"PUT /api/v2/webhooks/:id": {
+		Summary: "修改 webhook", Request: webhook_api.WebhookUpdateRequest{}, Response: webhook.Info{}, V2: true,
+	},
+	"DELETE /api/v2/webhooks/:id": {
+		Summary: "删除 webhook", Request: webhook_api.WebhookUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	"POST /api/v2/webhooks/:id/test": {
+		Summary: "发送测试事件（不重试），返回投递结果", Request: webhook_api.WebhookUriRequest{},
+		Response: webhook.Delivery{}, V2: true,
+	},
+	"GET /api/v2/webhooks/:id/deliveries": {
+		Summary: "最近的投递记录，新的在前", Request: webhook_api.WebhookUriRequest{},
+		Response: webhook.Delivery{}, List: true, V2: true,
+	},
+
+	// ddns
+	"GET /api/v2/ddns/providers": {Summary: "DNS 服务商列表", Response: ddns.ProviderConfig{}, List: true, V2: true},
+	"POST /api/v2/ddns/providers": {
+		Summary: "新增 DNS 服务商", Request: ddns_api.ProviderCreateRequest{}, Response: ddns.ProviderConfig{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/ddns/providers/:id": {
+		Summary: "DNS 服务商详情", Request: ddns_api.ProviderUriRequest{}, Response: ddns.ProviderConfig{}, V2: true,
+	},
+	"PUT /api/v2/ddns/providers/:id": {
+		Summary: "修改 DNS 服务商，使用它的域名会重新同步", Request: ddns_api.ProviderUpdateRequest{},
+		Response: ddns.ProviderConfig{}, V2: true,
+	},
+	"DELETE /api/v2/ddns/providers/:id": {
+		Summary: "删除 DNS 服务商（仍被使用时返回 409）", Request: ddns_api.ProviderUriRequest{},
+		Status: http.StatusNoContent, V2: true,
+	},
+	"GET /api/v2/ddns/domains": {Summary: "DDNS 域名列表（含同步状态）", Response: ddns.DomainInfo{}, List: true, V2: true},
+	"POST /api/v2/ddns/domains": {
+		Summary: "新增 DDNS 域名", Request: ddns_api.DomainCreateRequest{}, Response: ddns.DomainInfo{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/ddns/domains/:id": {
+		Summary: "DDNS 域名详情", Request: ddns_api.DomainUriRequest{}, Response: ddns.DomainInfo{}, V2: true,
+	},
+	"PUT /api/v2/ddns/domains/:id": {
+		Summary: "修改 DDNS 域名", Request: ddns_api.DomainUpdateRequest{}, Response: ddns.DomainInfo{}, V2: true,
+	},
+	"DELETE /api/v2/ddns/domains/:id": {
+		Summary: "删除 DDNS 域名（DNS 上的记录保留）", Request: ddns_api.DomainUriRequest{},
+		Status: http.StatusNoContent, V2: true,
+	},
+	"POST /api/v2/ddns/domains/:id/sync": {
+		Summary: "立即同步 DDNS 域名（不受最小间隔限制）", Request: ddns_api.DomainUriRequest{},
+		Response: ddns.DomainInfo{}, V2: true,
+	},
+
+	// srv
+	"GET /api/v2/srv/records": {Summary: "SRV 记录列表（含发布状态）", Response: srv.RecordInfo{}, List: true, V2: true},
+	"GET /api/v2/devices/:id/services/:sid/srv": {
+		Summary: "服务的 SRV 记录", Request: srv_api.SrvUriRequest{}, Response: srv.RecordInfo{}, V2: true,
+	},
+	"PUT /api/v2/devices/:id/services/:sid/srv": {
+		Summary: "设置服务的 SRV 记录（不存在时新增），端点变化时自动更新", Request: srv_api.SrvSetRequest{},
+		Response: srv.RecordInfo{}, V2: true,
+	},
+	"DELETE /api/v2/devices/:id/services/:sid/srv": {
+		Summary: "删除服务的 SRV 配置及已发布的记录", Request: srv_api.SrvUriRequest{},
+		Status: http.StatusNoContent, V2: true,
+	},
+	"POST /api/v2/devices/:id/services/:sid/srv/sync": {
+		Summary: "立即重新发布服务的 SRV 记录", Request: srv_api.SrvUriRequest{}, Response: srv.RecordInfo{}, V2: true,
+	},
+
+	// dns
+	"GET /api/v2/dns/status": {Summary: "内置 DNS 运行状态与查询统计", Response: dnsserver.Status{}, V2: true},
+	"GET /api/v2/dns/records": {
+		Summary: "内置 DNS 当前可应答的全部记录（未开启时返回 409）", Response: dnsserver.Record{}, List: true, V2: true,
+	},
+
+	// 固定地址跳转，配置了 shareToken 时需携带 ?token=
+	"GET /metrics": {Summary: "Prometheus 指标（文本格式，需在配置中开启）", Tag: "metrics", V2: true},
+	"GET /go":      {Summary: "全部在线服务及其访问地址", Tag: "redirect", Response: redirect.Target{}, List: true, V2: true},
+	"GET /go/:name": {
+		Summary: "302 跳转到服务当前的访问地址（不在线返回 503）", Tag: "redirect", Request: redirect_api.GoRequest{},
+		Status: http.StatusFound, V2: true,
+	},
+	"GET /go/:name/info": {
+		Summary: "查询服务当前的访问地址（供脚本使用）", Tag: "redirect", Request: redirect_api.GoRequest{},
+		Response: redirect.Target{}, V2: true,
+	},
+
+	// 证书
+	"GET /api/v2/certs": {Summary: "证书列表", Response: certs.Cert{}, List: true, V2: true},
+	"POST /api/v2/certs": {
+		Summary: "上传证书", Request: cert_api.CertUploadRequest{}, Response: certs.Cert{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"POST /api/v2/certs/generate": {
+		Summary: "生成自签名证书或由本地 CA 签发", Request: cert_api.CertGenerateRequest{}, Response: certs.Cert{},
+		Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/certs/ca": {Summary: "下载本地 CA 证书（PEM，不存在时生成）", V2: true},
+	"GET /api/v2/certs/:id": {
+		Summary: "证书详情", Request: cert_api.CertUriRequest{}, Response: certs.Cert{}, V2: true,
+	},
+	"PUT /api/v2/certs/:id": {
+		Summary: "替换证书内容，使用它的服务在下次握手时生效", Request: cert_api.CertReplaceRequest{},
+		Response: certs.Cert{}, V2: true,
+	},
+	"DELETE /api/v2/certs/:id": {
+		Summary: "删除证书（仍被服务使用时返回 409）", Request: cert_api.CertUriRequest{},
+		Status: http.StatusNoContent, V2: true,
+	},
+	// ACME 证书申请（DNS-01）
+	"GET /api/v2/acme/settings": {Summary: "ACME 账户与 CA 设置", Response: acme.Settings{}, V2: true},
+	"PUT /api/v2/acme/settings": {
+		Summary: "修改 ACME 账户与 CA 设置", Request: acme_api.SettingsUpdateRequest{}, Response: acme.Settings{}, V2: true,
+	},
+	"GET /api/v2/acme/orders": {Summary: "证书申请列表", Response: acme.OrderInfo{}, List: true, V2: true},
+	"POST /api/v2/acme/orders": {
+		Summary: "新增证书申请（启用时在后台立即申请）", Request: acme_api.OrderCreateRequest{},
+		Response: acme.OrderInfo{}, Status: http.StatusCreated, V2: true,
+	},
+	"GET /api/v2/acme/orders/:id": {
+		Summary: "证书申请详情及状态", Request: acme_api.OrderUriRequest{}, Response: acme.OrderInfo{}, V2: true,
+	},
+	"PUT /api/v2/acme/orders/:id": {
+		Summary: "修改证书申请", Request: acme_api.OrderUpdateRequest{}, Response: acme.OrderInfo{}, V2: true,
+	},
+	"DELETE /api/v2/acme/orders/:id": {
+		Summary: "删除证书申请（已签发的证书保留）", Request: acme_api.OrderUriRequest{},
+		Status: http.StatusNoContent, V2: true,
+	},
+	"POST /api/v2/acme/orders/:id/issue": {
+		Summary: "立即申请或续期（后台执行，申请中返回 409）", Request: acme_api.OrderUriRequest{},
+		Response: acme.OrderInfo{}, Status: http.StatusAccepted, V2: true,
+	},
+	// 访问控制与自动封禁
+	"GET /api/v2/firewall/settings": {Summary: "全局允许、禁止列表与自动封禁设置", Response: firewall.Settings{}, V2: true},
+	"PUT /api/v2/firewall/settings": {
+		Summary: "修改全局访问控制设置（立即对新连接生效）", Request: firewall_api.SettingsUpdateRequest{},
+		Response: firewall.Settings{}, V2: true,
+	},
+	"GET /api/v2/firewall/bans": {Summary: "当前生效的封禁", Response: firewall.Ban{}, List: true, V2: true},
+	"DELETE /api/v2/firewall/bans": {
+		Summary: "解除全部封禁", Response: firewall_api.BanClearResponse{}, V2: true,
+	},
+	"DELETE /api/v2/firewall/bans/:ip": {
+		Summary: "解除单个地址的封禁", Request: firewall_api.BanUriRequest{}, Status: http.StatusNoContent, V2: true,
+	},
+	// 全局限制
+	"GET /api/v2/limits": {Summary: "所有服务合计的连接数与带宽限制及当前并发连接数", Response: limiter.Status{}, V2: true},
+	"PUT /api/v2/limits": {
+		Summary: "修改全局限制（立即生效，不重启服务）", Request: limit_api.LimitsUpdateRequest{}, Response: limiter.Status{}, V2: true,
+	},
+	// 访问日志
+	"GET /api/v2/access-log": {
+		Summary: "按服务、客户端 IP 和时间范围查询访问日志（按关闭时间倒序）", Request: accesslog_api.AccessLogQueryRequest{},
+		Response: accesslog.Record{}, List: true, V2: true,
+	},
+	// 运行日志
+	"GET /api/v2/log/level": {Summary: "当前控制台与日志文件级别", Response: core.LogLevels{}, V2: true},
+	"PUT /api/v2/log/level": {
+		Summary: "修改日志级别（立即生效，不写回配置文件）", Request: log_api.LogLevelUpdateRequest{},
+		Response: core.LogLevels{}, V2: true,
+	},
+}
+
+// OpenAPIRouters OpenAPI 文档和 Swagger UI，不需要鉴权
+func OpenAPIRouters(r *gin.Engine) {
+	var (
+		once sync.Once
+		spec map[string]any
+	)
+
+	// 路由表在启动后不再变化，首次访问时生成
+	r.GET("api/openapi.json", func(c *gin.Context) {
+		once.Do(func() {
+			spec = docs.Build(r.Routes(), apiDocs, res.ErrorResponse{})
+		})
+		c.JSON(http.StatusOK, spec)
+	})
+
+	r.GET("api/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", docs.SwaggerHTML)
+	})
+	r.StaticFS("api/docs/assets", http.FS(docs.SwaggerAssets))
+}
@@ -0,0 +1,49 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/redirect_api"
+	"linkstar/global"
+	"linkstar/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RedirectRouters 固定地址跳转，挂载在面板根路径下，配置了独立监听时也挂载在独立监听上
+func RedirectRouters(r gin.IRouter) {
+	var app = api.App.RedirectApi
+
+	g := r.Group("go", middleware.ShareAuthMiddleware)
+	g.GET("", app.GoListView)
+	g.GET(
+		":name",
+		middleware.BindV2QueryMiddleware[redirect_api.GoRequest],
+		app.GoRedirectView,
+	)
+	g.GET(
+		":name/info",
+		middleware.BindV2QueryMiddleware[redirect_api.GoRequest],
+		app.GoInfoView,
+	)
+}
+
+// 独立监听，只提供 /go
+func runRedirectServer() {
+	addr := global.Config.Redirect.Listen
+	r := gin.New()
+	r.Use(gin.Recovery())
+	RedirectRouters(r)
+
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     r,
+		IdleTimeout: 60 * time.Second,
+	}
+	logrus.Infof("跳转服务运行在：%s", addr)
+	if err := srv.ListenAndServe(); err != nil {
+		logrus.Errorf("跳转服务启动失败：%v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/srv_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SrvRouters SRV 记录管理接口，挂载在 /api/v2 下
+func SrvRouters(g *gin.RouterGroup) {
+	var app = api.App.SrvApi
+
+	g.GET("srv/records", app.SrvListView)
+
+	// 每个服务一条 SRV 配置
+	g.GET(
+		"devices/:id/services/:sid/srv",
+		middleware.BindV2Middleware[srv_api.SrvUriRequest],
+		app.SrvGetView,
+	)
+	g.PUT(
+		"devices/:id/services/:sid/srv",
+		middleware.BindV2Middleware[srv_api.SrvSetRequest],
+		app.SrvSetView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid/srv",
+		middleware.BindV2Middleware[srv_api.SrvUriRequest],
+		app.SrvDeleteView,
+	)
+
+	// 立即重新发布
+	g.POST(
+		"devices/:id/services/:sid/srv/sync",
+		middleware.BindV2Middleware[srv_api.SrvUriRequest],
+		app.SrvSyncView,
+	)
+}
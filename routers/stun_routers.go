@@ -17,6 +17,13 @@ func StunRouters(g *gin.RouterGroup) {
 		app.GetStunConfigView,
 	)
 
+	// 推送服务状态变化（SSE），EventSource 无法设置请求头，可通过 ?token= 鉴权
+	g.GET(
+		"stun/events",
+		middleware.BindQueryMiddleware[stun_api.StunEventsViewRequest],
+		app.StunEventsView,
+	)
+
 	// 新增服务
 	g.POST(
 		"stun/service/add",
@@ -59,4 +66,25 @@ func StunRouters(g *gin.RouterGroup) {
 		app.StunDeviceUpdateView,
 	)
 
+	// 服务启动/停止/重启/重新打洞
+	g.POST(
+		"stun/service/action",
+		middleware.BindJsonMiddleware[stun_api.StunServiceActionViewRequest],
+		app.StunServiceActionView,
+	)
+
+	// 设备下所有服务执行同一操作
+	g.POST(
+		"stun/device/action",
+		middleware.BindJsonMiddleware[stun_api.StunDeviceActionViewRequest],
+		app.StunDeviceActionView,
+	)
+
+	// 批量操作服务
+	g.POST(
+		"stun/service/action/bulk",
+		middleware.BindJsonMiddleware[stun_api.StunServiceBulkActionViewRequest],
+		app.StunServiceBulkActionView,
+	)
+
 }
@@ -0,0 +1,159 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/stun_v2_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StunV2Routers 资源风格的 v2 接口，挂载在 /api/v2 下
+func StunV2Routers(g *gin.RouterGroup) {
+	var app = api.App.StunV2Api
+
+	// 当前stun配置
+	g.GET("config", app.ConfigView)
+
+	// 全部服务的流量统计
+	g.GET("traffic", app.TrafficListView)
+	g.DELETE("traffic", app.TrafficResetView)
+
+	// 全部服务正在转发的连接
+	g.GET("connections", app.ConnListAllView)
+	g.DELETE(
+		"connections/ip/:ip",
+		middleware.BindV2Middleware[stun_v2_api.ConnIPRequest],
+		app.ConnKillFromView,
+	)
+
+	// 设备
+	g.GET("devices", app.DeviceListView)
+	g.POST(
+		"devices",
+		middleware.BindV2Middleware[stun_v2_api.DeviceCreateRequest],
+		app.DeviceCreateView,
+	)
+	g.GET(
+		"devices/:id",
+		middleware.BindV2Middleware[stun_v2_api.DeviceUriRequest],
+		app.DeviceGetView,
+	)
+	g.PUT(
+		"devices/:id",
+		middleware.BindV2Middleware[stun_v2_api.DeviceUpdateRequest],
+		app.DeviceUpdateView,
+	)
+	g.DELETE(
+		"devices/:id",
+		middleware.BindV2Middleware[stun_v2_api.DeviceUriRequest],
+		app.DeviceDeleteView,
+	)
+
+	// 服务
+	g.GET(
+		"devices/:id/services",
+		middleware.BindV2Middleware[stun_v2_api.DeviceUriRequest],
+		app.ServiceListView,
+	)
+	g.POST(
+		"devices/:id/services",
+		middleware.BindV2Middleware[stun_v2_api.ServiceCreateRequest],
+		app.ServiceCreateView,
+	)
+	g.GET(
+		"devices/:id/services/:sid",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ServiceGetView,
+	)
+	g.PUT(
+		"devices/:id/services/:sid",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUpdateRequest],
+		app.ServiceUpdateView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ServiceDeleteView,
+	)
+
+	// http-proxy 服务的路由
+	g.GET(
+		"devices/:id/services/:sid/routes",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.RouteListView,
+	)
+	g.POST(
+		"devices/:id/services/:sid/routes",
+		middleware.BindV2Middleware[stun_v2_api.RouteCreateRequest],
+		app.RouteCreateView,
+	)
+	g.PUT(
+		"devices/:id/services/:sid/routes/:rid",
+		middleware.BindV2Middleware[stun_v2_api.RouteUpdateRequest],
+		app.RouteUpdateView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid/routes/:rid",
+		middleware.BindV2Middleware[stun_v2_api.RouteUriRequest],
+		app.RouteDeleteView,
+	)
+
+	// 连接数与带宽限制
+	g.GET(
+		"devices/:id/services/:sid/limits",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ServiceLimitsGetView,
+	)
+	g.PUT(
+		"devices/:id/services/:sid/limits",
+		middleware.BindV2Middleware[stun_v2_api.ServiceLimitsUpdateRequest],
+		app.ServiceLimitsUpdateView,
+	)
+
+	// 流量统计
+	g.GET(
+		"devices/:id/services/:sid/traffic",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ServiceTrafficGetView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid/traffic",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ServiceTrafficResetView,
+	)
+
+	// 正在转发的连接
+	g.GET(
+		"devices/:id/services/:sid/connections",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ConnListView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid/connections",
+		middleware.BindV2Middleware[stun_v2_api.ServiceUriRequest],
+		app.ConnKillServiceView,
+	)
+	g.DELETE(
+		"devices/:id/services/:sid/connections/:cid",
+		middleware.BindV2Middleware[stun_v2_api.ConnUriRequest],
+		app.ConnKillView,
+	)
+
+	// 生命周期操作：start / stop / restart / repunch
+	g.POST(
+		"devices/:id/services/:sid/actions/:action",
+		middleware.BindV2Middleware[stun_v2_api.ServiceActionRequest],
+		app.ServiceActionView,
+	)
+	g.POST(
+		"devices/:id/actions/:action",
+		middleware.BindV2Middleware[stun_v2_api.DeviceActionRequest],
+		app.DeviceActionView,
+	)
+	g.POST(
+		"services/actions/:action",
+		middleware.BindV2Middleware[stun_v2_api.BulkActionRequest],
+		app.BulkActionView,
+	)
+}
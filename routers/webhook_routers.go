@@ -0,0 +1,50 @@
+package routers
+
+import (
+	"linkstar/api"
+	"linkstar/api/webhook_api"
+	"linkstar/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRouters webhook 管理接口，挂载在 /api/v2 下
+func WebhookRouters(g *gin.RouterGroup) {
+	var app = api.App.WebhookApi
+
+	g.GET("webhooks", app.WebhookListView)
+	g.POST(
+		"webhooks",
+		middleware.BindV2Middleware[webhook_api.WebhookCreateRequest],
+		app.WebhookCreateView,
+	)
+	g.GET(
+		"webhooks/:id",
+		middleware.BindV2Middleware[webhook_api.WebhookUriRequest],
+		app.WebhookGetView,
+	)
+	g.PUT(
+		"webhooks/:id",
+		middleware.BindV2Middleware[webhook_api.WebhookUpdateRequest],
+		app.WebhookUpdateView,
+	)
+	g.DELETE(
+		"webhooks/:id",
+		middleware.BindV2Middleware[webhook_api.WebhookUriRequest],
+		app.WebhookDeleteView,
+	)
+
+	// 发送测试事件
+	g.POST(
+		"webhooks/:id/test",
+		middleware.BindV2Middleware[webhook_api.WebhookUriRequest],
+		app.WebhookTestView,
+	)
+
+	// 投递记录
+	g.GET(
+		"webhooks/:id/deliveries",
+		middleware.BindV2Middleware[webhook_api.WebhookUriRequest],
+		app.WebhookDeliveryListView,
+	)
+}
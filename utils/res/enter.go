@@ -44,6 +44,15 @@ func FailWithMsg(msg string, c *gin.Context) {
 	Fail(7, msg, c)
 }
 
+// FailWithDetails 失败并附带详细信息，如字段级校验错误
+func FailWithDetails(msg string, details any, c *gin.Context) {
+	c.JSON(200, Response{
+		Code: 7,
+		Data: details,
+		Msg:  msg,
+	})
+}
+
 func FailWithError(err error, c *gin.Context) {
 	msg := err.Error()
 	Fail(7, msg, c)
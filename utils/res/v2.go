@@ -0,0 +1,66 @@
+package res
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v2 接口机器可读的错误码
+const (
+	ErrCodeInvalidRequest  = "INVALID_REQUEST"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeDeviceNotFound  = "DEVICE_NOT_FOUND"
+	ErrCodeServiceNotFound = "SERVICE_NOT_FOUND"
+	ErrCodeNotFound        = "NOT_FOUND"
+	ErrCodeConflict        = "CONFLICT"
+	ErrCodeInvalidState    = "INVALID_STATE"
+	ErrCodeInternal        = "INTERNAL_ERROR"
+)
+
+// ErrorResponse v2 接口错误响应
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code"`              // 机器可读错误码，如 DEVICE_NOT_FOUND
+	Message string `json:"message"`           // 错误描述
+	Details any    `json:"details,omitempty"` // 附加信息
+}
+
+// ListResponse v2 接口列表响应
+type ListResponse struct {
+	List  any   `json:"list"`
+	Count int64 `json:"count"`
+}
+
+// JSON v2 接口按真实 HTTP 状态码返回资源
+func JSON(status int, data any, c *gin.Context) {
+	c.JSON(status, data)
+}
+
+// Created 201 资源已创建
+func Created(data any, c *gin.Context) {
+	c.JSON(http.StatusCreated, data)
+}
+
+// NoContent 204 无返回内容
+func NoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// List 200 返回列表
+func List(list any, count int64, c *gin.Context) {
+	c.JSON(http.StatusOK, ListResponse{List: list, Count: count})
+}
+
+// Error v2 接口错误响应
+func Error(status int, code, msg string, c *gin.Context) {
+	c.JSON(status, ErrorResponse{Error: ErrorBody{Code: code, Message: msg}})
+}
+
+// ErrorWithDetails v2 接口错误响应，附带详细信息
+func ErrorWithDetails(status int, code, msg string, details any, c *gin.Context) {
+	c.JSON(status, ErrorResponse{Error: ErrorBody{Code: code, Message: msg, Details: details}})
+}
@@ -0,0 +1,114 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名（json 名称）
+	Rule    string `json:"rule"`    // 未通过的规则，如 required / min / ip
+	Message string `json:"message"` // 错误描述
+}
+
+// Errors 一组字段校验错误
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Message)
+	}
+	return strings.Join(msgs, "；")
+}
+
+// Init 注册自定义校验规则，字段名使用 json / uri 标签名
+func Init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "uri", "form"} {
+			name := strings.Split(f.Tag.Get(tag), ",")[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	// 传输协议，不区分大小写
+	_ = v.RegisterValidation("protocol", func(fl validator.FieldLevel) bool {
+		switch strings.ToUpper(fl.Field().String()) {
+		case "TCP", "UDP":
+			return true
+		}
+		return false
+	})
+}
+
+// Translate 将绑定/校验错误转换为字段级错误
+func Translate(err error) Errors {
+	var errs Errors
+
+	var ves validator.ValidationErrors
+	if errors.As(err, &ves) {
+		for _, fe := range ves {
+			errs = append(errs, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: message(fe),
+			})
+		}
+		return errs
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return Errors{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s 类型错误，应为 %s", typeErr.Field, typeErr.Type),
+		}}
+	}
+
+	return Errors{{Rule: "format", Message: err.Error()}}
+}
+
+// 按规则生成中文错误描述
+func message(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required":
+		return field + " 不能为空"
+	case "min":
+		return fmt.Sprintf("%s 不能小于 %s", field, fe.Param())
+	case "max":
+		return fmt.Sprintf("%s 不能大于 %s", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s 必须是 [%s] 之一", field, fe.Param())
+	case "ip", "ipv4":
+		return field + " 不是合法的 IP 地址"
+	case "url":
+		return field + " 不是合法的 URL"
+	case "fqdn":
+		return field + " 不是合法的域名"
+	case "startswith":
+		return fmt.Sprintf("%s 必须以 %s 开头", field, fe.Param())
+	case "cidr":
+		return field + " 不是合法的 CIDR，如 192.168.1.0/24"
+	case "protocol":
+		return field + " 必须是 TCP 或 UDP"
+	default:
+		return fmt.Sprintf("%s 校验失败（%s）", field, fe.Tag())
+	}
+}